@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -31,13 +32,129 @@ type BackupSpec struct {
 	// Schedule defines when to perform backups (cron format)
 	Schedule string `json:"schedule"`
 
-	// Retention defines how long to keep backups
-	Retention string `json:"retention,omitempty"`
+	// Retention prunes older backups after this one completes: the reconciler deletes
+	// any backup under StorageLocation no longer covered by the policy, and (for the
+	// minio/s3 provider) syncs an equivalent bucket lifecycle rule so pruning still
+	// happens even if the operator is offline. A nil Retention disables both.
+	// +optional
+	Retention *RetentionPolicy `json:"retention,omitempty"`
 
 	// StorageLocation defines where to store backups
 	StorageLocation StorageLocation `json:"storageLocation"`
+
+	// PackagingMode selects how backed-up resources are laid out in storage.
+	// PerResource (the default) writes one JSON object per resource, as every backup
+	// has always done. TarballPerNamespace and SingleTarball instead stream resources
+	// into a tar archive (one per namespace, or one for the whole backup) uploaded
+	// through a single PutObject call with an unknown size, so the archive is never
+	// buffered fully in memory; see packagingState.upload. Not supported together with
+	// Source.Deduplicate, which depends on one object per resource to dedupe against.
+	// +kubebuilder:validation:Enum=PerResource;TarballPerNamespace;SingleTarball
+	// +optional
+	PackagingMode PackagingMode `json:"packagingMode,omitempty"`
+
+	// Encryption enables client-side envelope encryption of the manifest tarball,
+	// independent of any server-side encryption StorageLocation.Encryption configures
+	// on the storage backend itself. Each backup generates its own random AES-256
+	// data encryption key (DEK) used to seal the tarball with AES-256-GCM; the DEK is
+	// then wrapped under the KEK selected by Provider and stored alongside the
+	// ciphertext, so a reader needs both the backup's objects and the KEK to recover
+	// anything. Only supported together with PackagingMode SingleTarball, since the
+	// GCM authentication tag needs the whole archive to be known up front.
+	// +optional
+	Encryption *BackupEncryptionConfig `json:"encryption,omitempty"`
+
+	// ItemActions lists plugins (see pkg/plugin) to run against every matching
+	// resource as it is backed up, in order. A plugin's Applies decides which
+	// resources it runs against; Execute can modify the resource, skip it
+	// entirely, or request additional resources be backed up alongside it.
+	// Mirrors Velero's BackupItemAction plugin model.
+	// +optional
+	ItemActions []ItemActionRef `json:"itemActions,omitempty"`
+}
+
+// BackupEncryptionProvider selects how BackupEncryptionConfig.KeySecretRef is
+// interpreted as a key-encryption key (KEK) for wrapping a backup's data key.
+type BackupEncryptionProvider string
+
+const (
+	// BackupEncryptionProviderAESGCM treats KeySecretRef's "key" field as a raw
+	// 32-byte AES-256 key and wraps the data key with AES-256-GCM.
+	BackupEncryptionProviderAESGCM BackupEncryptionProvider = "aes-gcm"
+	// BackupEncryptionProviderAge treats KeySecretRef's "recipients" field as one or
+	// more age recipient public keys (one per line) and wraps the data key so any
+	// matching age identity can unwrap it, making it straightforward to grant or
+	// revoke access to past backups by adding or removing recipients going forward.
+	BackupEncryptionProviderAge BackupEncryptionProvider = "age"
+)
+
+// BackupEncryptionConfig selects client-side envelope encryption for a Backup's
+// manifest tarball.
+type BackupEncryptionConfig struct {
+	// Provider selects how the per-backup data key is wrapped.
+	// +kubebuilder:validation:Enum=aes-gcm;age
+	Provider BackupEncryptionProvider `json:"provider"`
+
+	// KeySecretRef points at the Secret holding the key-encryption key: a raw
+	// 32-byte key under "key" for aes-gcm, or one or more age recipient public keys
+	// (one per line) under "recipients" for age.
+	KeySecretRef corev1.SecretReference `json:"keySecretRef"`
+}
+
+// RetentionPolicy bounds how many backups are kept under a bucket. Keep* fields
+// compose: a backup is kept if it is covered by any one of them, so e.g. KeepLast: 3,
+// KeepDaily: 7 keeps the 3 most recent backups plus one per day for the last 7 days
+// that had a backup at all.
+type RetentionPolicy struct {
+	// MaxAge prunes any backup older than this duration outright, before the Keep*
+	// windows below are considered - it is a hard ceiling, not a Keep* bucket, so a
+	// backup it covers is removed even if it would also have been the most recent in
+	// its KeepDaily/KeepWeekly/KeepMonthly window.
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+
+	// MaxCount caps the total number of backups kept, regardless of age: once more
+	// than MaxCount backups exist, the oldest are pruned until the cap holds, after
+	// MaxAge and the Keep* windows below have already been applied.
+	// +optional
+	MaxCount int32 `json:"maxCount,omitempty"`
+
+	// KeepLast retains the N most recent backups regardless of age.
+	// +optional
+	KeepLast int32 `json:"keepLast,omitempty"`
+
+	// KeepDaily retains the most recent backup for each of the last N distinct
+	// calendar days that had a backup.
+	// +optional
+	KeepDaily int32 `json:"keepDaily,omitempty"`
+
+	// KeepWeekly retains the most recent backup for each of the last N distinct ISO
+	// weeks that had a backup.
+	// +optional
+	KeepWeekly int32 `json:"keepWeekly,omitempty"`
+
+	// KeepMonthly retains the most recent backup for each of the last N distinct
+	// calendar months that had a backup.
+	// +optional
+	KeepMonthly int32 `json:"keepMonthly,omitempty"`
 }
 
+// PackagingMode selects how BackupSpec lays out backed-up resources in storage.
+type PackagingMode string
+
+const (
+	// PackagingModePerResource writes one storage object per resource. This is the
+	// default when PackagingMode is unset.
+	PackagingModePerResource PackagingMode = "PerResource"
+	// PackagingModeTarballPerNamespace streams every resource backed up from a
+	// namespace into a single tar archive instead of one object per resource.
+	PackagingModeTarballPerNamespace PackagingMode = "TarballPerNamespace"
+	// PackagingModeSingleTarball streams every resource backed up by the whole run
+	// (every namespace, cluster-scoped resource, and IncludeCRDs entry) into one tar
+	// archive.
+	PackagingModeSingleTarball PackagingMode = "SingleTarball"
+)
+
 // BackupSource defines the source of the backup
 type BackupSource struct {
 	// Namespaces to backup. If empty, backs up all namespaces
@@ -63,8 +180,256 @@ type BackupSource struct {
 	// ExcludeNamespaces specifies namespaces to exclude from backup
 	// Useful when backing up all namespaces but want to skip system namespaces
 	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+
+	// SnapshotVolumes requests a CSI VolumeSnapshot for every backed-up PVC, in addition
+	// to backing up the PVC manifest. The PVC's StorageClass is not checked for a CSI
+	// driver before the VolumeSnapshot is created.
+	// Clusters without the snapshot.storage.k8s.io CRDs installed are unaffected:
+	// snapshot creation is skipped with a status message instead of failing the backup.
+	// +optional
+	SnapshotVolumes bool `json:"snapshotVolumes,omitempty"`
+
+	// VolumeSnapshotClassMapping maps a StorageClass name to the VolumeSnapshotClass
+	// to use when snapshotting PVCs provisioned by that StorageClass. A "default" key
+	// is used as a fallback for StorageClasses with no explicit entry.
+	// +optional
+	VolumeSnapshotClassMapping map[string]string `json:"volumeSnapshotClassMapping,omitempty"`
+
+	// CSISnapshotTimeout bounds how long the backup waits for a VolumeSnapshot to
+	// become ready before recording it as not-yet-ready and proceeding with the
+	// backup anyway. Defaults to snapshotReadyTimeout (5 minutes) when unset.
+	// +optional
+	CSISnapshotTimeout *metav1.Duration `json:"csiSnapshotTimeout,omitempty"`
+
+	// VolumePolicy routes each backed-up PVC to a snapshot, fs-backup, or skip action
+	// based on the first matching rule, instead of the single repo-wide SnapshotVolumes
+	// toggle. Rules are evaluated in order; a PVC matching no rule falls back to
+	// SnapshotVolumes' legacy behavior (snapshot if true, skip if false). This lets a
+	// mixed cluster snapshot PVCs on CSI-capable StorageClasses while falling back to a
+	// file-system copy for the rest. The resolved decision for each PVC is recorded in
+	// Status.VolumeBackups.
+	// +optional
+	VolumePolicy []VolumePolicyRule `json:"volumePolicy,omitempty"`
+
+	// Hooks quiesce matching workloads for consistency before resources in their
+	// namespace are uploaded, and un-quiesce them again afterwards.
+	// +optional
+	Hooks []BackupHook `json:"hooks,omitempty"`
+
+	// IncludeCRDs lists additional, arbitrary resource types to back up via the
+	// discovery/dynamic client instead of a hardcoded, typed handler. Each entry is
+	// "group/version/Kind", e.g. "cert-manager.io/v1/Certificate"; core resources
+	// use an empty group, e.g. "/v1/ConfigMap". Namespace-scoped kinds are backed
+	// up for every namespace selected by this BackupSource; cluster-scoped kinds
+	// are backed up once regardless of IncludeClusterResources.
+	// +optional
+	IncludeCRDs []string `json:"includeCRDs,omitempty"`
+
+	// Deduplicate stores each resource as a content-addressed chunk under a stable
+	// chunks/ prefix shared by every backup of this Backup's storage bucket, instead
+	// of one object per resource under the timestamped backup path. A resource whose
+	// serialized content is unchanged since a previous backup reuses that backup's
+	// chunk instead of being uploaded again; only a small per-run manifest mapping
+	// resource paths to chunk hashes is written under the timestamped path. See
+	// Backup.Status.DedupStats for the resulting dedup ratio.
+	// +optional
+	Deduplicate bool `json:"deduplicate,omitempty"`
+
+	// ExcludedResources skips individual resources regardless of ResourceTypes.
+	// Each entry is either a bare resource type ("secrets", skipping every secret)
+	// or "type/name" ("secrets/db-creds", skipping a single named resource); both
+	// support glob patterns (e.g. "secrets/*-token"). It is invalid for the same
+	// resource type to appear in both ResourceTypes and ExcludedResources.
+	// +optional
+	ExcludedResources []string `json:"excludedResources,omitempty"`
+
+	// OrLabelSelectors, if set, takes precedence over LabelSelector: a resource is
+	// backed up if it matches any one of these selectors, instead of requiring a
+	// single selector to match. Useful for backing up resources belonging to any of
+	// several unrelated teams or apps in one Backup.
+	// +optional
+	OrLabelSelectors []metav1.LabelSelector `json:"orLabelSelectors,omitempty"`
+}
+
+// BackupHook quiesces the pods matched by PodSelector before backing up their
+// namespace, and reverses the quiescing once the namespace backup is done.
+type BackupHook struct {
+	// PodSelector matches the pods this hook applies to
+	PodSelector *metav1.LabelSelector `json:"podSelector"`
+
+	// IncludedNamespaces restricts this hook to the listed namespaces out of the
+	// ones the Backup already selects. Empty means the hook applies to every
+	// namespace this Backup backs up, matching the pre-existing behavior.
+	// +optional
+	IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
+
+	// Container is the container to exec into. Defaults to the pod's first container.
+	// Used by PreBackupExec/PostBackupExec; ignored by PreHooks/PostHooks entries,
+	// which each carry their own Container.
+	// +optional
+	Container string `json:"container,omitempty"`
+
+	// PreBackupExec is the command run inside Container before the namespace is backed
+	// up, when QuiesceMode is "exec" (e.g. to flush a database or freeze a filesystem).
+	// Ignored when PreHooks is set.
+	// +optional
+	PreBackupExec []string `json:"preBackupExec,omitempty"`
+
+	// PostBackupExec is the command run inside Container after the namespace backup
+	// completes, when QuiesceMode is "exec" (e.g. to unfreeze a filesystem).
+	// Ignored when PostHooks is set.
+	// +optional
+	PostBackupExec []string `json:"postBackupExec,omitempty"`
+
+	// PreHooks runs each entry in order before the namespace is backed up, instead of
+	// the single PreBackupExec command, for hooks that need more than one step (e.g. a
+	// pg_dump in one container followed by a filesystem freeze in another) with
+	// per-step container, timeout, and error handling. Ignored unless QuiesceMode is
+	// "exec". Empty means use PreBackupExec instead.
+	// +optional
+	PreHooks []ExecHook `json:"preHooks,omitempty"`
+
+	// PostHooks is the PreHooks equivalent run after the namespace backup completes,
+	// taking the place of PostBackupExec. Empty means use PostBackupExec instead.
+	// +optional
+	PostHooks []ExecHook `json:"postHooks,omitempty"`
+
+	// QuiesceMode selects how matching workloads are quiesced.
+	// +kubebuilder:validation:Enum=exec;scaleDown;none
+	QuiesceMode string `json:"quiesceMode,omitempty"`
+
+	// Timeout bounds how long a single exec hook invocation may run before it is
+	// treated as failed. Defaults to no timeout (bounded only by the backup's own
+	// context) when unset. Applies to PreBackupExec/PostBackupExec; each ExecHook in
+	// PreHooks/PostHooks carries its own Timeout instead.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// OnError controls whether a failed exec hook invocation fails the backup.
+	// Defaults to Continue: the failure is recorded in HookResults and the backup
+	// proceeds. Applies to PreBackupExec/PostBackupExec; each ExecHook in
+	// PreHooks/PostHooks carries its own OnError instead.
+	// +kubebuilder:validation:Enum=Fail;Continue
+	// +optional
+	OnError string `json:"onError,omitempty"`
 }
 
+// ExecHook is a single ordered exec invocation within BackupHook.PreHooks or
+// PostHooks, letting a hook run more than one command with its own container,
+// timeout, and error handling per step instead of sharing BackupHook's single
+// Container/Timeout/OnError across one command.
+type ExecHook struct {
+	// Container is the container to exec into. Defaults to the pod's first container.
+	// +optional
+	Container string `json:"container,omitempty"`
+
+	// Command is the command and arguments to run inside Container.
+	Command []string `json:"command"`
+
+	// OnError controls whether a failed invocation of this step fails the backup.
+	// Defaults to Continue: the failure is recorded in HookResults and the
+	// remaining steps still run.
+	// +kubebuilder:validation:Enum=Fail;Continue
+	// +optional
+	OnError string `json:"onError,omitempty"`
+
+	// Timeout bounds how long this invocation may run before it is treated as
+	// failed. Defaults to no timeout (bounded only by the backup's own context).
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// Quiesce modes supported by BackupHook.QuiesceMode.
+const (
+	// QuiesceModeExec runs PreBackupExec/PostBackupExec inside the running pod.
+	QuiesceModeExec = "exec"
+	// QuiesceModeScaleDown scales the pod's owning Deployment/StatefulSet to zero for
+	// the duration of the namespace backup, then restores its original replica count.
+	QuiesceModeScaleDown = "scaleDown"
+	// QuiesceModeNone disables quiescing; the hook is skipped and no HookResult is
+	// recorded for it.
+	QuiesceModeNone = "none"
+)
+
+// BackupHook.OnError values.
+const (
+	// HookOnErrorFail aborts the backup when the hook invocation fails.
+	HookOnErrorFail = "Fail"
+	// HookOnErrorContinue records the failure and lets the backup proceed.
+	HookOnErrorContinue = "Continue"
+)
+
+// VolumePolicyRule matches a PVC against Conditions and, for the first rule a PVC
+// matches, applies Action. Rules are evaluated in spec order; a PVC matching no rule
+// falls back to BackupSource.SnapshotVolumes' legacy snapshot-or-skip behavior.
+type VolumePolicyRule struct {
+	// Conditions a PVC must satisfy for this rule to match. A zero-valued field within
+	// Conditions is not checked, so a rule can match on as few or as many dimensions as
+	// needed (e.g. StorageClass alone, or StorageClass plus Capacity).
+	Conditions VolumeConditions `json:"conditions,omitempty"`
+
+	// Action to take for a PVC matching Conditions.
+	Action VolumeAction `json:"action"`
+}
+
+// VolumeConditions describes the PVC attributes a VolumePolicyRule can match on.
+type VolumeConditions struct {
+	// StorageClass matches pvc.Spec.StorageClassName exactly.
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// Capacity matches pvc.Spec.Resources.Requests[storage] against an inclusive
+	// "min,max" range, e.g. "10Gi,100Gi". Either bound may be omitted (",100Gi" or
+	// "10Gi,") to leave that side unbounded.
+	// +optional
+	Capacity string `json:"capacity,omitempty"`
+
+	// CSIDriver matches the CSI driver backing pvc's StorageClass, resolved from the
+	// cluster's StorageClass object at evaluation time.
+	// +optional
+	CSIDriver string `json:"csiDriver,omitempty"`
+
+	// AccessModes matches if pvc.Spec.AccessModes contains every mode listed here.
+	// +optional
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+
+	// PodMounted matches on whether any running pod currently mounts the PVC. Nil
+	// means this condition is not checked.
+	// +optional
+	PodMounted *bool `json:"podMounted,omitempty"`
+}
+
+// VolumeAction is the outcome a matching VolumePolicyRule applies to a PVC.
+type VolumeAction struct {
+	// Type selects how the PVC's data is backed up.
+	// +kubebuilder:validation:Enum=snapshot;fs-backup;data-mover;skip
+	Type string `json:"type"`
+
+	// Parameters carries action-specific configuration, e.g. the VolumeSnapshotClass
+	// for a "snapshot" action or a container image override for "fs-backup".
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// VolumeAction.Type values.
+const (
+	// VolumeActionSnapshot takes a CSI VolumeSnapshot of the PVC.
+	VolumeActionSnapshot = "snapshot"
+	// VolumeActionFSBackup copies the PVC's data via a per-node DaemonSet when CSI
+	// snapshotting is unavailable or undesired for that volume, writing it as a single
+	// tarball with no deduplication across PVCs or backup runs.
+	VolumeActionFSBackup = "fs-backup"
+	// VolumeActionDataMover copies the PVC's data the same way VolumeActionFSBackup
+	// does, but splits it into content-addressed chunks deduplicated across every PVC
+	// and backup run sharing a bucket, the way Restic and Kopia store file data.
+	// Prefer this over VolumeActionFSBackup unless the storage backend cannot afford
+	// the extra per-chunk ListObjects round trip this dedup check costs.
+	VolumeActionDataMover = "data-mover"
+	// VolumeActionSkip leaves the PVC's data out of the backup entirely (the PVC
+	// manifest itself is still backed up).
+	VolumeActionSkip = "skip"
+)
+
 // StorageLocation defines backup storage configuration
 type StorageLocation struct {
 	// Provider (e.g., "minio", "s3", "gcs")
@@ -76,11 +441,331 @@ type StorageLocation struct {
 	// Endpoint URL (for Minio)
 	Endpoint string `json:"endpoint,omitempty"`
 
+	// Secure forces TLS when talking to Endpoint. Ignored by providers that infer it
+	// from the endpoint scheme (Minio) or have no network endpoint (local).
+	// +optional
+	Secure bool `json:"secure,omitempty"`
+
+	// CredentialsRef points at a Secret holding the access/secret keys (and optionally
+	// a session token and CA bundle) instead of embedding them in the spec. Preferred
+	// over AccessKey/SecretKey.
+	// +optional
+	CredentialsRef *CredentialsReference `json:"credentialsRef,omitempty"`
+
 	// AccessKey for Minio authentication (for workshop simplicity)
+	// Deprecated: use CredentialsRef instead.
 	AccessKey string `json:"accessKey,omitempty"`
 
 	// SecretKey for Minio authentication (for workshop simplicity)
+	// Deprecated: use CredentialsRef instead.
 	SecretKey string `json:"secretKey,omitempty"`
+
+	// Encryption configures server-side encryption for objects written to this
+	// location. Only honored by the minio/s3 provider; other providers ignore it.
+	// Restore uses the same StorageLocation, so presenting the same Encryption block
+	// (and, for SSE-C, the same KeyRef secret) is all that is required to read objects
+	// back.
+	// +optional
+	Encryption *EncryptionConfig `json:"encryption,omitempty"`
+
+	// ObjectLock configures per-object WORM retention applied to every object written
+	// through this location, for ransomware-resistant backups that hold even against a
+	// compromised operator. Only honored by the minio/s3 provider, and only takes
+	// effect on a bucket that already has Object Locking enabled -- minio-go has no way
+	// to enable it on an existing bucket.
+	// +optional
+	ObjectLock *ObjectLockConfig `json:"objectLock,omitempty"`
+
+	// ReplicaTargets fans every object this StorageLocation writes out to one or more
+	// additional StorageLocations once the primary write succeeds, for redundancy
+	// across sites or providers (e.g. a primary minio bucket replicated to a gcs
+	// bucket in another region). A replica write failure is recorded per-target on
+	// BackupStatus.Replicas and does not fail the backup.
+	// +optional
+	ReplicaTargets []ReplicaTarget `json:"replicaTargets,omitempty"`
+
+	// TLS configures how the minio provider verifies (or, for mTLS, authenticates to)
+	// Endpoint, beyond the plain CA-bundle-via-CredentialsRef support every provider
+	// already has. Only honored by the minio provider.
+	// +optional
+	TLS *StorageTLSConfig `json:"tls,omitempty"`
+
+	// CredentialsChain configures an ordered fallback chain of credential sources for
+	// the minio provider, tried in declaration order (starting with CredentialsRef/
+	// AccessKey+SecretKey, ahead of every link here) until one produces usable
+	// credentials, so the operator can run outside single-cluster workshop mode
+	// against real S3/GCS/AliOSS backends that expect IAM-role or STS-based auth.
+	// Only honored by the minio provider.
+	// +optional
+	CredentialsChain *CredentialsChainConfig `json:"credentialsChain,omitempty"`
+
+	// AdminEndpoint is the MinIO admin API endpoint used by a Restore's preflight
+	// health check (see storage.RunPreflight). Defaults to Endpoint when unset. Only
+	// honored by the minio provider; other providers always use the
+	// BucketExists-only fallback check.
+	// +optional
+	AdminEndpoint string `json:"adminEndpoint,omitempty"`
+
+	// AdminCredentialsRef points at a Secret holding MinIO admin API credentials for
+	// the preflight health check. Defaults to CredentialsRef when unset.
+	// +optional
+	AdminCredentialsRef *CredentialsReference `json:"adminCredentialsRef,omitempty"`
+}
+
+// StorageTLSConfig configures TLS for a StorageLocation's Endpoint.
+type StorageTLSConfig struct {
+	// Enabled forces TLS independent of Secure or an https:// Endpoint. When unset,
+	// the provider keeps auto-detecting TLS from Secure/the endpoint scheme the way it
+	// always has.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// InsecureSkipVerify disables server certificate verification. Only ever useful
+	// against a known-trusted endpoint in development; never set this in production.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// CABundleSecretRef points at a Secret holding a PEM-encoded CA bundle to trust,
+	// independent of CredentialsReference.CABundleField, so a CA bundle can be
+	// configured without routing credentials through CredentialsRef too (e.g. while
+	// still using the deprecated inline AccessKey/SecretKey fields).
+	// +optional
+	CABundleSecretRef *StorageCABundleReference `json:"caBundleSecretRef,omitempty"`
+
+	// ClientCertSecretRef points at a Secret holding a PEM-encoded client certificate
+	// and private key, for storage endpoints that require mutual TLS.
+	// +optional
+	ClientCertSecretRef *StorageClientCertReference `json:"clientCertSecretRef,omitempty"`
+}
+
+// StorageCABundleReference points at a Secret holding a PEM-encoded CA bundle.
+type StorageCABundleReference struct {
+	// Name of the Secret.
+	Name string `json:"name"`
+
+	// Namespace of the Secret. Defaults to the Backup/Restore's own namespace, and
+	// must appear in the operator's allowed-namespaces list either way.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// CABundleField is the key in the Secret's data holding the PEM-encoded CA bundle.
+	// Defaults to "ca.crt".
+	// +optional
+	CABundleField string `json:"caBundleField,omitempty"`
+}
+
+// StorageClientCertReference points at a Secret holding a PEM-encoded client
+// certificate and private key for mutual TLS to a storage endpoint.
+type StorageClientCertReference struct {
+	// Name of the Secret.
+	Name string `json:"name"`
+
+	// Namespace of the Secret. Defaults to the Backup/Restore's own namespace, and
+	// must appear in the operator's allowed-namespaces list either way.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// CertField is the key in the Secret's data holding the PEM-encoded client
+	// certificate. Defaults to "tls.crt".
+	// +optional
+	CertField string `json:"certField,omitempty"`
+
+	// KeyField is the key in the Secret's data holding the PEM-encoded private key.
+	// Defaults to "tls.key".
+	// +optional
+	KeyField string `json:"keyField,omitempty"`
+}
+
+// CredentialsChainConfig enables additional links in the credentials fallback chain
+// beyond CredentialsRef/AccessKey+SecretKey, tried in the field order below after the
+// static source. A request is satisfied by the first link that produces usable
+// credentials; later links never run once one succeeds.
+type CredentialsChainConfig struct {
+	// EnvAWS tries AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY/AWS_SESSION_TOKEN read from
+	// the operator process's own environment.
+	// +optional
+	EnvAWS bool `json:"envAWS,omitempty"`
+
+	// EnvMinio tries MINIO_ROOT_USER/MINIO_ROOT_PASSWORD read from the operator
+	// process's own environment.
+	// +optional
+	EnvMinio bool `json:"envMinio,omitempty"`
+
+	// IAM tries the EC2/ECS/EKS instance metadata credentials endpoint.
+	// +optional
+	IAM *IAMCredentialsSource `json:"iam,omitempty"`
+
+	// WebIdentity tries an IRSA-style STS AssumeRoleWithWebIdentity exchange using a
+	// projected service account token.
+	// +optional
+	WebIdentity *WebIdentityCredentialsSource `json:"webIdentity,omitempty"`
+
+	// AssumeRole tries an STS AssumeRole exchange.
+	// +optional
+	AssumeRole *AssumeRoleCredentialsSource `json:"assumeRole,omitempty"`
+}
+
+// IAMCredentialsSource configures the EC2/ECS/EKS instance-role chain link.
+type IAMCredentialsSource struct {
+	// Endpoint overrides the IAM credentials endpoint, e.g. for a non-default
+	// regional STS endpoint or a test double. Empty uses the provider's own
+	// auto-detection of the instance metadata service.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// WebIdentityCredentialsSource configures the IRSA/STS-web-identity chain link.
+type WebIdentityCredentialsSource struct {
+	// STSEndpoint is the STS endpoint to exchange the web identity token against.
+	STSEndpoint string `json:"stsEndpoint"`
+
+	// RoleARN is the role to assume.
+	// +optional
+	RoleARN string `json:"roleARN,omitempty"`
+
+	// RoleSessionName identifies the assumed-role session.
+	// +optional
+	RoleSessionName string `json:"roleSessionName,omitempty"`
+
+	// TokenFile is the path to the projected service account token. Defaults to the
+	// AWS_WEB_IDENTITY_TOKEN_FILE environment variable, which is how EKS IRSA mounts
+	// it into every pod by default.
+	// +optional
+	TokenFile string `json:"tokenFile,omitempty"`
+}
+
+// AssumeRoleCredentialsSource configures the STS AssumeRole chain link.
+type AssumeRoleCredentialsSource struct {
+	// STSEndpoint is the STS endpoint to assume the role against.
+	STSEndpoint string `json:"stsEndpoint"`
+
+	// RoleARN is the role to assume.
+	RoleARN string `json:"roleARN"`
+
+	// RoleSessionName identifies the assumed-role session.
+	// +optional
+	RoleSessionName string `json:"roleSessionName,omitempty"`
+
+	// ExternalID is passed through to AssumeRole for cross-account access that
+	// requires one.
+	// +optional
+	ExternalID string `json:"externalID,omitempty"`
+}
+
+// ReplicaTarget is an additional StorageLocation a Backup's objects are fanned out to
+// after every successful write to the primary StorageLocation.
+type ReplicaTarget struct {
+	// Name identifies this replica in BackupStatus.Replicas. Must be unique among a
+	// StorageLocation's ReplicaTargets.
+	Name string `json:"name"`
+
+	// StorageLocation is the replica's own provider, bucket, and credentials. It may
+	// point at a different provider entirely than the primary.
+	StorageLocation StorageLocation `json:"storageLocation"`
+}
+
+// EncryptionMode selects the server-side encryption method used by EncryptionConfig.
+type EncryptionMode string
+
+const (
+	// EncryptionModeNone disables server-side encryption. This is the default.
+	EncryptionModeNone EncryptionMode = "None"
+	// EncryptionModeSSEC encrypts objects with a customer-supplied key (SSE-C). The
+	// same key must be presented on every subsequent read, including restores.
+	EncryptionModeSSEC EncryptionMode = "SSE-C"
+	// EncryptionModeSSEKMS encrypts objects with a key managed by the storage
+	// provider's KMS (SSE-KMS), identified by EncryptionConfig.KMSKeyID.
+	EncryptionModeSSEKMS EncryptionMode = "SSE-KMS"
+)
+
+// EncryptionConfig selects server-side encryption for a StorageLocation.
+type EncryptionConfig struct {
+	// Mode selects the SSE method.
+	// +kubebuilder:validation:Enum=None;SSE-C;SSE-KMS
+	// +optional
+	Mode EncryptionMode `json:"mode,omitempty"`
+
+	// KeyRef points at a Secret holding the 32-byte customer key used for SSE-C.
+	// Required when Mode is SSE-C; ignored otherwise.
+	// +optional
+	KeyRef *EncryptionKeyReference `json:"keyRef,omitempty"`
+
+	// KMSKeyID is the key management service key ID used to encrypt objects under
+	// SSE-KMS. Required when Mode is SSE-KMS; ignored otherwise.
+	// +optional
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+}
+
+// EncryptionKeyReference points at a Secret holding an SSE-C customer key.
+type EncryptionKeyReference struct {
+	// Name of the Secret.
+	Name string `json:"name"`
+
+	// Namespace of the Secret. Defaults to the Backup/Restore's own namespace, and
+	// must appear in the operator's allowed-namespaces list either way.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// KeyField is the key in the Secret's data holding the raw 32-byte encryption key.
+	// Defaults to "key".
+	// +optional
+	KeyField string `json:"keyField,omitempty"`
+}
+
+// ObjectLockMode selects how strictly an ObjectLockConfig's retention can be relaxed.
+type ObjectLockMode string
+
+const (
+	// ObjectLockModeGovernance can be bypassed by a principal with the
+	// s3:BypassGovernanceRetention permission, e.g. to correct a mistake.
+	ObjectLockModeGovernance ObjectLockMode = "GOVERNANCE"
+	// ObjectLockModeCompliance cannot be shortened or removed by anyone, including the
+	// bucket owner, until RetainDays elapses.
+	ObjectLockModeCompliance ObjectLockMode = "COMPLIANCE"
+)
+
+// ObjectLockConfig configures per-object retention applied on upload via
+// PutObjectOptions.Mode/RetainUntilDate.
+type ObjectLockConfig struct {
+	// Mode selects how strictly the retention period is enforced.
+	// +kubebuilder:validation:Enum=GOVERNANCE;COMPLIANCE
+	Mode ObjectLockMode `json:"mode"`
+
+	// RetainDays is how many days from upload each object may not be deleted or
+	// overwritten.
+	RetainDays int32 `json:"retainDays"`
+}
+
+// CredentialsReference points at a Secret holding storage credentials, so that
+// access/secret keys never need to be embedded in a Backup or Restore spec.
+type CredentialsReference struct {
+	// Name of the Secret
+	Name string `json:"name"`
+
+	// Namespace of the Secret. Defaults to the Backup/Restore's own namespace, and
+	// must appear in the operator's allowed-namespaces list either way.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// AccessKeyField is the key in the Secret's data holding the access key.
+	// Defaults to "accessKey".
+	// +optional
+	AccessKeyField string `json:"accessKeyField,omitempty"`
+
+	// SecretKeyField is the key in the Secret's data holding the secret key.
+	// Defaults to "secretKey".
+	// +optional
+	SecretKeyField string `json:"secretKeyField,omitempty"`
+
+	// SessionTokenField is the key in the Secret's data holding an optional session
+	// token, for providers that support temporary credentials.
+	// +optional
+	SessionTokenField string `json:"sessionTokenField,omitempty"`
+
+	// CABundleField is the key in the Secret's data holding a PEM-encoded CA bundle,
+	// for trusting a self-signed storage endpoint.
+	// +optional
+	CABundleField string `json:"caBundleField,omitempty"`
 }
 
 // BackupStatus defines the observed state of Backup.
@@ -105,6 +790,211 @@ type BackupStatus struct {
 
 	// BackupPath is the path in storage where the backup is stored
 	BackupPath string `json:"backupPath,omitempty"`
+
+	// CompletionTime is when the backup reached a terminal phase (Completed or Failed).
+	// BackupSchedule uses it to decide which child Backups to garbage-collect first.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// VolumeSnapshots records the CSI VolumeSnapshot created for each PVC backed up
+	// with SnapshotVolumes enabled.
+	VolumeSnapshots []VolumeSnapshotRecord `json:"volumeSnapshots,omitempty"`
+
+	// VolumeSnapshotsTaken is len(VolumeSnapshots): how many CSI VolumeSnapshots this
+	// backup attempted.
+	// +optional
+	VolumeSnapshotsTaken int32 `json:"volumeSnapshotsTaken,omitempty"`
+
+	// VolumeSnapshotsCompleted is how many of VolumeSnapshots reported ReadyToUse
+	// before CSISnapshotTimeout elapsed.
+	// +optional
+	VolumeSnapshotsCompleted int32 `json:"volumeSnapshotsCompleted,omitempty"`
+
+	// HookResults records the outcome of each pre/post backup hook execution.
+	HookResults []HookResult `json:"hookResults,omitempty"`
+
+	// HookStats aggregates HookResults into counts so callers don't have to scan the
+	// full list to tell whether quiescing succeeded.
+	// +optional
+	HookStats *HookStats `json:"hookStats,omitempty"`
+
+	// DedupStats reports chunk reuse for this run when Source.Deduplicate is enabled.
+	// +optional
+	DedupStats *DeduplicationStats `json:"dedupStats,omitempty"`
+
+	// Encryption records how this backup's manifest tarball was encrypted when
+	// Spec.Encryption is set, so the restore path can decrypt deterministically
+	// without guessing the algorithm or nonce length used.
+	// +optional
+	Encryption *BackupEncryptionStatus `json:"encryption,omitempty"`
+
+	// DeletionRequestName is the BackupDeletionRequest tearing this Backup down, set
+	// once this Backup is marked for deletion (see BackupFinalizer) so repeated
+	// reconciles find the same request instead of creating a new one each time.
+	// +optional
+	DeletionRequestName string `json:"deletionRequestName,omitempty"`
+
+	// VolumeBackups records the VolumePolicy decision resolved for each PVC backed up,
+	// for observability into why a given volume was snapshotted, fs-backed-up, copied
+	// by the data mover, or skipped. Populated whenever persistentvolumeclaims are backed up, regardless of
+	// whether VolumePolicy or the legacy SnapshotVolumes toggle was used.
+	// +optional
+	VolumeBackups []VolumeBackupRecord `json:"volumeBackups,omitempty"`
+
+	// Replicas reports the sync status of each of StorageLocation.ReplicaTargets as
+	// observed during this backup run. Empty when the StorageLocation has no
+	// ReplicaTargets configured.
+	// +optional
+	Replicas []ReplicaStatus `json:"replicas,omitempty"`
+}
+
+// ReplicaPhase describes how up to date a single ReplicaTarget is with a backup's
+// primary StorageLocation.
+type ReplicaPhase string
+
+const (
+	// ReplicaPhaseSynced means every object this run wrote to the primary also
+	// reached this replica.
+	ReplicaPhaseSynced ReplicaPhase = "Synced"
+	// ReplicaPhaseDegraded means at least one object this run failed to reach this
+	// replica, even though the backup itself still succeeded against its primary
+	// StorageLocation.
+	ReplicaPhaseDegraded ReplicaPhase = "Degraded"
+)
+
+// ReplicaStatus reports one ReplicaTarget's sync outcome for a single backup run.
+type ReplicaStatus struct {
+	// Name matches the ReplicaTarget.Name this status is for.
+	Name string `json:"name"`
+
+	// Phase summarizes whether every object reached this replica this run.
+	// +optional
+	Phase ReplicaPhase `json:"phase,omitempty"`
+
+	// LastSyncedGeneration is the Backup's metadata.generation as of the last run in
+	// which every object reached this replica successfully.
+	// +optional
+	LastSyncedGeneration int64 `json:"lastSyncedGeneration,omitempty"`
+
+	// Error is the most recent replication failure for this target, set when Phase
+	// is Degraded.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// DeduplicationStats reports how effective content-addressed chunk storage was for a
+// single backup run with BackupSource.Deduplicate enabled.
+type DeduplicationStats struct {
+	// TotalResources is how many resources were considered for chunk storage.
+	TotalResources int32 `json:"totalResources"`
+
+	// ChunksWritten is how many of those resources produced a new chunk, i.e. their
+	// content did not match any chunk written by a previous backup.
+	ChunksWritten int32 `json:"chunksWritten"`
+
+	// ChunksReused is how many resources matched a chunk a previous backup already
+	// wrote and were skipped instead of being uploaded again.
+	ChunksReused int32 `json:"chunksReused"`
+
+	// BytesWritten is the total serialized size of newly written chunks.
+	BytesWritten int64 `json:"bytesWritten"`
+
+	// BytesSaved is the total serialized size of resources that reused an existing
+	// chunk instead of being written again.
+	BytesSaved int64 `json:"bytesSaved"`
+}
+
+// BackupEncryptionStatus records how a Backup's manifest tarball was encrypted, so
+// the restore path can decrypt it deterministically.
+type BackupEncryptionStatus struct {
+	// Provider is the BackupEncryptionConfig.Provider used to wrap the data key.
+	Provider BackupEncryptionProvider `json:"provider"`
+
+	// KeyFingerprint is a SHA-256 hex digest of the wrapped data key, identifying
+	// which KEK/recipient decrypts this backup without revealing any key material.
+	KeyFingerprint string `json:"keyFingerprint"`
+
+	// NonceLength is the AES-GCM nonce length, in bytes, prepended to the ciphertext.
+	NonceLength int32 `json:"nonceLength"`
+
+	// ManifestPath is the object key of the encrypted manifest tarball.
+	ManifestPath string `json:"manifestPath"`
+
+	// WrappedDEKPath is the object key holding the KEK-wrapped data encryption key.
+	WrappedDEKPath string `json:"wrappedDEKPath"`
+}
+
+// HookResult records the outcome of a single BackupHook execution against a single pod.
+type HookResult struct {
+	// PodName is the pod the hook ran against
+	PodName string `json:"podName"`
+
+	// Namespace is the namespace of the pod (and, for scaleDown, the scaled workload)
+	Namespace string `json:"namespace"`
+
+	// Phase is "pre" or "post", identifying which half of the hook ran
+	Phase string `json:"phase"`
+
+	// QuiesceMode is the mode the hook ran under
+	QuiesceMode string `json:"quiesceMode"`
+
+	// Success indicates whether this hook execution succeeded
+	Success bool `json:"success"`
+
+	// Message carries the error or a stderr snippet when Success is false
+	Message string `json:"message,omitempty"`
+}
+
+// HookStats aggregates HookResult counts across an entire backup run.
+type HookStats struct {
+	// Attempted is how many pre/post hook executions ran, across every pod and phase.
+	Attempted int32 `json:"attempted"`
+
+	// Failed is how many of those executions did not succeed.
+	Failed int32 `json:"failed"`
+}
+
+// VolumeSnapshotRecord records the CSI VolumeSnapshot taken for one PVC during a backup.
+type VolumeSnapshotRecord struct {
+	// PVCName is the name of the PersistentVolumeClaim that was snapshotted
+	PVCName string `json:"pvcName"`
+
+	// Namespace is the namespace of the PersistentVolumeClaim and VolumeSnapshot
+	Namespace string `json:"namespace"`
+
+	// VolumeSnapshotName is the name of the created VolumeSnapshot object
+	VolumeSnapshotName string `json:"volumeSnapshotName"`
+
+	// VolumeSnapshotContentName is the name of the bound VolumeSnapshotContent
+	VolumeSnapshotContentName string `json:"volumeSnapshotContentName,omitempty"`
+
+	// SourceStorageClass is the PVC's StorageClassName at the time it was snapshotted,
+	// used to re-resolve a VolumeSnapshotClass (or a restore target's StorageClass
+	// remapping) without needing the original PVC manifest.
+	SourceStorageClass string `json:"sourceStorageClass,omitempty"`
+
+	// SnapshotHandle is the storage-side identifier reported by the CSI driver
+	SnapshotHandle string `json:"snapshotHandle,omitempty"`
+
+	// ReadyToUse indicates whether the snapshot was ready by the time the backup completed
+	ReadyToUse bool `json:"readyToUse"`
+}
+
+// VolumeBackupRecord records the VolumePolicy decision resolved for one PVC during a
+// backup.
+type VolumeBackupRecord struct {
+	// PVCName is the name of the PersistentVolumeClaim the decision was resolved for.
+	PVCName string `json:"pvcName"`
+
+	// Namespace is the namespace of the PersistentVolumeClaim.
+	Namespace string `json:"namespace"`
+
+	// Decision is the VolumeAction.Type applied: "snapshot", "fs-backup", "data-mover",
+	// or "skip".
+	Decision string `json:"decision"`
+
+	// Reason explains how Decision was reached, e.g. which rule matched or that it
+	// fell back to the legacy SnapshotVolumes toggle.
+	Reason string `json:"reason,omitempty"`
 }
 
 // BackupPhase represents the current phase of a backup
@@ -118,6 +1008,12 @@ const (
 	BackupPhaseFailed    BackupPhase = "Failed"
 )
 
+// BackupFinalizer blocks a Backup's deletion from the API server until a
+// BackupDeletionRequest has torn down its remote tarball/manifests and any tracked
+// VolumeSnapshot/VolumeSnapshotContent objects, so a plain kubectl delete never leaks
+// orphaned objects in the bucket.
+const BackupFinalizer = "backup.cnd.dk/backup-protection"
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 
@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupCatalogSpec defines the desired state of BackupCatalog
+type BackupCatalogSpec struct {
+	// StorageLocation is the bucket to catalog, usually the same StorageLocation used
+	// by the Backups being cataloged.
+	StorageLocation StorageLocation `json:"storageLocation"`
+
+	// PageSize caps how many BackupCatalogEntry items Status.Entries holds after a
+	// reconcile. Defaults to 50.
+	// +optional
+	PageSize int32 `json:"pageSize,omitempty"`
+
+	// PageToken resumes listing after the named backup. Set it to a previous
+	// Status.NextPageToken to page through a bucket with more backups than fit on one
+	// page.
+	// +optional
+	PageToken string `json:"pageToken,omitempty"`
+}
+
+// BackupCatalogEntry summarizes one backup found under a StorageLocation's backup
+// root, derived from the object key layout uploadResource writes rather than by
+// reading every object the backup contains.
+type BackupCatalogEntry struct {
+	// BackupName is the timestamped backup path segment, e.g. "2026-01-02T15-04-05".
+	BackupName string `json:"backupName"`
+
+	// Timestamp is BackupName parsed back into a time, when it matches the layout
+	// performBackup writes its backupPath with.
+	// +optional
+	Timestamp *metav1.Time `json:"timestamp,omitempty"`
+
+	// Namespaces lists the namespaces with at least one resource under this backup.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// ResourceCounts tracks how many objects were found per "<namespace>/<type>" or
+	// "cluster/<type>" key, matching the keys BackupStatus.ResourceCounts uses.
+	// +optional
+	ResourceCounts map[string]int32 `json:"resourceCounts,omitempty"`
+
+	// SizeBytes is always 0. Provider.ListObjects returns object keys only, not sizes,
+	// and computing a real total would mean a per-object stat call -- exactly the
+	// per-object listing cost this catalog exists to avoid.
+	// +optional
+	SizeBytes int64 `json:"sizeBytes,omitempty"`
+}
+
+// BackupCatalogStatus defines the observed state of BackupCatalog
+type BackupCatalogStatus struct {
+	// Entries holds one summary per backup found in this page.
+	// +optional
+	Entries []BackupCatalogEntry `json:"entries,omitempty"`
+
+	// NextPageToken is the PageToken to set on a subsequent reconcile to list the next
+	// page, or empty when this page reached the end of the bucket.
+	// +optional
+	NextPageToken string `json:"nextPageToken,omitempty"`
+
+	// RefreshTime is when Entries was last populated.
+	// +optional
+	RefreshTime *metav1.Time `json:"refreshTime,omitempty"`
+
+	// Message provides additional information about the current state
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Bucket",type=string,JSONPath=`.spec.storageLocation.bucket`
+// +kubebuilder:printcolumn:name="NextPageToken",type=string,JSONPath=`.status.nextPageToken`
+// +kubebuilder:printcolumn:name="RefreshTime",type=date,JSONPath=`.status.refreshTime`
+
+// BackupCatalog is the Schema for the backupcatalogs API. Reconciling a BackupCatalog
+// lists one page of backups out of a StorageLocation's backup root into
+// Status.Entries, without reading every resource object those backups contain, so
+// operators can enumerate historical backups at scale.
+type BackupCatalog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupCatalogSpec   `json:"spec,omitempty"`
+	Status BackupCatalogStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BackupCatalogList contains a list of BackupCatalog
+type BackupCatalogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BackupCatalog `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BackupCatalog{}, &BackupCatalogList{})
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupDeletionRequestSpec defines the desired state of BackupDeletionRequest.
+type BackupDeletionRequestSpec struct {
+	// BackupName is the Backup being torn down.
+	BackupName string `json:"backupName"`
+
+	// BackupNamespace is BackupName's namespace. Defaults to this request's own
+	// namespace.
+	// +optional
+	BackupNamespace string `json:"backupNamespace,omitempty"`
+
+	// BackupPath restricts teardown to a single historical backup path under
+	// BackupName's bucket instead of BackupName's current state. Set by
+	// enforceRetention when a retention policy prunes an old run out from under a
+	// Backup that is still otherwise active; the Backup CR and its current
+	// VolumeSnapshots are left untouched. Empty means "tear down the whole Backup",
+	// used for the finalizer-driven kubectl delete path.
+	// +optional
+	BackupPath string `json:"backupPath,omitempty"`
+}
+
+// BackupDeletionRequestPhase represents the current phase of a BackupDeletionRequest.
+type BackupDeletionRequestPhase string
+
+const (
+	BackupDeletionRequestPhaseNew        BackupDeletionRequestPhase = "New"
+	BackupDeletionRequestPhaseInProgress BackupDeletionRequestPhase = "InProgress"
+	BackupDeletionRequestPhaseCompleted  BackupDeletionRequestPhase = "Completed"
+	BackupDeletionRequestPhaseFailed     BackupDeletionRequestPhase = "Failed"
+)
+
+// BackupDeletionRequestStatus defines the observed state of BackupDeletionRequest.
+type BackupDeletionRequestStatus struct {
+	// Phase is New until the first reconcile, InProgress while any step remains
+	// incomplete, Completed once every applicable step has succeeded, or Failed if a
+	// step errored on its most recent attempt (still retried on the next reconcile).
+	// +optional
+	Phase BackupDeletionRequestPhase `json:"phase,omitempty"`
+
+	// CompletedSteps lists the steps (see the step* consts in
+	// internal/controller/backupdeletionrequest_controller.go) that have already
+	// succeeded, so a retry after a partial failure does not redo finished work.
+	// +optional
+	CompletedSteps []string `json:"completedSteps,omitempty"`
+
+	// StepErrors records the most recent error for any step that failed, keyed by
+	// step name, so a partial failure is visible without failing steps that already
+	// succeeded.
+	// +optional
+	StepErrors map[string]string `json:"stepErrors,omitempty"`
+
+	// CompletionTime is when Phase last became Completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Backup",type=string,JSONPath=`.spec.backupName`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+
+// BackupDeletionRequest tears a Backup down: its remote tarball/manifests, any
+// VolumeSnapshot/VolumeSnapshotContent objects recorded in its status, and finally the
+// Backup CR itself, retrying each step independently until every one has succeeded so
+// a transient failure partway through never leaves orphaned objects in the bucket.
+type BackupDeletionRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupDeletionRequestSpec   `json:"spec,omitempty"`
+	Status BackupDeletionRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BackupDeletionRequestList contains a list of BackupDeletionRequest
+type BackupDeletionRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BackupDeletionRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BackupDeletionRequest{}, &BackupDeletionRequestList{})
+}
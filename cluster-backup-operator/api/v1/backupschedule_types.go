@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConcurrencyPolicyMode governs what happens when a cron firing is due while a
+// previous child Backup created by the same schedule has not yet reached a
+// terminal phase (Completed or Failed), mirroring batch/v1 CronJob.
+type ConcurrencyPolicyMode string
+
+const (
+	// ConcurrencyPolicyAllow runs the new Backup alongside the still-running one.
+	// This is the default when ConcurrencyPolicy is empty.
+	ConcurrencyPolicyAllow ConcurrencyPolicyMode = "Allow"
+	// ConcurrencyPolicyForbid skips this firing entirely, leaving the running
+	// Backup to finish before the next firing is considered.
+	ConcurrencyPolicyForbid ConcurrencyPolicyMode = "Forbid"
+	// ConcurrencyPolicyReplace deletes the still-running Backup(s) and creates
+	// the new one in their place.
+	ConcurrencyPolicyReplace ConcurrencyPolicyMode = "Replace"
+)
+
+// BackupScheduleSpec defines the desired state of BackupSchedule.
+type BackupScheduleSpec struct {
+	// Schedule is the cron expression controlling when a new Backup is created
+	Schedule string `json:"schedule"`
+
+	// Pause stops new Backups from being created without deleting the BackupSchedule
+	Pause bool `json:"pause,omitempty"`
+
+	// ConcurrencyPolicy controls how a due firing is handled when a previous
+	// child Backup from this schedule has not yet reached Completed or Failed.
+	// One of Allow, Forbid, Replace. Empty behaves like Allow.
+	// +optional
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	ConcurrencyPolicy ConcurrencyPolicyMode `json:"concurrencyPolicy,omitempty"`
+
+	// MaxSuccessfulBackups is how many completed child Backups to retain, oldest first.
+	// 0 means unlimited.
+	MaxSuccessfulBackups int32 `json:"maxSuccessfulBackups,omitempty"`
+
+	// MaxFailedBackups is how many failed child Backups to retain, oldest first.
+	// 0 means unlimited.
+	MaxFailedBackups int32 `json:"maxFailedBackups,omitempty"`
+
+	// Template is the BackupSpec used to create each per-run Backup object.
+	// Its Schedule field is ignored; scheduling is owned by BackupSchedule.
+	Template BackupSpec `json:"template"`
+}
+
+// BackupScheduleStatus defines the observed state of BackupSchedule.
+type BackupScheduleStatus struct {
+	// LastScheduledBackupTime is when the most recent child Backup was created
+	LastScheduledBackupTime *metav1.Time `json:"lastScheduledBackupTime,omitempty"`
+
+	// LastSuccessfulBackupTime is when the most recent child Backup completed successfully
+	LastSuccessfulBackupTime *metav1.Time `json:"lastSuccessfulBackupTime,omitempty"`
+
+	// Message provides additional information about the current state
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Pause",type=boolean,JSONPath=`.spec.pause`
+// +kubebuilder:printcolumn:name="LastScheduledBackupTime",type=date,JSONPath=`.status.lastScheduledBackupTime`
+// +kubebuilder:printcolumn:name="LastSuccessfulBackupTime",type=date,JSONPath=`.status.lastSuccessfulBackupTime`
+
+// BackupSchedule is the Schema for the backupschedules API. It owns the
+// recurring cron schedule and creates one immutable Backup object per firing,
+// rather than mutating a single Backup object forever.
+type BackupSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupScheduleSpec   `json:"spec,omitempty"`
+	Status BackupScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BackupScheduleList contains a list of BackupSchedule.
+type BackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BackupSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BackupSchedule{}, &BackupScheduleList{})
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupStorageProviderSpec defines the desired state of BackupStorageProvider. A
+// StorageLocation with Provider set to "plugin://<name>" is resolved by looking up the
+// BackupStorageProvider named <name> in the Backup/Restore's namespace.
+type BackupStorageProviderSpec struct {
+	// SocketPath is the Unix domain socket the plugin process listens on, started by
+	// pkg/plugin/storage.Serve out-of-process. The operator must have this path
+	// mounted (e.g. a shared emptyDir between the operator and a plugin sidecar).
+	SocketPath string `json:"socketPath"`
+}
+
+// BackupStorageProviderStatus defines the observed state of BackupStorageProvider.
+type BackupStorageProviderStatus struct {
+	// Phase is "Ready" once the plugin has answered a HealthCheck RPC, "Unreachable"
+	// if the last attempt failed, or empty before the first check.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// Capabilities are the capability names the plugin advertised in its last
+	// Capabilities RPC response (e.g. "encryption"). A backup requiring a capability
+	// not listed here is refused rather than scheduled against a plugin that cannot
+	// honor it.
+	// +optional
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// LastHealthCheckTime is when Phase was last refreshed.
+	// +optional
+	LastHealthCheckTime *metav1.Time `json:"lastHealthCheckTime,omitempty"`
+
+	// Message carries the error from the last failed HealthCheck RPC.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// BackupStorageProvider.Status.Phase values.
+const (
+	BackupStorageProviderPhaseReady       = "Ready"
+	BackupStorageProviderPhaseUnreachable = "Unreachable"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Socket",type=string,JSONPath=`.spec.socketPath`
+
+// BackupStorageProvider registers an out-of-process storage backend plugin (served via
+// pkg/plugin/storage.Serve) so a StorageLocation can reference it as
+// "plugin://<name>" without the operator knowing about the backend at build time.
+type BackupStorageProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupStorageProviderSpec   `json:"spec,omitempty"`
+	Status BackupStorageProviderStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BackupStorageProviderList contains a list of BackupStorageProvider
+type BackupStorageProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BackupStorageProvider `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BackupStorageProvider{}, &BackupStorageProviderList{})
+}
@@ -33,6 +33,214 @@ type RestoreSpec struct {
 
 	// Options for restore behavior
 	Options RestoreOptions `json:"options,omitempty"`
+
+	// VolumeRestore controls restoration of PersistentVolumeClaim data from the CSI
+	// VolumeSnapshot/VolumeSnapshotContent metadata backed up alongside each PVC. Unset
+	// leaves PVCs restored as plain manifests with no dataSource, matching pre-existing
+	// behavior.
+	// +optional
+	VolumeRestore *VolumeRestoreOptions `json:"volumeRestore,omitempty"`
+
+	// RestoreVolumes controls provisioning a fresh cloud volume for each backed-up
+	// PersistentVolume from its recorded snapshot, independently of VolumeRestore's
+	// CSI VolumeSnapshot/PVC flow. nil means auto (provision when a VolumeSnapshotter
+	// is configured and the PV has a matching snapshot record, skip otherwise), true
+	// forces it on (failing validation if no VolumeSnapshotter is configured), and
+	// false always skips it. See pkg/volumesnapshotter.Snapshotter.
+	// +optional
+	RestoreVolumes *bool `json:"restoreVolumes,omitempty"`
+
+	// ConflictPolicy controls how an already-existing resource is handled, with
+	// per-resource-type overrides. Supersedes Target.ConflictResolution when set;
+	// Target.ConflictResolution remains the fallback for restores that don't set this.
+	// +optional
+	ConflictPolicy *ConflictPolicy `json:"conflictPolicy,omitempty"`
+
+	// ItemActions lists plugins (see pkg/plugin) to run against every matching
+	// resource before it is restored, in order. A plugin's Applies decides
+	// whether it runs against a given resource; RestoreReconciler.restoreResource
+	// records which ones touched it on RestoredResource.AppliedActions.
+	// +optional
+	ItemActions []ItemActionRef `json:"itemActions,omitempty"`
+
+	// Hooks run against resources as they're restored, matched by Selector, to let a
+	// workload finish initializing (wait-for-condition, init-container) or run a
+	// command once it's up (exec) - e.g. replaying a WAL or warming a cache after its
+	// data volume is back.
+	// +optional
+	Hooks []RestoreHook `json:"hooks,omitempty"`
+
+	// ResourceModifierRef points at a ConfigMap holding resource modifier rules
+	// (see internal/controller/restore_modifiers.go) applied to each resource, after
+	// ItemActions and the standard field cleanup, just before it is created or
+	// updated on the target cluster.
+	// +optional
+	ResourceModifierRef *ResourceModifierRef `json:"resourceModifierRef,omitempty"`
+}
+
+// ResourceModifierRef points at a ConfigMap holding resource modifier rules.
+type ResourceModifierRef struct {
+	// Name of the ConfigMap holding the rules, under the data key "modifiers.json".
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap. Defaults to the Restore's own namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// DryRun logs the diff each matching rule's patches would have produced instead
+	// of applying them, so rules can be validated against a real backup before they
+	// take effect.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// RestoreHook runs Type against every resource Selector matches, once that resource
+// has been created or updated by the restore.
+type RestoreHook struct {
+	// Selector identifies which restored resources this hook applies to.
+	Selector RestoreHookSelector `json:"selector"`
+
+	// Type selects how this hook runs. One of exec, init-container, wait-for-condition.
+	// +kubebuilder:validation:Enum=exec;init-container;wait-for-condition
+	Type string `json:"type"`
+
+	// Container is the container Command runs in (Type exec) or whose completion is
+	// awaited (Type init-container). Defaults to the pod's first container.
+	// +optional
+	Container string `json:"container,omitempty"`
+
+	// Command is the command exec'd inside Container once the pod is Ready. Required
+	// when Type is exec.
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// Condition is a JSONPath expression evaluated against the restored resource
+	// (e.g. "{.status.conditions[?(@.type=='Ready')].status}"); the hook is satisfied
+	// once it evaluates to a non-empty, non-false result. Required when Type is
+	// wait-for-condition.
+	// +optional
+	Condition string `json:"condition,omitempty"`
+
+	// Timeout bounds how long this hook waits for a Pod to become Ready, an
+	// init-container to finish, or Condition to be met, before the hook is recorded as
+	// failed. Defaults to 5 minutes (the controller's restoreHookDefaultTimeout).
+	// +optional
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// RestoreHookSelector matches the resources a RestoreHook applies to.
+type RestoreHookSelector struct {
+	// APIVersion of the resources this hook applies to, e.g. "v1" or "apps/v1".
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the resources this hook applies to, e.g. "Pod".
+	Kind string `json:"kind"`
+
+	// LabelSelector further restricts matches by label. Unset matches every resource
+	// of APIVersion/Kind.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// Namespaces restricts matches to these (target-cluster) namespaces. Empty matches
+	// every namespace.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// RestoreHook.Type values.
+const (
+	// RestoreHookTypeExec runs Command inside Container once the matched Pod is Ready.
+	RestoreHookTypeExec = "exec"
+	// RestoreHookTypeInitContainer waits for Container to finish in the matched Pod.
+	RestoreHookTypeInitContainer = "init-container"
+	// RestoreHookTypeWaitForCondition polls the matched resource until Condition
+	// evaluates to a non-empty, non-false result.
+	RestoreHookTypeWaitForCondition = "wait-for-condition"
+)
+
+// RestoreHookResult records the outcome of running one RestoreHook against one
+// restored resource.
+type RestoreHookResult struct {
+	// Kind of the resource the hook ran against.
+	Kind string `json:"kind"`
+
+	// Name of the resource the hook ran against.
+	Name string `json:"name"`
+
+	// Namespace of the resource the hook ran against.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Type is the RestoreHook.Type that ran.
+	Type string `json:"type"`
+
+	// Success indicates whether the hook completed within its timeout.
+	Success bool `json:"success"`
+
+	// Message explains a failure, or is empty on success.
+	Message string `json:"message,omitempty"`
+}
+
+// ItemActionRef selects a plugin.ItemAction by name to run against matching
+// resources during backup or restore, mirroring Velero's
+// BackupItemAction/RestoreItemAction plugin references.
+type ItemActionRef struct {
+	// Name identifies a built-in or plugin-registered ItemAction; see
+	// pkg/plugin.Registry.
+	Name string `json:"name"`
+
+	// Config is passed to the action's Configure call before each Execute, i.e.
+	// once for every resource the action applies to.
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// VolumeRestoreOptions controls whether and how PersistentVolumeClaims are restored
+// from the CSI VolumeSnapshot/VolumeSnapshotContent metadata
+// BackupReconciler.snapshotPersistentVolumeClaim uploads alongside each PVC manifest
+// when BackupSpec.Source.SnapshotVolumes is enabled.
+type VolumeRestoreOptions struct {
+	// RestorePVs enables volume data restoration via CSI snapshots: for every restored
+	// PersistentVolumeClaim with a matching backed-up VolumeSnapshot, the restore
+	// recreates the VolumeSnapshotContent and VolumeSnapshot in the target cluster and
+	// points the PVC's spec.dataSource at it before creating the PVC.
+	// +optional
+	RestorePVs *bool `json:"restorePVs,omitempty"`
+
+	// RestoreDataMoverVolumes enables volume data restoration from the content-addressed
+	// chunk manifests BackupReconciler.dataMoverPersistentVolumeClaim uploads for PVCs
+	// backed up with a VolumeActionDataMover policy action: once a restored PVC is
+	// mounted by a running pod, its chunks are fetched in order and extracted onto the
+	// volume via the fs-backup agent DaemonSet. Independent of RestorePVs, since a PVC
+	// is backed up with either a CSI VolumeSnapshot or a data-mover chunk manifest, not
+	// both.
+	// +optional
+	RestoreDataMoverVolumes *bool `json:"restoreDataMoverVolumes,omitempty"`
+
+	// SnapshotProvider identifies which CSI snapshot ecosystem the backup's
+	// VolumeSnapshotContents belong to (aws, gcp, azure, csi). Currently informational
+	// only: the restore recreates VolumeSnapshotContent.spec.driver exactly as backed
+	// up regardless of this value.
+	// +optional
+	// +kubebuilder:validation:Enum=aws;gcp;azure;csi
+	SnapshotProvider string `json:"snapshotProvider,omitempty"`
+
+	// VolumeSnapshotClassMapping remaps a backed-up VolumeSnapshot's
+	// VolumeSnapshotClassName to one that exists in the restore target cluster, keyed
+	// by the original class name. A "default" key is used as a fallback for class
+	// names with no explicit entry; an original class name with no entry at all (and
+	// no "default") is kept unchanged.
+	// +optional
+	VolumeSnapshotClassMapping map[string]string `json:"volumeSnapshotClassMapping,omitempty"`
+
+	// StorageClassMapping remaps a restored PersistentVolumeClaim's
+	// spec.storageClassName, keyed by the original class name, the same way
+	// VolumeSnapshotClassMapping remaps VolumeSnapshotClassName. Applied by the
+	// pvcVolumeAndStorageClassRemapAction built-in restore item action (see
+	// restore_itemactions.go). A "default" key is used as a fallback for class names
+	// with no explicit entry; an original class name with no entry at all (and no
+	// "default") is kept unchanged.
+	// +optional
+	StorageClassMapping map[string]string `json:"storageClassMapping,omitempty"`
 }
 
 // RestoreSource defines the backup source location
@@ -55,12 +263,87 @@ type RestoreTarget struct {
 	// ConflictResolution strategy when resources already exist: skip, overwrite, fail
 	ConflictResolution string `json:"conflictResolution,omitempty"`
 
-	// LabelSelector for filtering resources to restore
+	// LabelSelector for filtering resources to restore. A resource must match this
+	// selector (if OrLabelSelectors is also set, LabelSelector is ignored in favor of
+	// OR-of-selectors semantics there).
 	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
 
+	// OrLabelSelectors filters resources to restore by a set of selectors combined
+	// with OR: a resource matching any one of them is kept. Takes precedence over
+	// LabelSelector when both are set.
+	// +optional
+	OrLabelSelectors []metav1.LabelSelector `json:"orLabelSelectors,omitempty"`
+
 	// NamespaceMapping maps source namespaces to target namespaces
 	// Format: {"source-ns": "target-ns"}
 	NamespaceMapping map[string]string `json:"namespaceMapping,omitempty"`
+
+	// IncludedNamespaces restricts the restore to the source namespaces matching one
+	// of these entries (supports "*" and simple glob patterns, e.g. "dev-*"). Empty
+	// means all namespaces in the backup are candidates, same as ExcludedNamespaces
+	// not covering them. Exclusion in ExcludedNamespaces always wins.
+	// +optional
+	IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
+
+	// ExcludedNamespaces removes source namespaces from the restore regardless of
+	// IncludedNamespaces (supports "*" and simple glob patterns).
+	// +optional
+	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
+
+	// IncludedResources restricts the restore to resource types matching one of these
+	// entries (supports "*" and simple glob patterns, e.g. "config*"). Empty means all
+	// resource types in the backup are candidates.
+	// +optional
+	IncludedResources []string `json:"includedResources,omitempty"`
+
+	// ExcludedResources skips individual resources from the restore. Each entry is
+	// either a resource type (e.g. "secrets", matching the backup's on-disk folder
+	// name) to exclude the whole type, or "<resourceType>/<name>" to exclude a
+	// single resource of that type. Supports "*" and simple glob patterns (e.g.
+	// "secrets/db-*"). Always wins over IncludedResources.
+	ExcludedResources []string `json:"excludedResources,omitempty"`
+
+	// IncludeClusterResources restores cluster-scoped resources (ClusterRoles,
+	// ClusterRoleBindings, PersistentVolumes, StorageClasses) backed up under the
+	// backup's "cluster/" path, matching the flag of the same name on BackupSource
+	// that controls whether they were backed up in the first place. Defaults to
+	// false: a restore targeting specific namespaces does not usually want to also
+	// recreate cluster-wide objects.
+	// +optional
+	IncludeClusterResources bool `json:"includeClusterResources,omitempty"`
+}
+
+// ConflictPolicyMode is one of the named strategies ConflictPolicy.Default and
+// ConflictPolicy.PerResource accept.
+type ConflictPolicyMode string
+
+const (
+	// ConflictPolicySkip leaves the existing resource untouched.
+	ConflictPolicySkip ConflictPolicyMode = "Skip"
+	// ConflictPolicyOverwrite replaces the existing resource with the backed-up one.
+	ConflictPolicyOverwrite ConflictPolicyMode = "Overwrite"
+	// ConflictPolicyMerge three-way merges the backed-up manifest into the existing
+	// resource; see restoreResource's Merge handling for the original/modified/current
+	// sides used.
+	ConflictPolicyMerge ConflictPolicyMode = "Merge"
+	// ConflictPolicyFail aborts the whole restore, transitioning it to
+	// RestorePhaseFailed with the offending resource's GVK/name in Status.Message.
+	ConflictPolicyFail ConflictPolicyMode = "Fail"
+)
+
+// ConflictPolicy controls how RestoreReconciler handles a backed-up resource that
+// already exists in the target cluster, with per-resource-type granularity.
+type ConflictPolicy struct {
+	// Default strategy applied to a resource type with no PerResource entry. One of
+	// Skip, Overwrite, Merge, Fail. Empty behaves like Skip.
+	// +optional
+	// +kubebuilder:validation:Enum=Skip;Overwrite;Merge;Fail
+	Default ConflictPolicyMode `json:"default,omitempty"`
+
+	// PerResource overrides Default for specific resource types, keyed by the same
+	// resource type name used elsewhere in Target (e.g. "configmaps", "secrets").
+	// +optional
+	PerResource map[string]ConflictPolicyMode `json:"perResource,omitempty"`
 }
 
 // RestoreOptions defines additional restore options
@@ -76,6 +359,44 @@ type RestoreOptions struct {
 
 	// SkipClusterResources skips restoration of cluster-scoped resources
 	SkipClusterResources bool `json:"skipClusterResources,omitempty"`
+
+	// Resume continues a restore from Status.Checkpoint instead of starting over,
+	// skipping resources already processed. Only meaningful on a Restore that
+	// previously ran and was interrupted; it has no effect while Status.Checkpoint is
+	// unset.
+	Resume bool `json:"resume,omitempty"`
+
+	// CheckpointInterval is how many resources performRestore processes between
+	// persisting Status.Checkpoint, so a large restore surviving a pod eviction picks
+	// up mid-archive instead of restarting. Defaults to defaultCheckpointInterval when
+	// unset.
+	// +optional
+	CheckpointInterval int32 `json:"checkpointInterval,omitempty"`
+
+	// NamespaceTerminationTimeout bounds how long the restore waits in the
+	// WaitingForNamespace phase for a target namespace stuck in Terminating to be
+	// fully removed before failing the restore. Defaults to
+	// defaultNamespaceTerminationTimeout when unset.
+	// +optional
+	NamespaceTerminationTimeout metav1.Duration `json:"namespaceTerminationTimeout,omitempty"`
+
+	// ResourceTerminationTimeout bounds how long restoreResource waits for a
+	// PersistentVolume or PersistentVolumeClaim it finds mid-deletion (a non-nil
+	// deletionTimestamp) to be fully removed before recreating it, instead of racing
+	// the deletion and failing with AlreadyExists or a spurious conflict. Unlike
+	// NamespaceTerminationTimeout, this wait happens inline while restoring that one
+	// resource rather than as its own reconcile phase, since a PV/PVC's finalizers
+	// typically clear much faster than a whole namespace's contents. Defaults to
+	// defaultResourceTerminationTimeout when unset.
+	// +optional
+	ResourceTerminationTimeout metav1.Duration `json:"resourceTerminationTimeout,omitempty"`
+
+	// RestoreOrder overrides defaultRestoreWaveOrder, the sequence of resource types
+	// (lower-cased plural, e.g. "persistentvolumeclaims") performRestore restores in.
+	// Resource types not listed here restore after every listed type, in their
+	// existing relative order. See restoreWaveOrder.
+	// +optional
+	RestoreOrder []string `json:"restoreOrder,omitempty"`
 }
 
 // RestoreStatus defines the observed state of Restore.
@@ -106,18 +427,91 @@ type RestoreStatus struct {
 
 	// BackupInfo contains information about the source backup
 	BackupInfo *BackupInfo `json:"backupInfo,omitempty"`
+
+	// Checkpoint records how far a resumable restore has progressed through its
+	// ordered backup key list; see RestoreOptions.Resume.
+	// +optional
+	Checkpoint *RestoreCheckpoint `json:"checkpoint,omitempty"`
+
+	// NamespaceWaitStartTime records when the restore entered the
+	// WaitingForNamespace phase, so that phase can enforce
+	// RestoreOptions.NamespaceTerminationTimeout. Cleared once the wait ends.
+	// +optional
+	NamespaceWaitStartTime *metav1.Time `json:"namespaceWaitStartTime,omitempty"`
+
+	// HookResults lists the outcome of every RestoreHook run against a restored
+	// resource.
+	// +optional
+	HookResults []RestoreHookResult `json:"hookResults,omitempty"`
+
+	// CreatedNamespaces lists the target namespaces this restore created (as opposed
+	// to ones that already existed) via EnsureNamespaceExistsAndIsReady, so they count
+	// toward restore progress alongside RestoredResources.
+	// +optional
+	CreatedNamespaces []string `json:"createdNamespaces,omitempty"`
+
+	// Progress surfaces a resumable restore's progress through its ordered backup item
+	// list, refreshed at the same cadence as Checkpoint, for `kubectl get restore -w`
+	// and similar observability without having to count RestoredResources/
+	// FailedResources/SkippedResources entries by hand.
+	// +optional
+	Progress *RestoreProgress `json:"progress,omitempty"`
+
+	// Conditions reports standard Kubernetes status conditions for this restore, e.g.
+	// CredentialsResolved reporting which storage credentials source satisfied the
+	// source StorageLocation, or why none did.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// RestoreProgress reports how far performRestore has gotten through its ordered backup
+// item list.
+type RestoreProgress struct {
+	// ItemsTotal is the number of backup items (after Target filtering and dedup
+	// manifest resolution) this restore will process.
+	ItemsTotal int32 `json:"itemsTotal"`
+
+	// ItemsCompleted is how many of those items have been created, updated, skipped,
+	// or failed so far.
+	ItemsCompleted int32 `json:"itemsCompleted"`
+
+	// CurrentResource is the backup object key of the item currently being restored,
+	// the same value a new Checkpoint would record if performRestore were interrupted
+	// right now.
+	// +optional
+	CurrentResource string `json:"currentResource,omitempty"`
+}
+
+// RestoreCheckpoint records the last backup resource performRestore successfully
+// processed (created, updated, skipped, or failed all count as processed), so a
+// restore with RestoreOptions.Resume set can continue from Offset+1 in its ordered
+// key list instead of redoing completed work.
+type RestoreCheckpoint struct {
+	// ResourceKey is the backup object key of the last processed resource.
+	ResourceKey string `json:"resourceKey"`
+
+	// Offset is ResourceKey's index in the restore's ordered key list. Resuming
+	// continues from Offset+1 rather than reinterpreting ResourceKey, so it is only
+	// meaningful read back against the same backup and the same Target filters that
+	// produced this ordering.
+	Offset int32 `json:"offset"`
 }
 
 // RestorePhase represents the current phase of a restore operation
 type RestorePhase string
 
 const (
-	RestorePhaseNew         RestorePhase = "New"
-	RestorePhaseValidating  RestorePhase = "Validating"
-	RestorePhaseDownloading RestorePhase = "Downloading"
-	RestorePhaseRestoring   RestorePhase = "Restoring"
-	RestorePhaseCompleted   RestorePhase = "Completed"
-	RestorePhaseFailed      RestorePhase = "Failed"
+	RestorePhaseNew                 RestorePhase = "New"
+	RestorePhaseValidating          RestorePhase = "Validating"
+	RestorePhaseDownloading         RestorePhase = "Downloading"
+	RestorePhaseWaitingForNamespace RestorePhase = "WaitingForNamespace"
+	RestorePhaseRestoring           RestorePhase = "Restoring"
+	RestorePhaseCompleted           RestorePhase = "Completed"
+	RestorePhaseFailed              RestorePhase = "Failed"
 )
 
 // RestoredResource represents a successfully restored resource
@@ -134,8 +528,27 @@ type RestoredResource struct {
 	// Namespace of the restored resource (empty for cluster-scoped resources)
 	Namespace string `json:"namespace,omitempty"`
 
-	// Action taken during restoration (created, updated)
+	// Action taken during restoration (created, updated, dry-run, snapshot-restored,
+	// pvc-remapped, data-mover-restored, data-mover-pending). pvc-remapped is a
+	// created/updated PersistentVolumeClaim whose spec.dataSource was pointed at a
+	// restored VolumeSnapshot; snapshot-restored is that VolumeSnapshot's own entry.
+	// See VolumeRestoreOptions.RestorePVs. data-mover-restored is a PersistentVolumeClaim
+	// whose data was extracted from a data-mover chunk manifest once a consuming pod
+	// mounted it; data-mover-pending means no consuming pod did so within the bounded
+	// wait, so the data-mover restore was skipped. See
+	// VolumeRestoreOptions.RestoreDataMoverVolumes.
 	Action string `json:"action"`
+
+	// AppliedActions lists the names of ItemActions (see RestoreSpec.ItemActions)
+	// whose Applies matched this resource and whose Execute ran against it.
+	// +optional
+	AppliedActions []string `json:"appliedActions,omitempty"`
+
+	// ModifiedByRules lists the GroupResource of every ResourceModifierRef rule
+	// whose patches were applied to (or, in DryRun, would have been applied to) this
+	// resource.
+	// +optional
+	ModifiedByRules []string `json:"modifiedByRules,omitempty"`
 }
 
 // FailedResource represents a resource that failed to restore
@@ -197,6 +610,8 @@ type BackupInfo struct {
 //+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
 //+kubebuilder:printcolumn:name="Backup Path",type=string,JSONPath=`.spec.source.backupPath`
 //+kubebuilder:printcolumn:name="Restored",type=integer,JSONPath=`.status.resourceCounts.total`
+//+kubebuilder:printcolumn:name="Items",type=string,JSONPath=`.status.progress.itemsCompleted`
+//+kubebuilder:printcolumn:name="Total",type=string,JSONPath=`.status.progress.itemsTotal`
 //+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Restore is the Schema for the restores API
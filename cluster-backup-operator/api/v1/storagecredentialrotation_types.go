@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StorageCredentialRotationSpec defines the desired state of StorageCredentialRotation.
+type StorageCredentialRotationSpec struct {
+	// SecretRef points at the Secret holding the MinIO access/secret key pair to
+	// rotate. This is normally the same Secret one or more StorageLocations
+	// reference via CredentialsRef/AdminCredentialsRef.
+	SecretRef CredentialsReference `json:"secretRef"`
+
+	// AdminEndpoint is the MinIO admin API endpoint used to register the newly
+	// generated credential and probe it before it replaces the current one.
+	AdminEndpoint string `json:"adminEndpoint"`
+
+	// Secure forces TLS when talking to AdminEndpoint.
+	// +optional
+	Secure bool `json:"secure,omitempty"`
+
+	// Bucket is checked (via BucketExists) with the newly generated credential
+	// before it is allowed to replace the current one in SecretRef.
+	Bucket string `json:"bucket"`
+
+	// RotationInterval is how often a new credential pair is generated. Defaults to
+	// 24h when unset.
+	// +optional
+	RotationInterval metav1.Duration `json:"rotationInterval,omitempty"`
+
+	// DryRun generates a candidate credential pair and logs what rotation would do
+	// without calling the MinIO admin API or writing SecretRef, so the
+	// interval/probe plumbing can be exercised before it is trusted to run for real.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// StorageCredentialRotationPhase represents the current phase of a
+// StorageCredentialRotation.
+type StorageCredentialRotationPhase string
+
+const (
+	// StorageCredentialRotationPhaseNew is the phase before the first rotation has
+	// been attempted.
+	StorageCredentialRotationPhaseNew StorageCredentialRotationPhase = "New"
+	// StorageCredentialRotationPhaseCompleted is the phase after the most recent
+	// rotation attempt swapped in a verified new credential (or, in DryRun, after a
+	// no-op attempt completed successfully).
+	StorageCredentialRotationPhaseCompleted StorageCredentialRotationPhase = "Completed"
+	// StorageCredentialRotationPhaseFailed is the phase after the most recent
+	// rotation attempt could not verify the new credential and was rolled back. The
+	// Secret's existing credential is left untouched, and the next attempt is made at
+	// NextRotation, one RotationInterval later.
+	StorageCredentialRotationPhaseFailed StorageCredentialRotationPhase = "Failed"
+)
+
+// StorageCredentialRotationStatus defines the observed state of
+// StorageCredentialRotation.
+type StorageCredentialRotationStatus struct {
+	// Phase represents the current phase of the rotation
+	// +optional
+	Phase StorageCredentialRotationPhase `json:"phase,omitempty"`
+
+	// Message provides additional information about the current state
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastRotated is when a new credential was last written to SecretRef as the
+	// primary access/secret key.
+	// +optional
+	LastRotated *metav1.Time `json:"lastRotated,omitempty"`
+
+	// NextRotation is when the controller will next attempt a rotation.
+	// +optional
+	NextRotation *metav1.Time `json:"nextRotation,omitempty"`
+
+	// Conditions reports standard Kubernetes status conditions for this rotation,
+	// e.g. RotationSucceeded reporting whether the most recent attempt verified and
+	// swapped in its new credential, or why it was rolled back.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="DryRun",type=boolean,JSONPath=`.spec.dryRun`
+// +kubebuilder:printcolumn:name="LastRotated",type=date,JSONPath=`.status.lastRotated`
+// +kubebuilder:printcolumn:name="NextRotation",type=date,JSONPath=`.status.nextRotation`
+
+// StorageCredentialRotation periodically rotates the access/secret key pair a
+// MinIO-backed StorageLocation's Secret holds, generating a new credential,
+// registering it with the MinIO admin API under the current credential's
+// authority, writing it into the Secret as a pending key, probing it with a
+// BucketExists call, and only then promoting it to the Secret's primary key --
+// rolling the new credential back if the probe fails, so a bad rotation never
+// leaves the Secret holding a key nothing can authenticate with.
+type StorageCredentialRotation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StorageCredentialRotationSpec   `json:"spec,omitempty"`
+	Status StorageCredentialRotationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// StorageCredentialRotationList contains a list of StorageCredentialRotation.
+type StorageCredentialRotationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StorageCredentialRotation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StorageCredentialRotation{}, &StorageCredentialRotationList{})
+}
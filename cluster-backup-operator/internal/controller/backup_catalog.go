@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// backupRootPrefix is the prefix every Backup's timestamped backupPath is written
+// under; see performBackup.
+const backupRootPrefix = "backups/cluster-backup"
+
+// backupTimestampLayout is how performBackup formats a backup's timestamp into its
+// backupPath segment.
+const backupTimestampLayout = "2006-01-02T15-04-05"
+
+// defaultCatalogPageSize is how many BackupCatalogEntry items catalogBackups returns
+// per page when BackupCatalogSpec.PageSize is unset.
+const defaultCatalogPageSize = 50
+
+// Catalog paginates over every backup found under backupRootPrefix in bucket,
+// deriving each backup's boundaries from the object key layout uploadResource writes
+// instead of reading every resource object it contains. token resumes listing after
+// the named backup, mirroring minio.ListObjectsOptions.StartAfter; pageSize <= 0
+// defaults to defaultCatalogPageSize.
+func (r *BackupReconciler) Catalog(ctx context.Context, provider storage.Provider, bucket, token string, pageSize int) ([]backupv1.BackupCatalogEntry, string, error) {
+	return catalogBackups(ctx, provider, bucket, token, pageSize)
+}
+
+// catalogBackups is the shared implementation behind BackupReconciler.Catalog and
+// BackupCatalogReconciler, kept as a package-level function since it needs no
+// reconciler state of its own beyond the storage.Provider it is handed.
+func catalogBackups(ctx context.Context, provider storage.Provider, bucket, token string, pageSize int) ([]backupv1.BackupCatalogEntry, string, error) {
+	if pageSize <= 0 {
+		pageSize = defaultCatalogPageSize
+	}
+
+	keys, err := provider.ListObjects(ctx, bucket, backupRootPrefix+"/")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list backups under %s: %w", backupRootPrefix, err)
+	}
+
+	names := backupNamesFromKeys(keys)
+
+	start := 0
+	for i, name := range names {
+		if name > token {
+			break
+		}
+		start = i + 1
+	}
+	page := names[start:]
+
+	nextToken := ""
+	if len(page) > pageSize {
+		page = page[:pageSize]
+		nextToken = page[len(page)-1]
+	}
+
+	entries := make([]backupv1.BackupCatalogEntry, 0, len(page))
+	for _, name := range page {
+		entries = append(entries, catalogEntryForBackup(name, keys))
+	}
+
+	return entries, nextToken, nil
+}
+
+// backupNamesFromKeys derives the sorted, de-duplicated set of backup path segments
+// (e.g. "2026-01-02T15-04-05") immediately under backupRootPrefix from a flat key
+// listing. dedupChunksPrefix and volumeChunksPrefix live under backupRootPrefix too
+// but are not backups, so their "chunks" and "volumechunks" segments are excluded
+// explicitly. Backup timestamps sort lexicographically in the same order they were
+// created, so a plain string sort orders backups oldest-first.
+func backupNamesFromKeys(keys []string) []string {
+	const chunksSegment = "chunks"
+	const volumeChunksSegment = "volumechunks"
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, key := range keys {
+		name := backupNameFromKey(key)
+		if name == "" || name == chunksSegment || name == volumeChunksSegment || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// catalogEntryForBackup summarizes one backup by scanning the subset of keys under
+// "<backupRootPrefix>/<name>/", counting resources per the same "<namespace>/<type>"
+// and "cluster/<type>" keys performBackup records in Backup.Status.ResourceCounts.
+// Keys that fall directly under the backup (manifest.json, a SingleTarball's
+// backup.tar) match neither shape and are simply not counted.
+func catalogEntryForBackup(name string, keys []string) backupv1.BackupCatalogEntry {
+	entry := backupv1.BackupCatalogEntry{
+		BackupName:     name,
+		ResourceCounts: make(map[string]int32),
+	}
+
+	if ts, err := time.Parse(backupTimestampLayout, name); err == nil {
+		entry.Timestamp = &metav1.Time{Time: ts}
+	}
+
+	prefix := fmt.Sprintf("%s/%s/", backupRootPrefix, name)
+	namespaces := make(map[string]bool)
+
+	for _, key := range keys {
+		rest := strings.TrimPrefix(key, prefix)
+		if rest == key {
+			continue
+		}
+
+		parts := strings.SplitN(rest, "/", 4)
+		switch {
+		case len(parts) >= 3 && parts[0] == "namespaces":
+			namespace, resourceType := parts[1], parts[2]
+			namespaces[namespace] = true
+			entry.ResourceCounts[fmt.Sprintf("%s/%s", namespace, resourceType)]++
+		case len(parts) >= 2 && parts[0] == "cluster":
+			entry.ResourceCounts[fmt.Sprintf("cluster/%s", parts[1])]++
+		}
+	}
+
+	for namespace := range namespaces {
+		entry.Namespaces = append(entry.Namespaces, namespace)
+	}
+	sort.Strings(entry.Namespaces)
+
+	return entry
+}
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+// TestCatalogBackupsDerivesBoundariesAndCounts verifies that catalogBackups turns a
+// flat key listing across two backups into per-backup entries, ignoring the
+// dedupChunksPrefix keys that live under the same root.
+func TestCatalogBackupsDerivesBoundariesAndCounts(t *testing.T) {
+	ctx := context.Background()
+	provider := newLocalProviderForTest(t)
+	bucket := "test-bucket"
+	if err := provider.EnsureBucket(ctx, bucket); err != nil {
+		t.Fatalf("failed to ensure bucket: %v", err)
+	}
+
+	keys := []string{
+		"backups/cluster-backup/2026-01-01T00-00-00/namespaces/default/configmaps/a.json",
+		"backups/cluster-backup/2026-01-01T00-00-00/namespaces/default/configmaps/b.json",
+		"backups/cluster-backup/2026-01-01T00-00-00/namespaces/default/secrets/c.json",
+		"backups/cluster-backup/2026-01-01T00-00-00/cluster/clusterroles/d.json",
+		"backups/cluster-backup/2026-01-01T00-00-00/manifest.json",
+		"backups/cluster-backup/2026-01-02T00-00-00/namespaces/kube-system/configmaps/e.json",
+		"backups/cluster-backup/chunks/ab/abcdef.json",
+	}
+	for _, key := range keys {
+		if err := provider.PutObject(ctx, bucket, key, bytes.NewReader([]byte("{}")), 2); err != nil {
+			t.Fatalf("failed to seed object %s: %v", key, err)
+		}
+	}
+
+	entries, nextToken, err := catalogBackups(ctx, provider, bucket, "", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nextToken != "" {
+		t.Errorf("expected no next token with default page size, got %q", nextToken)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 backups, got %d: %+v", len(entries), entries)
+	}
+
+	first := entries[0]
+	if first.BackupName != "2026-01-01T00-00-00" {
+		t.Errorf("unexpected first backup name: %s", first.BackupName)
+	}
+	if first.Timestamp == nil {
+		t.Errorf("expected timestamp to be parsed for %s", first.BackupName)
+	}
+	if len(first.Namespaces) != 1 || first.Namespaces[0] != "default" {
+		t.Errorf("unexpected namespaces: %+v", first.Namespaces)
+	}
+	if first.ResourceCounts["default/configmaps"] != 2 {
+		t.Errorf("expected 2 default/configmaps, got %d", first.ResourceCounts["default/configmaps"])
+	}
+	if first.ResourceCounts["default/secrets"] != 1 {
+		t.Errorf("expected 1 default/secrets, got %d", first.ResourceCounts["default/secrets"])
+	}
+	if first.ResourceCounts["cluster/clusterroles"] != 1 {
+		t.Errorf("expected 1 cluster/clusterroles, got %d", first.ResourceCounts["cluster/clusterroles"])
+	}
+
+	second := entries[1]
+	if second.BackupName != "2026-01-02T00-00-00" {
+		t.Errorf("unexpected second backup name: %s", second.BackupName)
+	}
+}
+
+// TestCatalogBackupsPaginates verifies that pageSize and the returned token
+// correctly split three backups across two pages.
+func TestCatalogBackupsPaginates(t *testing.T) {
+	ctx := context.Background()
+	provider := newLocalProviderForTest(t)
+	bucket := "test-bucket"
+	if err := provider.EnsureBucket(ctx, bucket); err != nil {
+		t.Fatalf("failed to ensure bucket: %v", err)
+	}
+
+	for _, name := range []string{"2026-01-01T00-00-00", "2026-01-02T00-00-00", "2026-01-03T00-00-00"} {
+		key := "backups/cluster-backup/" + name + "/cluster/clusterroles/a.json"
+		if err := provider.PutObject(ctx, bucket, key, bytes.NewReader([]byte("{}")), 2); err != nil {
+			t.Fatalf("failed to seed object %s: %v", key, err)
+		}
+	}
+
+	firstPage, token, err := catalogBackups(ctx, provider, bucket, "", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(firstPage) != 2 {
+		t.Fatalf("expected 2 entries on first page, got %d", len(firstPage))
+	}
+	if token != "2026-01-02T00-00-00" {
+		t.Errorf("unexpected next token: %q", token)
+	}
+
+	secondPage, token, err := catalogBackups(ctx, provider, bucket, token, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(secondPage) != 1 || secondPage[0].BackupName != "2026-01-03T00-00-00" {
+		t.Fatalf("unexpected second page: %+v", secondPage)
+	}
+	if token != "" {
+		t.Errorf("expected no next token after the last page, got %q", token)
+	}
+}
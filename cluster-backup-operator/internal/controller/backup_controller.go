@@ -21,20 +21,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
-	"github.com/minio/minio-go/v7"
-	"github.com/minio/minio-go/v7/pkg/credentials"
 	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
-	"github.com/robfig/cron/v3"
+	"github.com/rfashwall/cnd-workshop/pkg/filter"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
 
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -43,22 +45,61 @@ import (
 type BackupReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// RestConfig is used to open exec sessions into pods for "exec" quiesce hooks.
+	RestConfig *rest.Config
+
+	// Recorder emits Kubernetes Events on the Backup object, e.g. when a hook fails.
+	Recorder record.EventRecorder
+
+	// AllowedCredentialsNamespaces restricts which namespaces a StorageLocation's
+	// CredentialsRef may point Secrets into. Empty means no restriction.
+	AllowedCredentialsNamespaces []string
+
+	// PluginDir is scanned for ItemAction plugin manifests (see pkg/plugin.Registry.
+	// LoadDir) whenever a Backup configures spec.itemActions. Empty disables loading
+	// external plugins; the built-in ItemActions remain available regardless.
+	PluginDir string
+
+	// ProviderCache, if set, caches the storage.Provider built for each Backup's
+	// StorageLocation across reconciles, keyed by storage.CacheKey so a change to the
+	// Backup or its CredentialsRef Secret still rebuilds a fresh client. nil disables
+	// caching and builds a Provider on every call, same as before ProviderCache
+	// existed.
+	ProviderCache *storage.ProviderCache
+
+	// RunMode, when OperatorRunModeRestoreOnly, refuses to reconcile new/changed
+	// Backup objects (other than their own deletion) -- for a DR cluster that should
+	// only ever restore into it. The zero value, OperatorRunModeFull, reconciles
+	// normally.
+	RunMode OperatorRunMode
 }
 
 // +kubebuilder:rbac:groups=backup.cnd.dk,resources=backups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=backup.cnd.dk,resources=backups/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=backup.cnd.dk,resources=backups/finalizers,verbs=update
-// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=backup.cnd.dk,resources=backupdeletionrequests,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=backup.cnd.dk,resources=backupstorageproviders,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="",resources=persistentvolumes,verbs=get;list;watch
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=get;list;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshotcontents,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshotclasses,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -79,6 +120,23 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	log.Info("Reconciling Backup", "backup", backup.Name, "namespace", backup.Namespace)
 
+	if backup.DeletionTimestamp != nil {
+		return r.reconcileDeletion(ctx, backup)
+	}
+
+	if r.RunMode == OperatorRunModeRestoreOnly {
+		return r.refuseRunMode(ctx, backup)
+	}
+
+	if !controllerutil.ContainsFinalizer(backup, backupv1.BackupFinalizer) {
+		controllerutil.AddFinalizer(backup, backupv1.BackupFinalizer)
+		if err := r.Update(ctx, backup); err != nil {
+			log.Error(err, "Failed to add finalizer to Backup")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	// Initialize status if not set
 	if backup.Status.Phase == "" {
 		backup.Status.Phase = backupv1.BackupPhaseNew
@@ -147,6 +205,7 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			if err := r.performBackup(ctx, backup); err != nil {
 				backup.Status.Phase = backupv1.BackupPhaseFailed
 				backup.Status.Message = fmt.Sprintf("Backup failed: %v", err)
+				backup.Status.CompletionTime = &metav1.Time{Time: time.Now()}
 				if updateErr := r.Status().Update(ctx, backup); updateErr != nil {
 					log.Error(updateErr, "Failed to update backup status to failed")
 				}
@@ -156,6 +215,7 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			backup.Status.Phase = backupv1.BackupPhaseCompleted
 			backup.Status.Message = "Backup completed successfully"
 			backup.Status.LastBackupTime = &metav1.Time{Time: time.Now()}
+			backup.Status.CompletionTime = &metav1.Time{Time: time.Now()}
 			backup.Status.BackupCount++
 
 			// Calculate next backup time and schedule next run
@@ -197,6 +257,43 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	return ctrl.Result{}, nil
 }
 
+// refuseRunMode marks backup Failed with a message explaining RunMode blocked it,
+// without attempting any backup work. It is idempotent: once the message is already
+// set, it does nothing further, so a restore-only operator doesn't churn the object's
+// status on every reconcile.
+func (r *BackupReconciler) refuseRunMode(ctx context.Context, backup *backupv1.Backup) (ctrl.Result, error) {
+	message := runModeRefusalMessage("Backup", r.RunMode)
+	if backup.Status.Phase == backupv1.BackupPhaseFailed && backup.Status.Message == message {
+		return ctrl.Result{}, nil
+	}
+	backup.Status.Phase = backupv1.BackupPhaseFailed
+	backup.Status.Message = message
+	if err := r.Status().Update(ctx, backup); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// storageProvider resolves backup's storage.Provider, via r.ProviderCache when set.
+// The cache key folds in backup's own ResourceVersion and (when its StorageLocation
+// uses CredentialsRef) the referenced Secret's ResourceVersion, so editing the Backup
+// or rotating its credentials Secret always misses the cache and rebuilds a fresh
+// client rather than serving a stale one.
+func (r *BackupReconciler) storageProvider(ctx context.Context, backup *backupv1.Backup) (storage.Provider, error) {
+	spec := backup.Spec.StorageLocation
+	if r.ProviderCache == nil {
+		return storage.NewProvider(ctx, r.Client, r.AllowedCredentialsNamespaces, backup.Namespace, spec)
+	}
+
+	credsRV, err := storage.CredentialsRefResourceVersion(ctx, r.Client, r.AllowedCredentialsNamespaces, backup.Namespace, spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve credentials secret for cache key: %w", err)
+	}
+
+	cacheKey := storage.CacheKey(backup.Namespace, backup.Name, backup.ResourceVersion, credsRV)
+	return r.ProviderCache.GetOrCreate(ctx, r.Client, r.AllowedCredentialsNamespaces, backup.Namespace, cacheKey, spec)
+}
+
 // performBackup executes the actual backup operation
 func (r *BackupReconciler) performBackup(ctx context.Context, backup *backupv1.Backup) error {
 	log := logf.FromContext(ctx)
@@ -208,28 +305,33 @@ func (r *BackupReconciler) performBackup(ctx context.Context, backup *backupv1.B
 		return fmt.Errorf("failed to update status to running: %w", err)
 	}
 
-	// Initialize Minio client
-	minioClient, err := r.initMinioClient(ctx, backup)
+	// Initialize the storage provider for the configured backend
+	provider, err := r.storageProvider(ctx, backup)
 	if err != nil {
-		return fmt.Errorf("failed to initialize Minio client: %w", err)
+		return fmt.Errorf("failed to initialize storage provider: %w", err)
 	}
 
 	// Ensure bucket exists
 	bucketName := backup.Spec.StorageLocation.Bucket
-	exists, err := minioClient.BucketExists(ctx, bucketName)
-	if err != nil {
-		return fmt.Errorf("failed to check bucket existence: %w", err)
+	if err := provider.EnsureBucket(ctx, bucketName); err != nil {
+		return fmt.Errorf("failed to ensure bucket: %w", err)
 	}
-	if !exists {
-		if err := minioClient.MakeBucket(ctx, bucketName, minio.MakeBucketOptions{}); err != nil {
-			return fmt.Errorf("failed to create bucket: %w", err)
+
+	// Wrap provider so every object written during this run also fans out to each
+	// configured ReplicaTarget; replicatingProvider stays nil (and provider
+	// unwrapped) when there are none, so the common case pays no overhead.
+	var replicatingProvider *storage.ReplicatingProvider
+	if len(backup.Spec.StorageLocation.ReplicaTargets) > 0 {
+		replicatingProvider, err = r.newReplicatingProvider(ctx, backup, provider)
+		if err != nil {
+			return fmt.Errorf("failed to initialize replica targets: %w", err)
 		}
-		log.Info("Created bucket", "bucket", bucketName)
+		provider = replicatingProvider
 	}
 
 	// Create backup timestamp and path
-	timestamp := time.Now().Format("2006-01-02T15-04-05")
-	backupPath := fmt.Sprintf("backups/cluster-backup/%s", timestamp)
+	timestamp := time.Now().Format(backupTimestampLayout)
+	backupPath := fmt.Sprintf("%s/%s", backupRootPrefix, timestamp)
 
 	// Initialize resource counts
 	resourceCounts := make(map[string]int32)
@@ -243,24 +345,66 @@ func (r *BackupReconciler) performBackup(ctx context.Context, backup *backupv1.B
 	// Determine which resource types to backup
 	resourceTypes := r.getResourceTypesToBackup(backup.Spec.Source)
 
+	// Reject an invalid filter policy (e.g. a resourceType listed in both
+	// ResourceTypes and ExcludedResources, or a malformed label selector) before any
+	// resource is listed, so it surfaces as a Backup failure instead of silently
+	// backing up nothing.
+	if _, err := backupFilterPredicate(backup.Spec.Source); err != nil {
+		return fmt.Errorf("invalid backup filter policy: %w", err)
+	}
+	for _, resourceType := range resourceTypes {
+		for _, excluded := range backup.Spec.Source.ExcludedResources {
+			if resourceType == excluded {
+				return fmt.Errorf("invalid backup filter policy: resourceType %q is listed in both resourceTypes and excludedResources", resourceType)
+			}
+		}
+	}
+
+	// When Deduplicate is enabled, every uploadResource call below routes through dedup
+	// instead of writing one object per resource; dedup.finalize uploads the resulting
+	// manifest once all resource types have been processed.
+	packagingMode := backup.Spec.PackagingMode
+	if backup.Spec.Source.Deduplicate && packagingMode != "" && packagingMode != backupv1.PackagingModePerResource {
+		return fmt.Errorf("source.deduplicate is not supported together with packagingMode %q", packagingMode)
+	}
+
+	if backup.Spec.Encryption != nil && packagingMode != backupv1.PackagingModeSingleTarball {
+		return fmt.Errorf("encryption requires packagingMode %q, since its AES-GCM authentication tag needs the whole manifest tarball up front", backupv1.PackagingModeSingleTarball)
+	}
+
+	var dedup *dedupState
+	if backup.Spec.Source.Deduplicate {
+		dedup = newDedupState()
+	}
+
+	actions, err := r.newItemActionState(backup)
+	if err != nil {
+		return fmt.Errorf("failed to load item actions: %w", err)
+	}
+	defer actions.close()
+
+	// Under PackagingModeSingleTarball every resource backed up by this run, across
+	// every namespace, cluster resource type and IncludeCRDs entry, accumulates into
+	// one packagingState uploaded as a single tarball once everything below completes.
+	// PackagingModeTarballPerNamespace instead creates one packagingState per namespace
+	// inside backupNamespace; pack stays nil here for that mode.
+	var pack *packagingState
+	if packagingMode == backupv1.PackagingModeSingleTarball {
+		pack = newPackagingState()
+	}
+
 	log.Info("Starting backup operation",
 		"namespaces", namespacesToBackup,
 		"resourceTypes", resourceTypes,
 		"backupPath", backupPath,
-		"includeClusterResources", backup.Spec.Source.IncludeClusterResources)
+		"includeClusterResources", backup.Spec.Source.IncludeClusterResources,
+		"deduplicate", backup.Spec.Source.Deduplicate,
+		"packagingMode", packagingMode)
 
 	// Backup namespace-scoped resources
 	for _, namespace := range namespacesToBackup {
-		for _, resourceType := range resourceTypes {
-			count, err := r.backupNamespacedResourceType(ctx, minioClient, bucketName, backupPath, namespace, backup.Spec.Source, resourceType)
-			if err != nil {
-				return fmt.Errorf("failed to backup %s in namespace %s: %w", resourceType, namespace, err)
-			}
-			key := fmt.Sprintf("%s/%s", namespace, resourceType)
-			resourceCounts[key] = count
-			if count > 0 {
-				log.Info("Backed up namespaced resources", "namespace", namespace, "type", resourceType, "count", count)
-			}
+		if err := r.backupNamespace(ctx, provider, bucketName, backupPath, namespace, backup, resourceTypes, resourceCounts, actions, dedup, pack); err != nil {
+			return err
 		}
 	}
 
@@ -268,7 +412,7 @@ func (r *BackupReconciler) performBackup(ctx context.Context, backup *backupv1.B
 	if backup.Spec.Source.IncludeClusterResources {
 		clusterResourceTypes := r.getClusterResourceTypes()
 		for _, resourceType := range clusterResourceTypes {
-			count, err := r.backupClusterResourceType(ctx, minioClient, bucketName, backupPath, backup.Spec.Source, resourceType)
+			count, err := r.backupClusterResourceType(ctx, provider, bucketName, backupPath, backup.Spec.Source, resourceType, actions, dedup, pack)
 			if err != nil {
 				return fmt.Errorf("failed to backup cluster resource %s: %w", resourceType, err)
 			}
@@ -280,9 +424,66 @@ func (r *BackupReconciler) performBackup(ctx context.Context, backup *backupv1.B
 		}
 	}
 
+	// Backup arbitrary CRDs listed in IncludeCRDs via the discovery/dynamic client
+	for _, ref := range backup.Spec.Source.IncludeCRDs {
+		counts, err := r.backupIncludedCRD(ctx, provider, bucketName, backupPath, namespacesToBackup, backup.Spec.Source, ref, actions, dedup, pack)
+		if err != nil {
+			return fmt.Errorf("failed to backup CRD %q: %w", ref, err)
+		}
+		for key, count := range counts {
+			resourceCounts[key] = count
+			if count > 0 {
+				log.Info("Backed up CRD resources", "ref", ref, "key", key, "count", count)
+			}
+		}
+	}
+
+	if dedup != nil {
+		stats, err := dedup.finalize(ctx, provider, bucketName, backupPath)
+		if err != nil {
+			return fmt.Errorf("failed to finalize dedup manifest: %w", err)
+		}
+		backup.Status.DedupStats = stats
+		log.Info("Deduplication summary",
+			"totalResources", stats.TotalResources,
+			"chunksWritten", stats.ChunksWritten,
+			"chunksReused", stats.ChunksReused,
+			"bytesSaved", stats.BytesSaved)
+	}
+
+	if pack != nil {
+		if backup.Spec.Encryption != nil {
+			status, err := r.encryptAndUploadManifest(ctx, provider, bucketName, backupPath, backup, pack)
+			if err != nil {
+				return fmt.Errorf("failed to upload encrypted backup tarball: %w", err)
+			}
+			backup.Status.Encryption = status
+		} else {
+			tarKey := fmt.Sprintf("%s/backup.tar", backupPath)
+			if err := pack.upload(ctx, provider, bucketName, backupPath, tarKey); err != nil {
+				return fmt.Errorf("failed to upload backup tarball: %w", err)
+			}
+		}
+	}
+
+	if backup.Spec.Retention != nil {
+		if err := r.enforceRetention(ctx, backup, provider, bucketName, backup.Spec.Retention); err != nil {
+			return fmt.Errorf("failed to enforce retention policy: %w", err)
+		}
+	}
+
 	// Update backup status with results
 	backup.Status.ResourceCounts = resourceCounts
 	backup.Status.BackupPath = backupPath
+	backup.Status.VolumeSnapshotsTaken = int32(len(backup.Status.VolumeSnapshots))
+	for _, record := range backup.Status.VolumeSnapshots {
+		if record.ReadyToUse {
+			backup.Status.VolumeSnapshotsCompleted++
+		}
+	}
+	if replicatingProvider != nil {
+		backup.Status.Replicas = r.buildReplicaStatuses(backup, replicatingProvider)
+	}
 
 	log.Info("Backup operation completed successfully",
 		"namespaces", namespacesToBackup,
@@ -293,42 +494,51 @@ func (r *BackupReconciler) performBackup(ctx context.Context, backup *backupv1.B
 	return nil
 }
 
-// initMinioClient creates and configures a Minio client
-func (r *BackupReconciler) initMinioClient(ctx context.Context, backup *backupv1.Backup) (*minio.Client, error) {
-	storage := backup.Spec.StorageLocation
-
-	// Get credentials from backup spec (simplified for workshop)
-	accessKey := storage.AccessKey
-	secretKey := storage.SecretKey
+// backupNamespace quiesces any workloads matching backup.Spec.Source.Hooks in namespace,
+// backs up every requested resourceType, and always un-quiesces afterwards (even on
+// failure) via a deferred call to runPostBackupHooks. When pack is non-nil
+// (PackagingModeSingleTarball), every resource backed up here is accumulated into it
+// instead of uploaded individually. When PackagingModeTarballPerNamespace is set
+// instead, backupNamespace creates its own packagingState and uploads a tarball scoped
+// to this namespace once every resourceType has been processed.
+func (r *BackupReconciler) backupNamespace(ctx context.Context, provider storage.Provider, bucket, backupPath, namespace string, backup *backupv1.Backup, resourceTypes []string, resourceCounts map[string]int32, actions *itemActionState, dedup *dedupState, pack *packagingState) error {
+	log := logf.FromContext(ctx)
 
-	// Use default credentials if not specified
-	if accessKey == "" {
-		accessKey = "minioadmin"
-	}
-	if secretKey == "" {
-		secretKey = "minioadmin123"
+	scaled, err := r.runPreBackupHooks(ctx, backup, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to run pre-backup hooks in namespace %s: %w", namespace, err)
 	}
+	defer func() {
+		if err := r.runPostBackupHooks(ctx, backup, namespace, scaled); err != nil {
+			log.Error(err, "failed to run post-backup hooks", "namespace", namespace)
+		}
+	}()
 
-	// Parse endpoint URL
-	endpoint := storage.Endpoint
-	if endpoint == "" {
-		return nil, fmt.Errorf("storage endpoint is required")
+	namespacePack := pack
+	if backup.Spec.PackagingMode == backupv1.PackagingModeTarballPerNamespace {
+		namespacePack = newPackagingState()
 	}
 
-	// Remove http:// or https:// prefix for minio client
-	endpoint = strings.TrimPrefix(endpoint, "http://")
-	endpoint = strings.TrimPrefix(endpoint, "https://")
+	for _, resourceType := range resourceTypes {
+		count, err := r.backupNamespacedResourceType(ctx, provider, bucket, backupPath, namespace, backup, backup.Spec.Source, resourceType, actions, dedup, namespacePack)
+		if err != nil {
+			return fmt.Errorf("failed to backup %s in namespace %s: %w", resourceType, namespace, err)
+		}
+		key := fmt.Sprintf("%s/%s", namespace, resourceType)
+		resourceCounts[key] = count
+		if count > 0 {
+			log.Info("Backed up namespaced resources", "namespace", namespace, "type", resourceType, "count", count)
+		}
+	}
 
-	// Create Minio client
-	minioClient, err := minio.New(endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
-		Secure: false, // Use HTTP for workshop setup
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Minio client: %w", err)
+	if backup.Spec.PackagingMode == backupv1.PackagingModeTarballPerNamespace {
+		tarKey := fmt.Sprintf("%s/namespaces/%s.tar", backupPath, namespace)
+		if err := namespacePack.upload(ctx, provider, bucket, backupPath, tarKey); err != nil {
+			return fmt.Errorf("failed to upload tarball for namespace %s: %w", namespace, err)
+		}
 	}
 
-	return minioClient, nil
+	return nil
 }
 
 // getResourceTypesToBackup determines which resource types to backup based on the source configuration
@@ -368,24 +578,23 @@ func (r *BackupReconciler) getNamespacesToBackup(ctx context.Context, source bac
 	return r.getAllNamespaces(ctx, excludes)
 }
 
-// getAllNamespaces gets all namespaces in the cluster, excluding specified ones
+// getAllNamespaces gets all namespaces in the cluster, excluding those matching any of
+// excludeNamespaces. Each entry may be an exact name or a glob pattern (e.g.
+// "kube-*"), via the same matching filter.Predicate.AllowsNamespace uses elsewhere.
 func (r *BackupReconciler) getAllNamespaces(ctx context.Context, excludeNamespaces []string) ([]string, error) {
 	namespaceList := &corev1.NamespaceList{}
 	if err := r.List(ctx, namespaceList); err != nil {
 		return nil, fmt.Errorf("failed to list namespaces: %w", err)
 	}
 
+	predicate, err := filter.Compile(filter.Policy{ExcludedNamespaces: excludeNamespaces})
+	if err != nil {
+		return nil, fmt.Errorf("invalid excludeNamespaces: %w", err)
+	}
+
 	var namespaces []string
 	for _, ns := range namespaceList.Items {
-		// Skip excluded namespaces
-		excluded := false
-		for _, exclude := range excludeNamespaces {
-			if ns.Name == exclude {
-				excluded = true
-				break
-			}
-		}
-		if !excluded {
+		if predicate.AllowsNamespace(ns.Name) {
 			namespaces = append(namespaces, ns.Name)
 		}
 	}
@@ -398,299 +607,167 @@ func (r *BackupReconciler) getClusterResourceTypes() []string {
 	return []string{"clusterroles", "clusterrolebindings", "persistentvolumes", "storageclasses"}
 }
 
-// calculateNextBackupTime calculates the next backup time based on the cron schedule
+// calculateNextBackupTime calculates the next backup time based on the cron schedule.
+// It delegates to the package-level helper shared with BackupScheduleReconciler.
 func (r *BackupReconciler) calculateNextBackupTime(schedule string) (time.Time, error) {
-	// Parse the cron schedule using robfig/cron library
-	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
-
-	cronSchedule, err := parser.Parse(schedule)
-	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to parse cron schedule '%s': %w", schedule, err)
-	}
-
-	// Calculate next run time from now
-	now := time.Now()
-	nextTime := cronSchedule.Next(now)
-
-	return nextTime, nil
-}
-
-// backupNamespacedResourceType backs up all resources of a specific type in a specific namespace
-func (r *BackupReconciler) backupNamespacedResourceType(ctx context.Context, minioClient *minio.Client, bucket, backupPath, namespace string, source backupv1.BackupSource, resourceType string) (int32, error) {
-	switch resourceType {
-	case "deployments":
-		return r.backupDeployments(ctx, minioClient, bucket, backupPath, namespace, source)
-	case "services":
-		return r.backupServices(ctx, minioClient, bucket, backupPath, namespace, source)
-	case "configmaps":
-		return r.backupConfigMaps(ctx, minioClient, bucket, backupPath, namespace, source)
-	case "secrets":
-		return r.backupSecrets(ctx, minioClient, bucket, backupPath, namespace, source)
-	case "persistentvolumeclaims":
-		return r.backupPersistentVolumeClaims(ctx, minioClient, bucket, backupPath, namespace, source)
-	case "ingresses":
-		return r.backupIngresses(ctx, minioClient, bucket, backupPath, namespace, source)
-	default:
-		return 0, nil
-	}
+	return calculateNextBackupTime(schedule)
 }
 
-// backupClusterResourceType backs up cluster-scoped resources
-func (r *BackupReconciler) backupClusterResourceType(ctx context.Context, minioClient *minio.Client, bucket, backupPath string, source backupv1.BackupSource, resourceType string) (int32, error) {
-	switch resourceType {
-	case "clusterroles":
-		return r.backupClusterRoles(ctx, minioClient, bucket, backupPath, source)
-	case "clusterrolebindings":
-		return r.backupClusterRoleBindings(ctx, minioClient, bucket, backupPath, source)
-	case "persistentvolumes":
-		return r.backupPersistentVolumes(ctx, minioClient, bucket, backupPath, source)
-	case "storageclasses":
-		return r.backupStorageClasses(ctx, minioClient, bucket, backupPath, source)
-	default:
+// backupNamespacedResourceType backs up all resources of a specific type in a specific
+// namespace by delegating to the handler registered in namespacedResourceHandlers.
+// Unrecognized resourceType strings are a no-op, matching the pre-registry behavior.
+// Resources are uploaded concurrently, bounded by boundedParallel, so a namespace with
+// many resources of one type does not upload them one at a time.
+func (r *BackupReconciler) backupNamespacedResourceType(ctx context.Context, provider storage.Provider, bucket, backupPath, namespace string, backup *backupv1.Backup, source backupv1.BackupSource, resourceType string, actions *itemActionState, dedup *dedupState, pack *packagingState) (int32, error) {
+	handler, ok := namespacedResourceHandlers[resourceType]
+	if !ok {
 		return 0, nil
 	}
-}
-
-// backupDeployments backs up all deployments in the specified namespace
-func (r *BackupReconciler) backupDeployments(ctx context.Context, minioClient *minio.Client, bucket, backupPath, namespace string, source backupv1.BackupSource) (int32, error) {
-	deployments := &appsv1.DeploymentList{}
 
-	// Build list options with namespace and label selector
-	listOpts := []client.ListOption{client.InNamespace(namespace)}
-	if source.LabelSelector != nil {
-		selector, err := metav1.LabelSelectorAsSelector(source.LabelSelector)
-		if err != nil {
-			return 0, fmt.Errorf("failed to convert label selector: %w", err)
-		}
-		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: selector})
+	objects, err := handler.List(ctx, r, namespace, source)
+	if err != nil {
+		return 0, err
 	}
-
-	if err := r.List(ctx, deployments, listOpts...); err != nil {
-		return 0, fmt.Errorf("failed to list deployments: %w", err)
+	objects, err = filterBackupObjects(objects, resourceType, source)
+	if err != nil {
+		return 0, err
 	}
 
-	count := int32(0)
-	for _, deployment := range deployments.Items {
-		objectName := fmt.Sprintf("%s/namespaces/%s/deployments/%s.json", backupPath, namespace, deployment.Name)
-		if err := r.uploadResource(ctx, minioClient, bucket, objectName, deployment); err != nil {
-			return 0, fmt.Errorf("failed to backup deployment %s: %w", deployment.Name, err)
+	var statusMu sync.Mutex
+	err = boundedParallel(len(objects), func(i int) error {
+		obj := objects[i]
+		objectName := fmt.Sprintf("%s/namespaces/%s/%s/%s.json", backupPath, namespace, handler.PathPrefix(), obj.GetName())
+		if err := r.uploadResource(ctx, provider, bucket, backupPath, objectName, obj, handler.GVK(), namespace, actions, dedup, pack); err != nil {
+			return fmt.Errorf("failed to backup %s %s: %w", resourceType, obj.GetName(), err)
 		}
-		count++
-	}
-
-	return count, nil
-}
 
-// backupConfigMaps backs up all configmaps in the specified namespace
-func (r *BackupReconciler) backupConfigMaps(ctx context.Context, minioClient *minio.Client, bucket, backupPath, namespace string, source backupv1.BackupSource) (int32, error) {
-	configMaps := &corev1.ConfigMapList{}
+		if resourceType == "persistentvolumeclaims" {
+			pvc, ok := obj.(*corev1.PersistentVolumeClaim)
+			if !ok {
+				return fmt.Errorf("persistentvolumeclaims handler returned unexpected type %T", obj)
+			}
 
-	// Build list options with namespace and label selector
-	listOpts := []client.ListOption{client.InNamespace(namespace)}
-	if source.LabelSelector != nil {
-		selector, err := metav1.LabelSelectorAsSelector(source.LabelSelector)
-		if err != nil {
-			return 0, fmt.Errorf("failed to convert label selector: %w", err)
-		}
-		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: selector})
-	}
+			action, reason, err := r.resolveVolumeAction(ctx, backup, pvc)
+			if err != nil {
+				return fmt.Errorf("failed to resolve volume policy for persistentvolumeclaim %s: %w", pvc.Name, err)
+			}
 
-	if err := r.List(ctx, configMaps, listOpts...); err != nil {
-		return 0, fmt.Errorf("failed to list configmaps: %w", err)
-	}
+			switch action.Type {
+			case backupv1.VolumeActionSnapshot:
+				record, err := r.snapshotPersistentVolumeClaim(ctx, provider, bucket, backupPath, backup, pvc, dedup, pack)
+				if err != nil {
+					return fmt.Errorf("failed to snapshot persistentvolumeclaim %s: %w", pvc.Name, err)
+				}
+				if record != nil {
+					statusMu.Lock()
+					backup.Status.VolumeSnapshots = append(backup.Status.VolumeSnapshots, *record)
+					statusMu.Unlock()
+				}
+			case backupv1.VolumeActionFSBackup:
+				if err := r.fsBackupPersistentVolumeClaim(ctx, provider, bucket, backupPath, pvc); err != nil {
+					return fmt.Errorf("failed to fs-backup persistentvolumeclaim %s: %w", pvc.Name, err)
+				}
+			case backupv1.VolumeActionDataMover:
+				if err := r.dataMoverPersistentVolumeClaim(ctx, provider, bucket, backupPath, pvc); err != nil {
+					return fmt.Errorf("failed to data-mover backup persistentvolumeclaim %s: %w", pvc.Name, err)
+				}
+			}
 
-	count := int32(0)
-	for _, cm := range configMaps.Items {
-		objectName := fmt.Sprintf("%s/namespaces/%s/configmaps/%s.json", backupPath, namespace, cm.Name)
-		if err := r.uploadResource(ctx, minioClient, bucket, objectName, cm); err != nil {
-			return 0, fmt.Errorf("failed to backup configmap %s: %w", cm.Name, err)
+			statusMu.Lock()
+			backup.Status.VolumeBackups = append(backup.Status.VolumeBackups, backupv1.VolumeBackupRecord{
+				PVCName:   pvc.Name,
+				Namespace: pvc.Namespace,
+				Decision:  action.Type,
+				Reason:    reason,
+			})
+			statusMu.Unlock()
 		}
-		count++
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	return count, nil
+	return int32(len(objects)), nil
 }
 
-// backupSecrets backs up all secrets in the specified namespace
-func (r *BackupReconciler) backupSecrets(ctx context.Context, minioClient *minio.Client, bucket, backupPath, namespace string, source backupv1.BackupSource) (int32, error) {
-	secrets := &corev1.SecretList{}
-
-	// Build list options with namespace and label selector
-	listOpts := []client.ListOption{client.InNamespace(namespace)}
-	if source.LabelSelector != nil {
-		selector, err := metav1.LabelSelectorAsSelector(source.LabelSelector)
-		if err != nil {
-			return 0, fmt.Errorf("failed to convert label selector: %w", err)
-		}
-		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: selector})
-	}
-
-	if err := r.List(ctx, secrets, listOpts...); err != nil {
-		return 0, fmt.Errorf("failed to list secrets: %w", err)
-	}
-
-	count := int32(0)
-	for _, secret := range secrets.Items {
-		// Skip service account tokens and other system secrets
-		if secret.Type == corev1.SecretTypeServiceAccountToken ||
-			strings.HasPrefix(secret.Name, "default-token-") ||
-			strings.Contains(secret.Name, "token-") {
-			continue
-		}
-
-		objectName := fmt.Sprintf("%s/namespaces/%s/secrets/%s.json", backupPath, namespace, secret.Name)
-		if err := r.uploadResource(ctx, minioClient, bucket, objectName, secret); err != nil {
-			return 0, fmt.Errorf("failed to backup secret %s: %w", secret.Name, err)
-		}
-		count++
+// backupClusterResourceType backs up all cluster-scoped resources of a specific type by
+// delegating to the handler registered in clusterResourceHandlers. Unrecognized
+// resourceType strings are a no-op, matching the pre-registry behavior. Resources are
+// uploaded concurrently, bounded by boundedParallel.
+func (r *BackupReconciler) backupClusterResourceType(ctx context.Context, provider storage.Provider, bucket, backupPath string, source backupv1.BackupSource, resourceType string, actions *itemActionState, dedup *dedupState, pack *packagingState) (int32, error) {
+	handler, ok := clusterResourceHandlers[resourceType]
+	if !ok {
+		return 0, nil
 	}
 
-	return count, nil
-}
-
-// backupServices backs up all services in the specified namespace
-func (r *BackupReconciler) backupServices(ctx context.Context, minioClient *minio.Client, bucket, backupPath, namespace string, source backupv1.BackupSource) (int32, error) {
-	services := &corev1.ServiceList{}
-
-	// Build list options with namespace and label selector
-	listOpts := []client.ListOption{client.InNamespace(namespace)}
-	if source.LabelSelector != nil {
-		selector, err := metav1.LabelSelectorAsSelector(source.LabelSelector)
-		if err != nil {
-			return 0, fmt.Errorf("failed to convert label selector: %w", err)
-		}
-		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: selector})
+	objects, err := handler.List(ctx, r, "", source)
+	if err != nil {
+		return 0, err
 	}
-
-	if err := r.List(ctx, services, listOpts...); err != nil {
-		return 0, fmt.Errorf("failed to list services: %w", err)
+	objects, err = filterBackupObjects(objects, resourceType, source)
+	if err != nil {
+		return 0, err
 	}
 
-	count := int32(0)
-	for _, service := range services.Items {
-		// Skip default kubernetes service and system services
-		if service.Name == "kubernetes" ||
-			service.Namespace == "kube-system" ||
-			service.Namespace == "kube-public" {
-			continue
+	err = boundedParallel(len(objects), func(i int) error {
+		obj := objects[i]
+		objectName := fmt.Sprintf("%s/cluster/%s/%s.json", backupPath, handler.PathPrefix(), obj.GetName())
+		if err := r.uploadResource(ctx, provider, bucket, backupPath, objectName, obj, handler.GVK(), "", actions, dedup, pack); err != nil {
+			return fmt.Errorf("failed to backup %s %s: %w", resourceType, obj.GetName(), err)
 		}
-
-		objectName := fmt.Sprintf("%s/namespaces/%s/services/%s.json", backupPath, namespace, service.Name)
-		if err := r.uploadResource(ctx, minioClient, bucket, objectName, service); err != nil {
-			return 0, fmt.Errorf("failed to backup service %s: %w", service.Name, err)
-		}
-		count++
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	return count, nil
+	return int32(len(objects)), nil
 }
 
-// backupPersistentVolumeClaims backs up all PVCs in the specified namespace
-func (r *BackupReconciler) backupPersistentVolumeClaims(ctx context.Context, minioClient *minio.Client, bucket, backupPath, namespace string, source backupv1.BackupSource) (int32, error) {
-	pvcs := &corev1.PersistentVolumeClaimList{}
-
-	// Build list options with namespace and label selector
-	listOpts := []client.ListOption{client.InNamespace(namespace)}
-	if source.LabelSelector != nil {
-		selector, err := metav1.LabelSelectorAsSelector(source.LabelSelector)
-		if err != nil {
-			return 0, fmt.Errorf("failed to convert label selector: %w", err)
-		}
-		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: selector})
-	}
-
-	if err := r.List(ctx, pvcs, listOpts...); err != nil {
-		return 0, fmt.Errorf("failed to list persistentvolumeclaims: %w", err)
+// uploadResource serializes a Kubernetes resource to JSON, runs any configured
+// ItemActions against it (see itemActionState.apply), and gets the result into
+// storage. When pack is non-nil (PackagingMode is TarballPerNamespace or
+// SingleTarball), the resource is instead accumulated for that tarball; see
+// packagingState.add. Otherwise, when dedup is non-nil (BackupSource.Deduplicate is
+// enabled), the object is written as a content-addressed chunk and recorded in the
+// dedup manifest; see dedupState.store. pack and dedup are never both non-nil:
+// performBackup rejects that combination. gvk and namespace identify resource for
+// ItemAction.Applies; namespace is empty for cluster-scoped resources. backupPath is
+// only used to place any additional items an ItemAction asks to back up alongside
+// resource.
+func (r *BackupReconciler) uploadResource(ctx context.Context, provider storage.Provider, bucket, backupPath, objectName string, resource interface{}, gvk schema.GroupVersionKind, namespace string, actions *itemActionState, dedup *dedupState, pack *packagingState) error {
+	// Serialize resource to JSON
+	jsonData, err := json.MarshalIndent(resource, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal resource to JSON: %w", err)
 	}
 
-	count := int32(0)
-	for _, pvc := range pvcs.Items {
-		objectName := fmt.Sprintf("%s/namespaces/%s/persistentvolumeclaims/%s.json", backupPath, namespace, pvc.Name)
-		if err := r.uploadResource(ctx, minioClient, bucket, objectName, pvc); err != nil {
-			return 0, fmt.Errorf("failed to backup persistentvolumeclaim %s: %w", pvc.Name, err)
-		}
-		count++
+	transformed, additionalItems, skip, err := actions.apply(gvk.Group, gvk.Kind, namespace, jsonData)
+	if err != nil {
+		return fmt.Errorf("failed to run item actions on %s: %w", objectName, err)
 	}
-
-	return count, nil
-}
-
-// backupIngresses backs up all ingresses in the specified namespace
-func (r *BackupReconciler) backupIngresses(ctx context.Context, minioClient *minio.Client, bucket, backupPath, namespace string, source backupv1.BackupSource) (int32, error) {
-	// Note: Using unversioned client for ingresses as they might be in different API versions
-	// For simplicity in the workshop, we'll skip ingresses if they're not available
-	// In a real implementation, you'd handle multiple API versions
-
-	// For now, return 0 count as ingresses require more complex API version handling
-	return 0, nil
-}
-
-// backupClusterRoles backs up all cluster roles
-func (r *BackupReconciler) backupClusterRoles(ctx context.Context, minioClient *minio.Client, bucket, backupPath string, source backupv1.BackupSource) (int32, error) {
-	// For workshop simplicity, we'll skip cluster roles
-	// In a real implementation, you'd need to import rbacv1 and implement this
-	return 0, nil
-}
-
-// backupClusterRoleBindings backs up all cluster role bindings
-func (r *BackupReconciler) backupClusterRoleBindings(ctx context.Context, minioClient *minio.Client, bucket, backupPath string, source backupv1.BackupSource) (int32, error) {
-	// For workshop simplicity, we'll skip cluster role bindings
-	// In a real implementation, you'd need to import rbacv1 and implement this
-	return 0, nil
-}
-
-// backupPersistentVolumes backs up all persistent volumes
-func (r *BackupReconciler) backupPersistentVolumes(ctx context.Context, minioClient *minio.Client, bucket, backupPath string, source backupv1.BackupSource) (int32, error) {
-	pvs := &corev1.PersistentVolumeList{}
-
-	// Build list options with label selector (no namespace for cluster resources)
-	var listOpts []client.ListOption
-	if source.LabelSelector != nil {
-		selector, err := metav1.LabelSelectorAsSelector(source.LabelSelector)
-		if err != nil {
-			return 0, fmt.Errorf("failed to convert label selector: %w", err)
-		}
-		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: selector})
+	if err := r.backupAdditionalItems(ctx, provider, bucket, backupPath, additionalItems); err != nil {
+		return err
 	}
-
-	if err := r.List(ctx, pvs, listOpts...); err != nil {
-		return 0, fmt.Errorf("failed to list persistentvolumes: %w", err)
+	if skip {
+		return nil
 	}
+	jsonData = transformed
 
-	count := int32(0)
-	for _, pv := range pvs.Items {
-		objectName := fmt.Sprintf("%s/cluster/persistentvolumes/%s.json", backupPath, pv.Name)
-		if err := r.uploadResource(ctx, minioClient, bucket, objectName, pv); err != nil {
-			return 0, fmt.Errorf("failed to backup persistentvolume %s: %w", pv.Name, err)
-		}
-		count++
+	if pack != nil {
+		pack.add(objectName, jsonData)
+		return nil
 	}
 
-	return count, nil
-}
-
-// backupStorageClasses backs up all storage classes
-func (r *BackupReconciler) backupStorageClasses(ctx context.Context, minioClient *minio.Client, bucket, backupPath string, source backupv1.BackupSource) (int32, error) {
-	// For workshop simplicity, we'll skip storage classes
-	// In a real implementation, you'd need to import storagev1 and implement this
-	return 0, nil
-}
-
-// uploadResource serializes a Kubernetes resource to JSON and uploads it to Minio
-func (r *BackupReconciler) uploadResource(ctx context.Context, minioClient *minio.Client, bucket, objectName string, resource interface{}) error {
-	// Serialize resource to JSON
-	jsonData, err := json.MarshalIndent(resource, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal resource to JSON: %w", err)
+	if dedup != nil {
+		return dedup.store(ctx, provider, bucket, objectName, jsonData)
 	}
 
-	// Upload to Minio
+	// Upload to the storage backend
 	reader := bytes.NewReader(jsonData)
-	_, err = minioClient.PutObject(ctx, bucket, objectName, reader, int64(len(jsonData)), minio.PutObjectOptions{
-		ContentType: "application/json",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to upload object to Minio: %w", err)
+	if err := provider.PutObject(ctx, bucket, objectName, reader, int64(len(jsonData))); err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
 	}
 
 	return nil
@@ -698,6 +775,13 @@ func (r *BackupReconciler) uploadResource(ctx context.Context, minioClient *mini
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *BackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.RestConfig == nil {
+		r.RestConfig = mgr.GetConfig()
+	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("backup-controller")
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&backupv1.Backup{}).
 		Named("backup").
@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+)
+
+// parseIncludeCRDRef parses a BackupSource.IncludeCRDs entry of the form
+// "group/version/Kind" into a GroupVersionKind. Core resources use an empty group,
+// e.g. "/v1/ConfigMap".
+func parseIncludeCRDRef(ref string) (schema.GroupVersionKind, error) {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 3 {
+		return schema.GroupVersionKind{}, fmt.Errorf("expected \"group/version/Kind\" (core resources use an empty group, e.g. \"/v1/ConfigMap\"), got %q", ref)
+	}
+	group, version, kind := parts[0], parts[1], parts[2]
+	if version == "" || kind == "" {
+		return schema.GroupVersionKind{}, fmt.Errorf("expected \"group/version/Kind\" (core resources use an empty group, e.g. \"/v1/ConfigMap\"), got %q", ref)
+	}
+	return schema.GroupVersionKind{Group: group, Version: version, Kind: kind}, nil
+}
+
+// restMapper builds a REST mapper from live discovery data, so IncludeCRDs entries
+// can be resolved to a GroupVersionResource and a namespaced/cluster scope without the
+// caller needing to know the plural resource name up front.
+func (r *BackupReconciler) restMapper() (meta.RESTMapper, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(r.RestConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	groupResources, err := restmapper.GetAPIGroupResources(discoveryClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch API group resources: %w", err)
+	}
+	return restmapper.NewDiscoveryRESTMapper(groupResources), nil
+}
+
+// backupIncludedCRD resolves ref to a listable resource via discovery and backs it up
+// through the dynamic client. Namespace-scoped kinds are backed up once per namespace
+// in namespaces; cluster-scoped kinds are backed up once. Returned keys mirror the
+// "<namespace>/<resourceType>" and "cluster/<resourceType>" keys used by the typed
+// resource handlers, with resourceType set to the lowercased plural resource name.
+func (r *BackupReconciler) backupIncludedCRD(ctx context.Context, provider storage.Provider, bucket, backupPath string, namespaces []string, source backupv1.BackupSource, ref string, actions *itemActionState, dedup *dedupState, pack *packagingState) (map[string]int32, error) {
+	gvk, err := parseIncludeCRDRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	mapper, err := r.restMapper()
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %q: %w", ref, err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(r.RestConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	resourceType := mapping.Resource.Resource
+	counts := make(map[string]int32)
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		count, err := r.backupCRDList(ctx, dynamicClient, mapping.Resource, mapping.GroupVersionKind, "", provider, bucket, backupPath, fmt.Sprintf("%s/cluster/%s", backupPath, resourceType), source, actions, dedup, pack)
+		if err != nil {
+			return nil, fmt.Errorf("failed to backup %s: %w", resourceType, err)
+		}
+		counts[fmt.Sprintf("cluster/%s", resourceType)] = count
+		return counts, nil
+	}
+
+	for _, namespace := range namespaces {
+		count, err := r.backupCRDList(ctx, dynamicClient, mapping.Resource, mapping.GroupVersionKind, namespace, provider, bucket, backupPath, fmt.Sprintf("%s/namespaces/%s/%s", backupPath, namespace, resourceType), source, actions, dedup, pack)
+		if err != nil {
+			return nil, fmt.Errorf("failed to backup %s in namespace %s: %w", resourceType, namespace, err)
+		}
+		counts[fmt.Sprintf("%s/%s", namespace, resourceType)] = count
+	}
+	return counts, nil
+}
+
+// backupCRDList lists every object of gvr (scoped to namespace, when set) and uploads
+// each one as JSON under objectPathPrefix, concurrently and bounded by boundedParallel.
+func (r *BackupReconciler) backupCRDList(ctx context.Context, dynamicClient dynamic.Interface, gvr schema.GroupVersionResource, gvk schema.GroupVersionKind, namespace string, provider storage.Provider, bucket, backupPath, objectPathPrefix string, source backupv1.BackupSource, actions *itemActionState, dedup *dedupState, pack *packagingState) (int32, error) {
+	var listOpts metav1.ListOptions
+	if source.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(source.LabelSelector)
+		if err != nil {
+			return 0, fmt.Errorf("failed to convert label selector: %w", err)
+		}
+		listOpts.LabelSelector = selector.String()
+	}
+
+	var resourceClient dynamic.ResourceInterface = dynamicClient.Resource(gvr)
+	if namespace != "" {
+		resourceClient = dynamicClient.Resource(gvr).Namespace(namespace)
+	}
+
+	list, err := resourceClient.List(ctx, listOpts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list %s: %w", gvr.Resource, err)
+	}
+
+	err = boundedParallel(len(list.Items), func(i int) error {
+		item := list.Items[i]
+		objectName := fmt.Sprintf("%s/%s.json", objectPathPrefix, item.GetName())
+		if err := r.uploadResource(ctx, provider, bucket, backupPath, objectName, item.Object, gvk, namespace, actions, dedup, pack); err != nil {
+			return fmt.Errorf("failed to backup %s %s: %w", gvr.Resource, item.GetName(), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(len(list.Items)), nil
+}
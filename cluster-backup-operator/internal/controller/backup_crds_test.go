@@ -0,0 +1,28 @@
+package controller
+
+import "testing"
+
+// TestParseIncludeCRDRef covers both namespaced and core-group references.
+func TestParseIncludeCRDRef(t *testing.T) {
+	gvk, err := parseIncludeCRDRef("cert-manager.io/v1/Certificate")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gvk.Group != "cert-manager.io" || gvk.Version != "v1" || gvk.Kind != "Certificate" {
+		t.Errorf("unexpected GVK: %+v", gvk)
+	}
+
+	gvk, err = parseIncludeCRDRef("/v1/ConfigMap")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gvk.Group != "" || gvk.Version != "v1" || gvk.Kind != "ConfigMap" {
+		t.Errorf("unexpected GVK for core resource: %+v", gvk)
+	}
+
+	for _, invalid := range []string{"v1/ConfigMap", "cert-manager.io/v1", "", "a/b/c/d"} {
+		if _, err := parseIncludeCRDRef(invalid); err == nil {
+			t.Errorf("expected error parsing %q, got nil", invalid)
+		}
+	}
+}
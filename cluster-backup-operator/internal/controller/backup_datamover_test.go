@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// TestStoreVolumeChunkReusesIdenticalChunks verifies that storing the same chunk bytes
+// twice (e.g. the same file content appearing in two different PVCs' backups) writes
+// the chunk once, the bucket-wide deduplication data-mover is meant to provide.
+func TestStoreVolumeChunkReusesIdenticalChunks(t *testing.T) {
+	ctx := context.Background()
+	provider := newLocalProviderForTest(t)
+	bucket := "test-bucket"
+	if err := provider.EnsureBucket(ctx, bucket); err != nil {
+		t.Fatalf("failed to ensure bucket: %v", err)
+	}
+
+	data := []byte("identical file contents shared across two pvcs")
+
+	hash1, err := storeVolumeChunk(ctx, provider, bucket, data)
+	if err != nil {
+		t.Fatalf("unexpected error storing first chunk: %v", err)
+	}
+
+	hash2, err := storeVolumeChunk(ctx, provider, bucket, data)
+	if err != nil {
+		t.Fatalf("unexpected error storing second chunk: %v", err)
+	}
+
+	if hash1 != hash2 {
+		t.Fatalf("expected identical data to hash to the same chunk, got %q and %q", hash1, hash2)
+	}
+
+	keys, err := provider.ListObjects(ctx, bucket, volumeChunksPrefix+"/")
+	if err != nil {
+		t.Fatalf("failed to list chunks: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("expected exactly 1 chunk object after storing identical data twice, got %d: %v", len(keys), keys)
+	}
+}
+
+// TestDataMoverPersistentVolumeClaimRoundTrip runs the same io.ReadFull chunking loop
+// dataMoverPersistentVolumeClaim uses, storing each chunk with storeVolumeChunk, over a
+// fake tar stream larger than one chunk (dataMoverPersistentVolumeClaim itself is not
+// called), then verifies copyDataMoverChunks reassembles the exact original bytes from
+// the manifest built along the way.
+func TestDataMoverPersistentVolumeClaimRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	provider := newLocalProviderForTest(t)
+	bucket := "test-bucket"
+	if err := provider.EnsureBucket(ctx, bucket); err != nil {
+		t.Fatalf("failed to ensure bucket: %v", err)
+	}
+
+	// Build fake "tar stream" data spanning more than one dataMoverChunkSize-sized
+	// chunk, so the chunking loop exercises its multi-chunk path.
+	data := bytes.Repeat([]byte("x"), dataMoverChunkSize+17)
+
+	manifest := dataMoverManifest{PVCName: "app-data", Namespace: "default"}
+	reader := bytes.NewReader(data)
+	buf := make([]byte, dataMoverChunkSize)
+	for {
+		n, readErr := io.ReadFull(reader, buf)
+		if n > 0 {
+			hash, err := storeVolumeChunk(ctx, provider, bucket, buf[:n])
+			if err != nil {
+				t.Fatalf("unexpected error storing chunk: %v", err)
+			}
+			manifest.Chunks = append(manifest.Chunks, hash)
+			manifest.TotalSize += int64(n)
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			t.Fatalf("unexpected read error: %v", readErr)
+		}
+	}
+
+	if len(manifest.Chunks) != 2 {
+		t.Fatalf("expected data spanning 2 chunks to produce 2 manifest entries, got %d", len(manifest.Chunks))
+	}
+	if manifest.TotalSize != int64(len(data)) {
+		t.Fatalf("expected manifest TotalSize %d, got %d", len(data), manifest.TotalSize)
+	}
+
+	reassembled := new(bytes.Buffer)
+	if err := copyDataMoverChunks(ctx, provider, bucket, &manifest, reassembled); err != nil {
+		t.Fatalf("failed to reassemble chunks: %v", err)
+	}
+
+	if !bytes.Equal(reassembled.Bytes(), data) {
+		t.Error("expected reassembled data to match the original byte stream exactly")
+	}
+}
+
+// TestLoadDataMoverManifestMissing verifies a PVC with no data-mover manifest (it was
+// backed up with a different VolumeAction, or not at all) returns (nil, nil) rather
+// than an error, so restoreDataMoverPVC can treat it as a no-op.
+func TestLoadDataMoverManifestMissing(t *testing.T) {
+	ctx := context.Background()
+	provider := newLocalProviderForTest(t)
+	bucket := "test-bucket"
+	if err := provider.EnsureBucket(ctx, bucket); err != nil {
+		t.Fatalf("failed to ensure bucket: %v", err)
+	}
+
+	manifest, err := loadDataMoverManifest(ctx, provider, bucket, "backups/cluster-backup/t1", "default", "no-such-pvc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manifest != nil {
+		t.Error("expected a nil manifest for a pvc with no data-mover backup")
+	}
+}
+
+// TestDataMoverRestoreEnabled verifies the RestoreDataMoverVolumes toggle, mirroring
+// volumeRestoreEnabled's own nil-safety tests.
+func TestDataMoverRestoreEnabled(t *testing.T) {
+	enabled := true
+	disabled := false
+
+	cases := []struct {
+		name     string
+		restore  *backupv1.Restore
+		expected bool
+	}{
+		{name: "nil VolumeRestore", restore: &backupv1.Restore{}, expected: false},
+		{
+			name:     "nil RestoreDataMoverVolumes",
+			restore:  &backupv1.Restore{Spec: backupv1.RestoreSpec{VolumeRestore: &backupv1.VolumeRestoreOptions{}}},
+			expected: false,
+		},
+		{
+			name:     "explicitly disabled",
+			restore:  &backupv1.Restore{Spec: backupv1.RestoreSpec{VolumeRestore: &backupv1.VolumeRestoreOptions{RestoreDataMoverVolumes: &disabled}}},
+			expected: false,
+		},
+		{
+			name:     "enabled",
+			restore:  &backupv1.Restore{Spec: backupv1.RestoreSpec{VolumeRestore: &backupv1.VolumeRestoreOptions{RestoreDataMoverVolumes: &enabled}}},
+			expected: true,
+		},
+	}
+
+	for _, tc := range cases {
+		if got := dataMoverRestoreEnabled(tc.restore); got != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+)
+
+// dedupChunksPrefix is the stable, non-timestamped prefix chunk objects are stored
+// under, so identical resource content is reused across every backup run sharing a
+// bucket rather than just within one. It lives under backupRootPrefix but is not
+// itself a backup; backupNamesFromKeys excludes its "chunks" segment explicitly.
+const dedupChunksPrefix = backupRootPrefix + "/chunks"
+
+// dedupManifestName is the object written under a backup's own (timestamped) path in
+// place of one object per resource when BackupSource.Deduplicate is enabled.
+const dedupManifestName = "manifest.json"
+
+// dedupManifest maps a resource's normal per-backup object key to the sha256 hex
+// digest of the chunk holding its content.
+type dedupManifest struct {
+	Resources map[string]string `json:"resources"`
+}
+
+// dedupState accumulates the manifest and DeduplicationStats for a single backup run.
+// A nil *dedupState means BackupSource.Deduplicate is disabled and uploadResource
+// falls back to writing one object per resource, as before this feature existed.
+//
+// store serializes the whole check-existing/upload/record sequence behind mu so that
+// boundedParallel's concurrent uploadResource calls can share one dedupState safely.
+type dedupState struct {
+	mu       sync.Mutex
+	manifest map[string]string
+	stats    backupv1.DeduplicationStats
+}
+
+// newDedupState returns an empty dedupState ready to accumulate one backup run.
+func newDedupState() *dedupState {
+	return &dedupState{manifest: make(map[string]string)}
+}
+
+// chunkObjectKey returns the content-addressed object key for a chunk with the given
+// sha256 hex digest, sharded by its first two characters so no single storage
+// "directory" ends up holding every chunk the cluster has ever produced.
+func chunkObjectKey(hash string) string {
+	return fmt.Sprintf("%s/%s/%s.json", dedupChunksPrefix, hash[:2], hash)
+}
+
+// store uploads jsonData as a content-addressed chunk, skipping the upload if an
+// identical chunk already exists from a previous backup, and records objectName's
+// mapping to that chunk's hash in the manifest.
+func (d *dedupState) store(ctx context.Context, provider storage.Provider, bucket, objectName string, jsonData []byte) error {
+	sum := sha256.Sum256(jsonData)
+	hash := hex.EncodeToString(sum[:])
+	chunkKey := chunkObjectKey(hash)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.stats.TotalResources++
+	d.manifest[objectName] = hash
+
+	existing, err := provider.ListObjects(ctx, bucket, chunkKey)
+	if err != nil {
+		return fmt.Errorf("failed to check for existing chunk %s: %w", chunkKey, err)
+	}
+	for _, key := range existing {
+		if key == chunkKey {
+			d.stats.ChunksReused++
+			d.stats.BytesSaved += int64(len(jsonData))
+			return nil
+		}
+	}
+
+	if err := provider.PutObject(ctx, bucket, chunkKey, bytes.NewReader(jsonData), int64(len(jsonData))); err != nil {
+		return fmt.Errorf("failed to upload chunk %s: %w", chunkKey, err)
+	}
+	d.stats.ChunksWritten++
+	d.stats.BytesWritten += int64(len(jsonData))
+	return nil
+}
+
+// finalize uploads the accumulated manifest to "<backupPath>/manifest.json" and
+// returns the run's DeduplicationStats for Backup.Status.DedupStats.
+func (d *dedupState) finalize(ctx context.Context, provider storage.Provider, bucket, backupPath string) (*backupv1.DeduplicationStats, error) {
+	data, err := json.MarshalIndent(dedupManifest{Resources: d.manifest}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal dedup manifest: %w", err)
+	}
+
+	manifestKey := fmt.Sprintf("%s/%s", backupPath, dedupManifestName)
+	if err := provider.PutObject(ctx, bucket, manifestKey, bytes.NewReader(data), int64(len(data))); err != nil {
+		return nil, fmt.Errorf("failed to upload dedup manifest: %w", err)
+	}
+
+	stats := d.stats
+	return &stats, nil
+}
+
+// loadDedupManifest fetches and parses "<backupPath>/manifest.json", when present, so
+// RestoreReconciler can resolve each resource's object key to its content-addressed
+// chunk instead of looking for a per-resource object that Deduplicate never wrote.
+func loadDedupManifest(ctx context.Context, provider storage.Provider, bucket, backupPath string) (map[string]string, error) {
+	manifestKey := fmt.Sprintf("%s/%s", backupPath, dedupManifestName)
+
+	reader, err := provider.GetObject(ctx, bucket, manifestKey)
+	if err != nil {
+		return nil, nil
+	}
+	defer reader.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(reader); err != nil {
+		return nil, fmt.Errorf("failed to read dedup manifest: %w", err)
+	}
+
+	var manifest dedupManifest
+	if err := json.Unmarshal(buf.Bytes(), &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse dedup manifest: %w", err)
+	}
+
+	return manifest.Resources, nil
+}
+
+// DedupGCStats summarizes one GCDedupChunks pass.
+type DedupGCStats struct {
+	ChunksScanned  int
+	ChunksDeleted  int
+	BytesReclaimed int64
+}
+
+// chunkHashFromKey extracts the sha256 hex digest chunkObjectKey encoded into key, or
+// "" if key is not a chunk object key (e.g. it belongs to some other prefix a future
+// ListObjects call under dedupChunksPrefix might also happen to return).
+func chunkHashFromKey(key string) string {
+	name := key
+	if idx := strings.LastIndex(key, "/"); idx >= 0 {
+		name = key[idx+1:]
+	}
+	return strings.TrimSuffix(name, ".json")
+}
+
+// GCDedupChunks deletes every chunk under dedupChunksPrefix that is not referenced by
+// the manifest.json of any backup path in retainBackupPaths (typically every path
+// enforceRetention's policy decided to keep). Chunks are shared bucket-wide across
+// every Deduplicate-enabled backup, so this must only be called with the full set of
+// surviving backup paths, not just the one(s) a single retention pass pruned -
+// otherwise a chunk still referenced by a backup outside retainBackupPaths would be
+// deleted out from under it.
+//
+// This is intentionally not wired into the automatic retention/deletion reconcile
+// loops: a backup run in progress writes chunks before it finalizes its manifest, and
+// running GC concurrently with that window could delete a chunk moments before it
+// becomes referenced. Callers (an ops script, a future scheduled job, etc.) are
+// expected to run this when no Deduplicate-enabled backup is currently uploading.
+func GCDedupChunks(ctx context.Context, provider storage.Provider, bucket string, retainBackupPaths []string) (*DedupGCStats, error) {
+	reachable := make(map[string]bool)
+	for _, path := range retainBackupPaths {
+		manifest, err := loadDedupManifest(ctx, provider, bucket, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load manifest for %s: %w", path, err)
+		}
+		for _, hash := range manifest {
+			reachable[hash] = true
+		}
+	}
+
+	keys, err := provider.ListObjects(ctx, bucket, dedupChunksPrefix+"/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dedup chunks: %w", err)
+	}
+
+	stats := &DedupGCStats{ChunksScanned: len(keys)}
+	for _, key := range keys {
+		if reachable[chunkHashFromKey(key)] {
+			continue
+		}
+
+		info, err := provider.StatObject(ctx, bucket, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat unreferenced chunk %s: %w", key, err)
+		}
+		if err := provider.DeleteObject(ctx, bucket, key); err != nil {
+			return nil, fmt.Errorf("failed to delete unreferenced chunk %s: %w", key, err)
+		}
+		stats.ChunksDeleted++
+		stats.BytesReclaimed += info.Size
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+)
+
+func newLocalProviderForTest(t *testing.T) storage.Provider {
+	t.Helper()
+	provider, err := storage.NewLocalProvider(backupv1.StorageLocation{Endpoint: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create local provider: %v", err)
+	}
+	return provider
+}
+
+// TestDedupStateReusesIdenticalChunks verifies that storing the same resource content
+// twice (e.g. across two backup runs) writes the chunk once and reports the second
+// store as a reuse.
+func TestDedupStateReusesIdenticalChunks(t *testing.T) {
+	ctx := context.Background()
+	provider := newLocalProviderForTest(t)
+	bucket := "test-bucket"
+	if err := provider.EnsureBucket(ctx, bucket); err != nil {
+		t.Fatalf("failed to ensure bucket: %v", err)
+	}
+
+	first := newDedupState()
+	if err := first.store(ctx, provider, bucket, "backups/cluster-backup/t1/namespaces/default/configmaps/app.json", []byte(`{"data":"v1"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.stats.ChunksWritten != 1 || first.stats.ChunksReused != 0 {
+		t.Errorf("expected 1 chunk written, 0 reused, got %+v", first.stats)
+	}
+
+	second := newDedupState()
+	if err := second.store(ctx, provider, bucket, "backups/cluster-backup/t2/namespaces/default/configmaps/app.json", []byte(`{"data":"v1"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.stats.ChunksWritten != 0 || second.stats.ChunksReused != 1 {
+		t.Errorf("expected 0 chunks written, 1 reused, got %+v", second.stats)
+	}
+
+	if _, err := first.finalize(ctx, provider, bucket, "backups/cluster-backup/t1"); err != nil {
+		t.Fatalf("failed to finalize manifest: %v", err)
+	}
+
+	manifest, err := loadDedupManifest(ctx, provider, bucket, "backups/cluster-backup/t1")
+	if err != nil {
+		t.Fatalf("failed to load manifest: %v", err)
+	}
+	if len(manifest) != 1 {
+		t.Fatalf("expected 1 manifest entry, got %d", len(manifest))
+	}
+}
+
+// TestLoadDedupManifestMissing verifies a backup written without Deduplicate (no
+// manifest.json present) is reported as "no manifest" rather than an error, so
+// performRestore falls back to its normal per-object listing.
+func TestLoadDedupManifestMissing(t *testing.T) {
+	ctx := context.Background()
+	provider := newLocalProviderForTest(t)
+	bucket := "test-bucket"
+	if err := provider.EnsureBucket(ctx, bucket); err != nil {
+		t.Fatalf("failed to ensure bucket: %v", err)
+	}
+
+	manifest, err := loadDedupManifest(ctx, provider, bucket, "backups/cluster-backup/no-manifest")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manifest != nil {
+		t.Errorf("expected nil manifest when none was written, got %+v", manifest)
+	}
+}
+
+// TestDedupStateBlobCountGrowsByOneForChangedResource backs up the same two-resource
+// namespace twice, changing one resource's content between runs, and verifies the
+// bucket's chunk count grows by exactly one: the changed resource produces a new
+// chunk, while the unchanged one reuses the first run's.
+func TestDedupStateBlobCountGrowsByOneForChangedResource(t *testing.T) {
+	ctx := context.Background()
+	provider := newLocalProviderForTest(t)
+	bucket := "test-bucket"
+	if err := provider.EnsureBucket(ctx, bucket); err != nil {
+		t.Fatalf("failed to ensure bucket: %v", err)
+	}
+
+	run1 := newDedupState()
+	if err := run1.store(ctx, provider, bucket, "backups/cluster-backup/t1/namespaces/default/configmaps/unchanged.json", []byte(`{"data":"stable"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := run1.store(ctx, provider, bucket, "backups/cluster-backup/t1/namespaces/default/configmaps/changed.json", []byte(`{"data":"v1"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := run1.finalize(ctx, provider, bucket, "backups/cluster-backup/t1"); err != nil {
+		t.Fatalf("failed to finalize run1 manifest: %v", err)
+	}
+
+	keysAfterRun1, err := provider.ListObjects(ctx, bucket, dedupChunksPrefix+"/")
+	if err != nil {
+		t.Fatalf("failed to list chunks after run1: %v", err)
+	}
+
+	run2 := newDedupState()
+	if err := run2.store(ctx, provider, bucket, "backups/cluster-backup/t2/namespaces/default/configmaps/unchanged.json", []byte(`{"data":"stable"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := run2.store(ctx, provider, bucket, "backups/cluster-backup/t2/namespaces/default/configmaps/changed.json", []byte(`{"data":"v2"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := run2.finalize(ctx, provider, bucket, "backups/cluster-backup/t2"); err != nil {
+		t.Fatalf("failed to finalize run2 manifest: %v", err)
+	}
+
+	keysAfterRun2, err := provider.ListObjects(ctx, bucket, dedupChunksPrefix+"/")
+	if err != nil {
+		t.Fatalf("failed to list chunks after run2: %v", err)
+	}
+
+	if got, want := len(keysAfterRun2), len(keysAfterRun1)+1; got != want {
+		t.Errorf("expected chunk count to grow by exactly 1 (from %d to %d), got %d", len(keysAfterRun1), want, got)
+	}
+}
+
+// TestGCDedupChunksDeletesOnlyUnreferencedChunks verifies that a chunk still
+// referenced by a retained backup's manifest survives GC, while a chunk that only
+// belonged to a pruned backup (not in retainBackupPaths) is deleted.
+func TestGCDedupChunksDeletesOnlyUnreferencedChunks(t *testing.T) {
+	ctx := context.Background()
+	provider := newLocalProviderForTest(t)
+	bucket := "test-bucket"
+	if err := provider.EnsureBucket(ctx, bucket); err != nil {
+		t.Fatalf("failed to ensure bucket: %v", err)
+	}
+
+	kept := newDedupState()
+	if err := kept.store(ctx, provider, bucket, "backups/cluster-backup/kept/namespaces/default/configmaps/shared.json", []byte(`{"data":"shared"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := kept.finalize(ctx, provider, bucket, "backups/cluster-backup/kept"); err != nil {
+		t.Fatalf("failed to finalize kept manifest: %v", err)
+	}
+
+	pruned := newDedupState()
+	if err := pruned.store(ctx, provider, bucket, "backups/cluster-backup/pruned/namespaces/default/configmaps/orphan.json", []byte(`{"data":"orphan"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// pruned's manifest is deliberately never finalized/retained, simulating a backup
+	// whose path retention already deleted.
+
+	stats, err := GCDedupChunks(ctx, provider, bucket, []string{"backups/cluster-backup/kept"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.ChunksScanned != 2 {
+		t.Errorf("expected 2 chunks scanned, got %d", stats.ChunksScanned)
+	}
+	if stats.ChunksDeleted != 1 {
+		t.Errorf("expected 1 chunk deleted, got %d", stats.ChunksDeleted)
+	}
+	if stats.BytesReclaimed == 0 {
+		t.Error("expected a non-zero BytesReclaimed for the deleted chunk")
+	}
+
+	keys, err := provider.ListObjects(ctx, bucket, dedupChunksPrefix+"/")
+	if err != nil {
+		t.Fatalf("failed to list remaining chunks: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("expected 1 chunk to remain, got %d: %v", len(keys), keys)
+	}
+}
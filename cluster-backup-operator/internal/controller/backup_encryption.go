@@ -0,0 +1,262 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"filippo.io/age"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// dataKeySize is the AES-256 data encryption key length, in bytes.
+	dataKeySize = 32
+
+	// gcmNonceSize is the nonce length crypto/cipher's standard GCM construction uses,
+	// recorded on BackupEncryptionStatus so a restore knows how many leading bytes of
+	// the ciphertext are the nonce rather than sealed data.
+	gcmNonceSize = 12
+
+	defaultKeySecretField        = "key"
+	defaultRecipientsSecretField = "recipients"
+)
+
+// generateBackupKey returns a random 32-byte AES-256 data encryption key (DEK),
+// unique to a single backup run. Wrapping this key under the configured KEK, rather
+// than encrypting the tarball directly with the KEK, lets the KEK be rotated or
+// shared across multiple age recipients without re-encrypting old backups.
+func generateBackupKey() ([]byte, error) {
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate data encryption key: %w", err)
+	}
+	return key, nil
+}
+
+// encryptManifest seals plaintext with AES-256-GCM under dek, prepending the random
+// nonce to the returned ciphertext so decryptManifest can recover it without a
+// separate field.
+func encryptManifest(plaintext, dek []byte) ([]byte, error) {
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// decryptManifest reverses encryptManifest. A GCM authentication failure - whether
+// from the wrong key or a tampered ciphertext - surfaces here as an error, which
+// callers should treat as equally fatal to the backup being unreadable.
+func decryptManifest(ciphertext, dek []byte) ([]byte, error) {
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("ciphertext shorter than nonce size %d", nonceSize)
+	}
+	nonce, sealed := ciphertext[:nonceSize], ciphertext[nonceSize:]
+
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt manifest, possible tampering: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize AES cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize AES-GCM: %w", err)
+	}
+	return gcm, nil
+}
+
+// keyFingerprint returns a non-reversible identifier for a wrapped key, so Status can
+// record which KEK/recipient a backup was encrypted for without exposing key material.
+func keyFingerprint(wrapped []byte) string {
+	sum := sha256.Sum256(wrapped)
+	return hex.EncodeToString(sum[:])
+}
+
+// wrapDataKey wraps dek under the KEK selected by cfg, returning the wrapped bytes
+// ready to be uploaded as dek.wrapped.
+func (r *BackupReconciler) wrapDataKey(ctx context.Context, defaultNamespace string, cfg *backupv1.BackupEncryptionConfig, dek []byte) ([]byte, error) {
+	switch cfg.Provider {
+	case backupv1.BackupEncryptionProviderAESGCM:
+		kek, err := r.resolveEncryptionSecretField(ctx, defaultNamespace, cfg.KeySecretRef, defaultKeySecretField)
+		if err != nil {
+			return nil, err
+		}
+		return encryptManifest(dek, kek)
+
+	case backupv1.BackupEncryptionProviderAge:
+		return r.wrapDataKeyAge(ctx, defaultNamespace, cfg, dek)
+
+	default:
+		return nil, fmt.Errorf("unsupported backup encryption provider %q", cfg.Provider)
+	}
+}
+
+// wrapDataKeyAge wraps dek for every age recipient listed (one per line) in
+// KeySecretRef's "recipients" field, so any one of their matching identities can
+// later unwrap it - useful for sharing or rotating access across multiple holders.
+func (r *BackupReconciler) wrapDataKeyAge(ctx context.Context, defaultNamespace string, cfg *backupv1.BackupEncryptionConfig, dek []byte) ([]byte, error) {
+	raw, err := r.resolveEncryptionSecretField(ctx, defaultNamespace, cfg.KeySecretRef, defaultRecipientsSecretField)
+	if err != nil {
+		return nil, err
+	}
+
+	var recipients []age.Recipient
+	for _, line := range strings.Split(strings.TrimSpace(string(raw)), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		recipient, err := age.ParseX25519Recipient(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid age recipient: %w", err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no age recipients configured in secret %s/%s", defaultNamespace, cfg.KeySecretRef.Name)
+	}
+
+	var wrapped bytes.Buffer
+	w, err := age.Encrypt(&wrapped, recipients...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize age encryption: %w", err)
+	}
+	if _, err := w.Write(dek); err != nil {
+		return nil, fmt.Errorf("failed to wrap data key with age: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize age-wrapped data key: %w", err)
+	}
+	return wrapped.Bytes(), nil
+}
+
+// resolveEncryptionSecretField reads a single field from the Secret ref points at,
+// defaulting its namespace to defaultNamespace and enforcing
+// AllowedCredentialsNamespaces the same way storage credential resolution does.
+func (r *BackupReconciler) resolveEncryptionSecretField(ctx context.Context, defaultNamespace string, ref corev1.SecretReference, field string) ([]byte, error) {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if namespace == "" {
+		return nil, fmt.Errorf("keySecretRef %q has no namespace and none could be defaulted", ref.Name)
+	}
+	if !encryptionNamespaceAllowed(namespace, r.AllowedCredentialsNamespaces) {
+		return nil, fmt.Errorf("keySecretRef namespace %q is not in the operator's allowed namespace list", namespace)
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get encryption key secret %s/%s: %w", namespace, ref.Name, err)
+	}
+
+	data, ok := secret.Data[field]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no data key %q", namespace, ref.Name, field)
+	}
+	return data, nil
+}
+
+func encryptionNamespaceAllowed(namespace string, allowedNamespaces []string) bool {
+	if len(allowedNamespaces) == 0 {
+		return true
+	}
+	for _, ns := range allowedNamespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// encryptAndUploadManifest encrypts the tar archive accumulated by pack with a fresh
+// per-backup data key, uploads the ciphertext and the KEK-wrapped data key alongside
+// each other, and returns the BackupEncryptionStatus to record on backup.Status.
+func (r *BackupReconciler) encryptAndUploadManifest(ctx context.Context, provider storage.Provider, bucket, backupPath string, backup *backupv1.Backup, pack *packagingState) (*backupv1.BackupEncryptionStatus, error) {
+	cfg := backup.Spec.Encryption
+
+	plaintext, err := pack.buildTar(backupPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build manifest tarball: %w", err)
+	}
+
+	dek, err := generateBackupKey()
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := encryptManifest(plaintext, dek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt manifest tarball: %w", err)
+	}
+
+	wrapped, err := r.wrapDataKey(ctx, backup.Namespace, cfg, dek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data encryption key: %w", err)
+	}
+
+	manifestPath := fmt.Sprintf("%s/manifest.tar.gz.enc", backupPath)
+	if err := provider.PutObject(ctx, bucket, manifestPath, bytes.NewReader(ciphertext), int64(len(ciphertext))); err != nil {
+		return nil, fmt.Errorf("failed to upload encrypted manifest: %w", err)
+	}
+
+	wrappedDEKPath := fmt.Sprintf("%s/dek.wrapped", backupPath)
+	if err := provider.PutObject(ctx, bucket, wrappedDEKPath, bytes.NewReader(wrapped), int64(len(wrapped))); err != nil {
+		return nil, fmt.Errorf("failed to upload wrapped data key: %w", err)
+	}
+
+	return &backupv1.BackupEncryptionStatus{
+		Provider:       cfg.Provider,
+		KeyFingerprint: keyFingerprint(wrapped),
+		NonceLength:    gcmNonceSize,
+		ManifestPath:   manifestPath,
+		WrappedDEKPath: wrappedDEKPath,
+	}, nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"filippo.io/age"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestGenerateBackupKey verifies the returned data encryption key has the expected
+// AES-256 length and that successive calls do not repeat the same key.
+func TestGenerateBackupKey(t *testing.T) {
+	dek1, err := generateBackupKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dek1) != dataKeySize {
+		t.Errorf("expected a %d-byte key, got %d bytes", dataKeySize, len(dek1))
+	}
+
+	dek2, err := generateBackupKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytes.Equal(dek1, dek2) {
+		t.Error("expected two successive keys to differ, got identical keys")
+	}
+}
+
+// TestEncryptDecryptManifestRoundTrip verifies a manifest sealed with encryptManifest
+// comes back unchanged through decryptManifest with the same key.
+func TestEncryptDecryptManifestRoundTrip(t *testing.T) {
+	dek, err := generateBackupKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	plaintext := []byte("this is a fake manifest tarball's contents")
+
+	ciphertext, err := encryptManifest(plaintext, dek)
+	if err != nil {
+		t.Fatalf("failed to encrypt manifest: %v", err)
+	}
+	if bytes.Equal(ciphertext, plaintext) {
+		t.Fatal("expected ciphertext to differ from plaintext")
+	}
+
+	got, err := decryptManifest(ciphertext, dek)
+	if err != nil {
+		t.Fatalf("failed to decrypt manifest: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("expected round-tripped plaintext %q, got %q", plaintext, got)
+	}
+}
+
+// TestDecryptManifestDetectsTampering verifies a flipped ciphertext byte is caught by
+// GCM's authentication tag, so decryptManifest returns an error rather than silently
+// returning corrupted data.
+func TestDecryptManifestDetectsTampering(t *testing.T) {
+	dek, err := generateBackupKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	ciphertext, err := encryptManifest([]byte("sensitive backup contents"), dek)
+	if err != nil {
+		t.Fatalf("failed to encrypt manifest: %v", err)
+	}
+
+	tampered := append([]byte(nil), ciphertext...)
+	tampered[len(tampered)-1] ^= 0xFF
+
+	if _, err := decryptManifest(tampered, dek); err == nil {
+		t.Error("expected tampered ciphertext to fail authentication, got nil error")
+	}
+}
+
+// TestWrapDataKeyAESGCM verifies the aes-gcm provider wraps the data key under a KEK
+// read from the referenced secret, and that the wrapped key unwraps back to the
+// original DEK via decryptManifest.
+func TestWrapDataKeyAESGCM(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	kek, err := generateBackupKey()
+	if err != nil {
+		t.Fatalf("failed to generate kek: %v", err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-kek", Namespace: "default"},
+		Data:       map[string][]byte{"key": kek},
+	}
+	r := &BackupReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()}
+
+	cfg := &backupv1.BackupEncryptionConfig{
+		Provider:     backupv1.BackupEncryptionProviderAESGCM,
+		KeySecretRef: corev1.SecretReference{Name: "backup-kek"},
+	}
+	dek, err := generateBackupKey()
+	if err != nil {
+		t.Fatalf("failed to generate dek: %v", err)
+	}
+
+	wrapped, err := r.wrapDataKey(context.Background(), "default", cfg, dek)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	unwrapped, err := decryptManifest(wrapped, kek)
+	if err != nil {
+		t.Fatalf("failed to unwrap data key: %v", err)
+	}
+	if !bytes.Equal(unwrapped, dek) {
+		t.Error("expected unwrapped key to match the original data encryption key")
+	}
+}
+
+// TestWrapDataKeyAgeMultipleRecipients verifies wrapDataKeyAge wraps the data key for
+// every recipient listed in the secret, so rotating or sharing access across multiple
+// identities does not require re-encrypting the backup itself - only re-wrapping its
+// (much smaller) data key.
+func TestWrapDataKeyAgeMultipleRecipients(t *testing.T) {
+	scheme := newHookTestScheme(t)
+
+	identityA, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("failed to generate age identity: %v", err)
+	}
+	identityB, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("failed to generate age identity: %v", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-age-recipients", Namespace: "default"},
+		Data: map[string][]byte{
+			"recipients": []byte(identityA.Recipient().String() + "\n" + identityB.Recipient().String() + "\n"),
+		},
+	}
+	r := &BackupReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()}
+
+	cfg := &backupv1.BackupEncryptionConfig{
+		Provider:     backupv1.BackupEncryptionProviderAge,
+		KeySecretRef: corev1.SecretReference{Name: "backup-age-recipients"},
+	}
+	dek, err := generateBackupKey()
+	if err != nil {
+		t.Fatalf("failed to generate dek: %v", err)
+	}
+
+	wrapped, err := r.wrapDataKey(context.Background(), "default", cfg, dek)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, identity := range map[string]*age.X25519Identity{"A": identityA, "B": identityB} {
+		rc, err := age.Decrypt(bytes.NewReader(wrapped), identity)
+		if err != nil {
+			t.Fatalf("recipient %s failed to unwrap data key: %v", name, err)
+		}
+		var unwrapped bytes.Buffer
+		if _, err := unwrapped.ReadFrom(rc); err != nil {
+			t.Fatalf("recipient %s failed to read unwrapped data key: %v", name, err)
+		}
+		if !bytes.Equal(unwrapped.Bytes(), dek) {
+			t.Errorf("recipient %s: expected unwrapped key to match the original data encryption key", name)
+		}
+	}
+}
+
+// TestResolveEncryptionSecretFieldRejectsDisallowedNamespace verifies
+// resolveEncryptionSecretField enforces AllowedCredentialsNamespaces the same way
+// storage credential resolution does, rather than trusting a KeySecretRef from any
+// namespace.
+func TestResolveEncryptionSecretFieldRejectsDisallowedNamespace(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-kek", Namespace: "untrusted"},
+		Data:       map[string][]byte{"key": []byte("0123456789abcdef0123456789abcdef")},
+	}
+	r := &BackupReconciler{
+		Client:                       fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+		AllowedCredentialsNamespaces: []string{"default"},
+	}
+
+	ref := corev1.SecretReference{Name: "backup-kek", Namespace: "untrusted"}
+	if _, err := r.resolveEncryptionSecretField(context.Background(), "default", ref, defaultKeySecretField); err == nil {
+		t.Error("expected an error resolving a secret outside AllowedCredentialsNamespaces, got nil")
+	}
+}
@@ -0,0 +1,513 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Pod annotations mirroring Velero's hook.velero.io/pre-exec and hook.velero.io/post-exec
+// convention, so users can declare one-off exec hooks on a pod without editing the
+// Backup CR. The annotation value is a JSON array of command arguments, e.g.
+// ["/bin/sh", "-c", "pg_dump ..."].
+const (
+	preExecAnnotation       = "hook.velero.io/pre-exec"
+	postExecAnnotation      = "hook.velero.io/post-exec"
+	execContainerAnnotation = "hook.velero.io/exec-container"
+)
+
+// scaledDownWorkload is a Deployment or StatefulSet a scaleDown hook paused, so
+// runPostBackupHooks knows what to scale back up and to how many replicas.
+type scaledDownWorkload struct {
+	kind             string // "Deployment" or "StatefulSet"
+	name             string
+	namespace        string
+	originalReplicas int32
+}
+
+// scaleDownWaitTimeout bounds how long runPreBackupHooks waits for a scaled-down
+// workload's pods to actually terminate before proceeding with the backup anyway.
+const scaleDownWaitTimeout = 2 * time.Minute
+
+// runPreBackupHooks quiesces every workload matched by backup.Spec.Source.Hooks in
+// namespace: "exec" hooks run PreBackupExec in each matching pod, "scaleDown" hooks
+// scale the pod's owning Deployment/StatefulSet to zero and wait for its pods to
+// terminate. It returns the workloads it scaled down so they can be restored by
+// runPostBackupHooks, and always records a HookResult per pod/hook.
+func (r *BackupReconciler) runPreBackupHooks(ctx context.Context, backup *backupv1.Backup, namespace string) ([]scaledDownWorkload, error) {
+	var scaled []scaledDownWorkload
+
+	for _, hook := range backup.Spec.Source.Hooks {
+		if hook.QuiesceMode == backupv1.QuiesceModeNone || hook.QuiesceMode == "" {
+			continue
+		}
+		if !hookAppliesToNamespace(hook, namespace) {
+			continue
+		}
+
+		pods, err := r.listHookPods(ctx, namespace, hook.PodSelector)
+		if err != nil {
+			return scaled, fmt.Errorf("failed to list pods for hook in namespace %s: %w", namespace, err)
+		}
+
+		for _, pod := range pods {
+			switch hook.QuiesceMode {
+			case backupv1.QuiesceModeExec:
+				for _, outcome := range r.runExecSteps(ctx, &pod, hook.PreHooks, hook.Container, hook.PreBackupExec, hook.OnError, "pre", hook.Timeout) {
+					r.recordHookResult(backup, outcome)
+					if outcome.err != nil && outcome.onErrorFail {
+						return scaled, fmt.Errorf("pre-backup hook failed for pod %s/%s: %w", namespace, pod.Name, outcome.err)
+					}
+				}
+			case backupv1.QuiesceModeScaleDown:
+				workload, err := r.scaleDownOwner(ctx, &pod)
+				if err != nil {
+					r.recordHookResult(backup, hookOutcome{podName: pod.Name, namespace: namespace, phase: "pre", mode: hook.QuiesceMode, err: err})
+					continue
+				}
+				if workload != nil {
+					scaled = append(scaled, *workload)
+				}
+				r.recordHookResult(backup, hookOutcome{podName: pod.Name, namespace: namespace, phase: "pre", mode: hook.QuiesceMode})
+			}
+		}
+	}
+
+	for _, workload := range scaled {
+		if err := r.waitForPodsGone(ctx, workload); err != nil {
+			logf.FromContext(ctx).Error(err, "timed out waiting for scaled-down workload's pods to terminate",
+				"kind", workload.kind, "name", workload.name, "namespace", workload.namespace)
+		}
+	}
+
+	if err := r.runAnnotationHooks(ctx, backup, namespace, "pre"); err != nil {
+		return scaled, err
+	}
+
+	return scaled, nil
+}
+
+// runPostBackupHooks reverses quiescing applied by runPreBackupHooks: scaled-down
+// workloads are restored to their original replica count, and "exec" hooks run
+// PostBackupExec in each matching pod. It is always invoked via defer from
+// backupNamespace, even when the namespace backup itself failed.
+func (r *BackupReconciler) runPostBackupHooks(ctx context.Context, backup *backupv1.Backup, namespace string, scaled []scaledDownWorkload) error {
+	var firstErr error
+
+	for _, workload := range scaled {
+		if err := r.scaleTo(ctx, workload.kind, workload.namespace, workload.name, workload.originalReplicas); err != nil {
+			r.recordHookResult(backup, hookOutcome{podName: workload.name, namespace: workload.namespace, phase: "post", mode: backupv1.QuiesceModeScaleDown, err: err})
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		r.recordHookResult(backup, hookOutcome{podName: workload.name, namespace: workload.namespace, phase: "post", mode: backupv1.QuiesceModeScaleDown})
+	}
+
+	for _, hook := range backup.Spec.Source.Hooks {
+		if hook.QuiesceMode != backupv1.QuiesceModeExec {
+			continue
+		}
+		if !hookAppliesToNamespace(hook, namespace) {
+			continue
+		}
+
+		pods, err := r.listHookPods(ctx, namespace, hook.PodSelector)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to list pods for post-backup hook in namespace %s: %w", namespace, err)
+			}
+			continue
+		}
+
+		for _, pod := range pods {
+			for _, outcome := range r.runExecSteps(ctx, &pod, hook.PostHooks, hook.Container, hook.PostBackupExec, hook.OnError, "post", hook.Timeout) {
+				r.recordHookResult(backup, outcome)
+				if outcome.err != nil && firstErr == nil && outcome.onErrorFail {
+					firstErr = fmt.Errorf("post-backup hook failed for pod %s/%s: %w", namespace, pod.Name, outcome.err)
+				}
+			}
+		}
+	}
+
+	if err := r.runAnnotationHooks(ctx, backup, namespace, "post"); err != nil && firstErr == nil {
+		firstErr = err
+	}
+
+	return firstErr
+}
+
+// runAnnotationHooks runs hook.velero.io/pre-exec and hook.velero.io/post-exec style
+// exec hooks declared directly on pods, for users who want ad hoc hooks without
+// modifying the Backup CR.
+func (r *BackupReconciler) runAnnotationHooks(ctx context.Context, backup *backupv1.Backup, namespace, phase string) error {
+	annotation := preExecAnnotation
+	if phase == "post" {
+		annotation = postExecAnnotation
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list pods for annotation hooks in namespace %s: %w", namespace, err)
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		raw, ok := pod.Annotations[annotation]
+		if !ok || raw == "" {
+			continue
+		}
+
+		var command []string
+		if err := json.Unmarshal([]byte(raw), &command); err != nil {
+			r.recordHookResult(backup, hookOutcome{
+				podName: pod.Name, namespace: namespace, phase: phase, mode: backupv1.QuiesceModeExec,
+				err: fmt.Errorf("invalid %s annotation: %w", annotation, err),
+			})
+			continue
+		}
+
+		r.recordHookResult(backup, r.execInPod(ctx, pod, pod.Annotations[execContainerAnnotation], command, phase, backupv1.QuiesceModeExec, nil))
+	}
+
+	return nil
+}
+
+// listHookPods returns the pods in namespace matching selector.
+func (r *BackupReconciler) listHookPods(ctx context.Context, namespace string, selector *metav1.LabelSelector) ([]corev1.Pod, error) {
+	if selector == nil {
+		return nil, nil
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert hook podSelector: %w", err)
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: labelSelector}); err != nil {
+		return nil, err
+	}
+
+	return pods.Items, nil
+}
+
+// hookOutcome is the intermediate result of running one hook against one pod, before
+// it is turned into a backupv1.HookResult and an Event.
+type hookOutcome struct {
+	podName     string
+	namespace   string
+	phase       string
+	mode        string
+	stderr      string
+	err         error
+	onErrorFail bool // whether err, if set, should abort the backup
+}
+
+// hookAppliesToNamespace reports whether hook should run against namespace, honoring
+// IncludedNamespaces when set. Empty IncludedNamespaces means every namespace applies.
+func hookAppliesToNamespace(hook backupv1.BackupHook, namespace string) bool {
+	if len(hook.IncludedNamespaces) == 0 {
+		return true
+	}
+	for _, ns := range hook.IncludedNamespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// runExecSteps runs either the ordered ExecHook steps or, when steps is empty, the
+// single legacy command/container/onError/timeout, against pod, returning one
+// hookOutcome per step run.
+func (r *BackupReconciler) runExecSteps(ctx context.Context, pod *corev1.Pod, steps []backupv1.ExecHook, legacyContainer string, legacyCommand []string, legacyOnError, phase string, legacyTimeout *metav1.Duration) []hookOutcome {
+	if len(steps) == 0 {
+		outcome := r.execInPod(ctx, pod, legacyContainer, legacyCommand, phase, backupv1.QuiesceModeExec, legacyTimeout)
+		outcome.onErrorFail = legacyOnError == backupv1.HookOnErrorFail
+		return []hookOutcome{outcome}
+	}
+
+	outcomes := make([]hookOutcome, 0, len(steps))
+	for _, step := range steps {
+		outcome := r.execInPod(ctx, pod, step.Container, step.Command, phase, backupv1.QuiesceModeExec, step.Timeout)
+		outcome.onErrorFail = step.OnError == backupv1.HookOnErrorFail
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes
+}
+
+// execInPod runs command inside pod/container via the remotecommand executor and
+// reports the outcome, truncating captured stderr to a short snippet. timeout, when
+// non-nil, bounds the exec call; a nil timeout leaves it bounded only by ctx.
+func (r *BackupReconciler) execInPod(ctx context.Context, pod *corev1.Pod, container string, command []string, phase, mode string, timeout *metav1.Duration) hookOutcome {
+	outcome := hookOutcome{podName: pod.Name, namespace: pod.Namespace, phase: phase, mode: mode}
+
+	if len(command) == 0 {
+		return outcome
+	}
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+	if r.RestConfig == nil {
+		outcome.err = fmt.Errorf("no RestConfig configured for exec hooks")
+		return outcome
+	}
+
+	clientset, err := kubernetes.NewForConfig(r.RestConfig)
+	if err != nil {
+		outcome.err = fmt.Errorf("failed to build clientset for exec hook: %w", err)
+		return outcome
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(r.RestConfig, "POST", req.URL())
+	if err != nil {
+		outcome.err = fmt.Errorf("failed to create exec executor: %w", err)
+		return outcome
+	}
+
+	execCtx := ctx
+	if timeout != nil {
+		var cancel context.CancelFunc
+		execCtx, cancel = context.WithTimeout(ctx, timeout.Duration)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := executor.StreamWithContext(execCtx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr}); err != nil {
+		outcome.stderr = truncate(stderr.String(), 256)
+		outcome.err = fmt.Errorf("exec %v in pod %s/%s container %s failed: %w", command, pod.Namespace, pod.Name, container, err)
+		return outcome
+	}
+
+	return outcome
+}
+
+// scaleDownOwner resolves pod's owning Deployment or StatefulSet and scales it to
+// zero replicas, returning the workload's prior state so it can be restored later.
+// Pods with no recognized scalable owner are left untouched (nil, nil).
+func (r *BackupReconciler) scaleDownOwner(ctx context.Context, pod *corev1.Pod) (*scaledDownWorkload, error) {
+	kind, name, err := r.resolveScalableOwner(ctx, pod)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "" {
+		return nil, nil
+	}
+
+	replicas, err := r.currentReplicas(ctx, kind, pod.Namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.scaleTo(ctx, kind, pod.Namespace, name, 0); err != nil {
+		return nil, err
+	}
+
+	return &scaledDownWorkload{kind: kind, name: name, namespace: pod.Namespace, originalReplicas: replicas}, nil
+}
+
+// resolveScalableOwner walks pod's owner references to find the Deployment or
+// StatefulSet that owns it (Deployments own pods indirectly, via a ReplicaSet).
+func (r *BackupReconciler) resolveScalableOwner(ctx context.Context, pod *corev1.Pod) (kind, name string, err error) {
+	for _, owner := range pod.OwnerReferences {
+		switch owner.Kind {
+		case "StatefulSet":
+			return "StatefulSet", owner.Name, nil
+		case "ReplicaSet":
+			rs := &appsv1.ReplicaSet{}
+			if err := r.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: owner.Name}, rs); err != nil {
+				return "", "", fmt.Errorf("failed to get replicaset %s: %w", owner.Name, err)
+			}
+			for _, rsOwner := range rs.OwnerReferences {
+				if rsOwner.Kind == "Deployment" {
+					return "Deployment", rsOwner.Name, nil
+				}
+			}
+		}
+	}
+	return "", "", nil
+}
+
+func (r *BackupReconciler) currentReplicas(ctx context.Context, kind, namespace, name string) (int32, error) {
+	switch kind {
+	case "Deployment":
+		dep := &appsv1.Deployment{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, dep); err != nil {
+			return 0, fmt.Errorf("failed to get deployment %s: %w", name, err)
+		}
+		if dep.Spec.Replicas == nil {
+			return 1, nil
+		}
+		return *dep.Spec.Replicas, nil
+	case "StatefulSet":
+		sts := &appsv1.StatefulSet{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, sts); err != nil {
+			return 0, fmt.Errorf("failed to get statefulset %s: %w", name, err)
+		}
+		if sts.Spec.Replicas == nil {
+			return 1, nil
+		}
+		return *sts.Spec.Replicas, nil
+	default:
+		return 0, fmt.Errorf("unsupported scalable workload kind %q", kind)
+	}
+}
+
+// scaleTo patches the replica count of the named Deployment or StatefulSet.
+func (r *BackupReconciler) scaleTo(ctx context.Context, kind, namespace, name string, replicas int32) error {
+	switch kind {
+	case "Deployment":
+		dep := &appsv1.Deployment{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, dep); err != nil {
+			return fmt.Errorf("failed to get deployment %s: %w", name, err)
+		}
+		dep.Spec.Replicas = &replicas
+		if err := r.Update(ctx, dep); err != nil {
+			return fmt.Errorf("failed to scale deployment %s to %d replicas: %w", name, replicas, err)
+		}
+	case "StatefulSet":
+		sts := &appsv1.StatefulSet{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, sts); err != nil {
+			return fmt.Errorf("failed to get statefulset %s: %w", name, err)
+		}
+		sts.Spec.Replicas = &replicas
+		if err := r.Update(ctx, sts); err != nil {
+			return fmt.Errorf("failed to scale statefulset %s to %d replicas: %w", name, replicas, err)
+		}
+	default:
+		return fmt.Errorf("unsupported scalable workload kind %q", kind)
+	}
+	return nil
+}
+
+// waitForPodsGone polls until the scaled-down workload has no running pods left, or
+// scaleDownWaitTimeout elapses, in which case it returns an error but does not block
+// the backup further - the caller only logs it.
+func (r *BackupReconciler) waitForPodsGone(ctx context.Context, workload scaledDownWorkload) error {
+	deadline := time.Now().Add(scaleDownWaitTimeout)
+
+	for {
+		pods := &corev1.PodList{}
+		if err := r.List(ctx, pods, client.InNamespace(workload.namespace)); err != nil {
+			return fmt.Errorf("failed to list pods while waiting for %s/%s to scale down: %w", workload.kind, workload.name, err)
+		}
+
+		stillRunning := false
+		for _, pod := range pods.Items {
+			owned, err := r.podBelongsToWorkload(ctx, &pod, workload)
+			if err != nil {
+				return err
+			}
+			if owned {
+				stillRunning = true
+				break
+			}
+		}
+		if !stillRunning {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for %s/%s pods to terminate", workload.kind, workload.name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// podBelongsToWorkload reports whether pod is owned (directly, or transitively via a
+// ReplicaSet) by the Deployment/StatefulSet described by workload.
+func (r *BackupReconciler) podBelongsToWorkload(ctx context.Context, pod *corev1.Pod, workload scaledDownWorkload) (bool, error) {
+	kind, name, err := r.resolveScalableOwner(ctx, pod)
+	if err != nil {
+		return false, err
+	}
+	return kind == workload.kind && name == workload.name, nil
+}
+
+// recordHookResult appends a HookResult to backup.Status and, on failure, emits a
+// Warning Event on the Backup object.
+func (r *BackupReconciler) recordHookResult(backup *backupv1.Backup, outcome hookOutcome) {
+	result := backupv1.HookResult{
+		PodName:     outcome.podName,
+		Namespace:   outcome.namespace,
+		Phase:       outcome.phase,
+		QuiesceMode: outcome.mode,
+		Success:     outcome.err == nil,
+	}
+
+	if outcome.err != nil {
+		result.Message = outcome.err.Error()
+		if outcome.stderr != "" {
+			result.Message = fmt.Sprintf("%s (stderr: %s)", result.Message, outcome.stderr)
+		}
+		if r.Recorder != nil {
+			r.Recorder.Eventf(backup, corev1.EventTypeWarning, "HookFailed",
+				"%s hook (%s) failed for pod %s/%s: %v", outcome.phase, outcome.mode, outcome.namespace, outcome.podName, outcome.err)
+		}
+	}
+
+	backup.Status.HookResults = append(backup.Status.HookResults, result)
+
+	if backup.Status.HookStats == nil {
+		backup.Status.HookStats = &backupv1.HookStats{}
+	}
+	backup.Status.HookStats.Attempted++
+	if !result.Success {
+		backup.Status.HookStats.Failed++
+	}
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
+}
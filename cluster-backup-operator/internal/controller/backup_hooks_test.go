@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newHookTestScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	if err := backupv1.AddToScheme(s); err != nil {
+		t.Fatalf("failed to add backupv1 to scheme: %v", err)
+	}
+	return s
+}
+
+func int32Ptr(i int32) *int32 { return &i }
+
+// TestResolveScalableOwnerStatefulSet tests direct pod -> StatefulSet ownership.
+func TestResolveScalableOwnerStatefulSet(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db-0",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "StatefulSet", Name: "db", APIVersion: "apps/v1"},
+			},
+		},
+	}
+
+	r := &BackupReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+
+	kind, name, err := r.resolveScalableOwner(context.Background(), pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if kind != "StatefulSet" || name != "db" {
+		t.Errorf("expected StatefulSet/db, got %s/%s", kind, name)
+	}
+}
+
+// TestResolveScalableOwnerDeployment tests pod -> ReplicaSet -> Deployment ownership.
+func TestResolveScalableOwnerDeployment(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-abc123",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "Deployment", Name: "web", APIVersion: "apps/v1"},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-abc123-xyz",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "web-abc123", APIVersion: "apps/v1"},
+			},
+		},
+	}
+
+	r := &BackupReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(rs).Build()}
+
+	kind, name, err := r.resolveScalableOwner(context.Background(), pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if kind != "Deployment" || name != "web" {
+		t.Errorf("expected Deployment/web, got %s/%s", kind, name)
+	}
+}
+
+// TestScaleToDeployment tests that scaleTo patches a Deployment's replica count.
+func TestScaleToDeployment(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+	}
+
+	r := &BackupReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(dep).Build()}
+
+	if err := r.scaleTo(context.Background(), "Deployment", "default", "web", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	replicas, err := r.currentReplicas(context.Background(), "Deployment", "default", "web")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replicas != 0 {
+		t.Errorf("expected 0 replicas after scale down, got %d", replicas)
+	}
+}
+
+// TestRecordHookResultAppendsStatus tests that hook outcomes accumulate on Status.HookResults.
+func TestRecordHookResultAppendsStatus(t *testing.T) {
+	r := &BackupReconciler{}
+	backup := &backupv1.Backup{}
+
+	r.recordHookResult(backup, hookOutcome{podName: "web-1", namespace: "default", phase: "pre", mode: backupv1.QuiesceModeExec})
+	r.recordHookResult(backup, hookOutcome{podName: "web-2", namespace: "default", phase: "pre", mode: backupv1.QuiesceModeExec, err: context.DeadlineExceeded})
+
+	if len(backup.Status.HookResults) != 2 {
+		t.Fatalf("expected 2 hook results, got %d", len(backup.Status.HookResults))
+	}
+	if !backup.Status.HookResults[0].Success {
+		t.Error("expected first hook result to be successful")
+	}
+	if backup.Status.HookResults[1].Success {
+		t.Error("expected second hook result to record failure")
+	}
+	if backup.Status.HookResults[1].Message == "" {
+		t.Error("expected failure message to be recorded")
+	}
+}
+
+// TestHookAppliesToNamespace tests that IncludedNamespaces scopes a hook to a subset
+// of the Backup's namespaces, and that an empty list applies everywhere.
+func TestHookAppliesToNamespace(t *testing.T) {
+	scoped := backupv1.BackupHook{IncludedNamespaces: []string{"prod"}}
+	if !hookAppliesToNamespace(scoped, "prod") {
+		t.Error("expected hook to apply to a namespace in IncludedNamespaces")
+	}
+	if hookAppliesToNamespace(scoped, "staging") {
+		t.Error("expected hook not to apply to a namespace outside IncludedNamespaces")
+	}
+
+	unscoped := backupv1.BackupHook{}
+	if !hookAppliesToNamespace(unscoped, "staging") {
+		t.Error("expected an empty IncludedNamespaces to apply to every namespace")
+	}
+}
+
+// TestRunExecStepsFallsBackToLegacyCommand tests that runExecSteps runs the legacy
+// single command/onError when no PreHooks/PostHooks steps are configured.
+func TestRunExecStepsFallsBackToLegacyCommand(t *testing.T) {
+	r := &BackupReconciler{}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}}
+
+	outcomes := r.runExecSteps(context.Background(), pod, nil, "app", []string{"true"}, backupv1.HookOnErrorFail, "pre", nil)
+
+	if len(outcomes) != 1 {
+		t.Fatalf("expected 1 outcome for the legacy command, got %d", len(outcomes))
+	}
+	if !outcomes[0].onErrorFail {
+		t.Error("expected onErrorFail to carry the legacy OnError=Fail setting")
+	}
+	if outcomes[0].err == nil {
+		t.Error("expected an error since no RestConfig is configured for exec hooks")
+	}
+}
+
+// TestRunExecStepsRunsOrderedSteps tests that runExecSteps runs each PreHooks/PostHooks
+// entry in order, carrying each step's own OnError into onErrorFail.
+func TestRunExecStepsRunsOrderedSteps(t *testing.T) {
+	r := &BackupReconciler{}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}}
+	steps := []backupv1.ExecHook{
+		{Container: "db", Command: []string{"pg_dump"}, OnError: backupv1.HookOnErrorFail},
+		{Container: "fs", Command: []string{"sync"}, OnError: backupv1.HookOnErrorContinue},
+	}
+
+	outcomes := r.runExecSteps(context.Background(), pod, steps, "ignored", []string{"ignored"}, backupv1.HookOnErrorContinue, "post", nil)
+
+	if len(outcomes) != 2 {
+		t.Fatalf("expected 2 outcomes, one per step, got %d", len(outcomes))
+	}
+	if !outcomes[0].onErrorFail {
+		t.Error("expected the first step's onErrorFail to reflect its own OnError=Fail")
+	}
+	if outcomes[1].onErrorFail {
+		t.Error("expected the second step's onErrorFail to reflect its own OnError=Continue")
+	}
+}
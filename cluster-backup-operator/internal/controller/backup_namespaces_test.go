@@ -0,0 +1,50 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestGetAllNamespacesExcludesGlobMatches verifies excludeNamespaces entries like
+// "kube-*" drop every matching namespace, not just an exact-name match.
+func TestGetAllNamespacesExcludesGlobMatches(t *testing.T) {
+	scheme := clientgoscheme.Scheme
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev-app"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-public"}},
+	).Build()
+
+	r := &BackupReconciler{Client: fakeClient}
+	namespaces, err := r.getAllNamespaces(context.Background(), []string{"kube-*"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sort.Strings(namespaces)
+	if len(namespaces) != 1 || namespaces[0] != "dev-app" {
+		t.Errorf("getAllNamespaces() = %v, want [dev-app]", namespaces)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+)
+
+// uploadConcurrency bounds how many resources backupNamespacedResourceType,
+// backupClusterResourceType and backupCRDList upload at once, so a namespace with
+// thousands of ConfigMaps does not serialize one PutObject round trip after another.
+const uploadConcurrency = 8
+
+// boundedParallel calls fn(i) for every i in [0,n) using at most uploadConcurrency
+// goroutines at a time, waits for all of them to finish, and returns the first error
+// encountered (later errors are discarded; all other calls are still allowed to run
+// to completion).
+func boundedParallel(n int, fn func(i int) error) error {
+	sem := make(chan struct{}, uploadConcurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	for i := 0; i < n; i++ {
+		i := i
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(i); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+// tarEntry is one resource accumulated by a packagingState, ready to be written as a
+// tar entry once the archive is finalized.
+type tarEntry struct {
+	name string
+	data []byte
+}
+
+// packagingState accumulates the resources written under PackagingModeTarballPerNamespace
+// or PackagingModeSingleTarball instead of uploading them individually. A nil
+// *packagingState means PackagingModePerResource (the default) is in effect and
+// uploadResource uploads each resource as its own object, as before this feature
+// existed.
+type packagingState struct {
+	mu      sync.Mutex
+	entries []tarEntry
+}
+
+// newPackagingState returns an empty packagingState ready to accumulate one tar
+// archive's worth of resources.
+func newPackagingState() *packagingState {
+	return &packagingState{}
+}
+
+// add records objectName's serialized content for inclusion in the tar archive this
+// packagingState will eventually upload.
+func (p *packagingState) add(objectName string, data []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.entries = append(p.entries, tarEntry{name: objectName, data: data})
+}
+
+// upload streams every entry accumulated so far into a single tar archive at tarKey,
+// with each entry named by stripping backupPath from the per-resource object name it
+// would otherwise have used. Entries are written into an io.Pipe by a goroutine and
+// read by provider.PutObject with an unknown size, the same streaming pattern
+// storage.Copy uses to move an object between providers without buffering it fully in
+// memory.
+func (p *packagingState) upload(ctx context.Context, provider storage.Provider, bucket, backupPath, tarKey string) error {
+	pr, pw := io.Pipe()
+
+	go func() {
+		tw := tar.NewWriter(pw)
+		for _, entry := range p.entries {
+			header := &tar.Header{
+				Name: strings.TrimPrefix(entry.name, backupPath+"/"),
+				Mode: 0o644,
+				Size: int64(len(entry.data)),
+			}
+			if err := tw.WriteHeader(header); err != nil {
+				pw.CloseWithError(fmt.Errorf("failed to write tar header for %s: %w", entry.name, err))
+				return
+			}
+			if _, err := tw.Write(entry.data); err != nil {
+				pw.CloseWithError(fmt.Errorf("failed to write tar entry for %s: %w", entry.name, err))
+				return
+			}
+		}
+		pw.CloseWithError(tw.Close())
+	}()
+
+	if err := provider.PutObject(ctx, bucket, tarKey, pr, -1); err != nil {
+		return fmt.Errorf("failed to upload tarball %s: %w", tarKey, err)
+	}
+	return nil
+}
+
+// buildTar renders every entry accumulated so far into an in-memory tar archive
+// instead of streaming it straight to PutObject. Used by backup encryption, which
+// needs the whole plaintext available up front to compute its AES-GCM authentication
+// tag; plain (unencrypted) uploads keep using upload, which never buffers the
+// archive fully in memory.
+func (p *packagingState) buildTar(backupPath string) ([]byte, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, entry := range p.entries {
+		header := &tar.Header{
+			Name: strings.TrimPrefix(entry.name, backupPath+"/"),
+			Mode: 0o644,
+			Size: int64(len(entry.data)),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, fmt.Errorf("failed to write tar header for %s: %w", entry.name, err)
+		}
+		if _, err := tw.Write(entry.data); err != nil {
+			return nil, fmt.Errorf("failed to write tar entry for %s: %w", entry.name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize tar archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
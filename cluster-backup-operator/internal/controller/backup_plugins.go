@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/plugin"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// itemActionState bundles the plugin.Registry and configured ItemActionRefs for one
+// backup run, threaded alongside dedup/pack the same way uploadResource already
+// threads those through. A nil *itemActionState means Backup.Spec.ItemActions was
+// empty, so apply is a no-op and every call site behaves exactly as it did before this
+// extension point existed.
+type itemActionState struct {
+	registry *plugin.Registry
+	refs     []backupv1.ItemActionRef
+}
+
+// newItemActionState builds the plugin.Registry this backup's ItemActions run
+// against: the built-in actions plus, when r.PluginDir is set, every plugin manifest
+// found there. Returns nil, without loading anything, when backup.Spec.ItemActions is
+// empty, since most backups configure none at all.
+func (r *BackupReconciler) newItemActionState(backup *backupv1.Backup) (*itemActionState, error) {
+	if len(backup.Spec.ItemActions) == 0 {
+		return nil, nil
+	}
+
+	registry := plugin.NewRegistry()
+	if r.PluginDir != "" {
+		if err := registry.LoadDir(r.PluginDir); err != nil {
+			return nil, fmt.Errorf("failed to load plugins from %s: %w", r.PluginDir, err)
+		}
+	}
+
+	return &itemActionState{registry: registry, refs: backup.Spec.ItemActions}, nil
+}
+
+// close releases the plugin registry's gRPC connections, if any were opened. Safe to
+// call on a nil *itemActionState.
+func (s *itemActionState) close() {
+	if s != nil {
+		s.registry.Close()
+	}
+}
+
+// apply runs every configured ItemActionRef whose ItemAction.Applies matches
+// group/kind/namespace against raw, in spec order, configuring each with its
+// ItemActionRef.Config before calling Execute. Returns the possibly transformed bytes,
+// any additional resources the actions asked to back up alongside this one (see
+// backupAdditionalItems), and whether any action asked to skip this resource entirely;
+// once an action returns Skip, the remaining actions are not run. A nil receiver
+// (Backup.Spec.ItemActions empty) returns raw unchanged. Mirrors applyItemActions on
+// the restore side.
+func (s *itemActionState) apply(group, kind, namespace string, raw []byte) ([]byte, []plugin.ResourceRef, bool, error) {
+	if s == nil {
+		return raw, nil, false, nil
+	}
+
+	var additional []plugin.ResourceRef
+	for _, ref := range s.refs {
+		action, ok := s.registry.Get(ref.Name)
+		if !ok {
+			return nil, additional, false, fmt.Errorf("item action %q is not registered (check spec.itemActions and the operator's --plugin-dir)", ref.Name)
+		}
+		if !action.Applies(group, kind, namespace) {
+			continue
+		}
+		if err := action.Configure(ref.Config); err != nil {
+			return nil, additional, false, fmt.Errorf("item action %q: failed to configure: %w", ref.Name, err)
+		}
+
+		result, err := action.Execute(raw)
+		if err != nil {
+			return nil, additional, false, fmt.Errorf("item action %q: %w", ref.Name, err)
+		}
+		additional = append(additional, result.AdditionalItems...)
+
+		if result.Skip {
+			return nil, additional, true, nil
+		}
+		if len(result.Transformed) > 0 {
+			raw = result.Transformed
+		}
+	}
+
+	return raw, additional, false, nil
+}
+
+// backupAdditionalItems resolves and uploads every ResourceRef an ItemAction returned
+// from Execute, via the same discovery/dynamic client backupIncludedCRD uses, so a
+// plugin can pull in objects a built-in resource handler would not otherwise have
+// backed up (e.g. a Secret a transformed resource now references). Each item is
+// uploaded as its own object under backupPath/itemactions, independent of
+// PackagingMode or Source.Deduplicate: these are expected to be a handful of
+// supplementary objects, not the bulk of the backup.
+func (r *BackupReconciler) backupAdditionalItems(ctx context.Context, provider storage.Provider, bucket, backupPath string, refs []plugin.ResourceRef) error {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	mapper, err := r.restMapper()
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(r.RestConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	for _, ref := range refs {
+		mapping, err := mapper.RESTMapping(schema.GroupKind{Group: ref.Group, Kind: ref.Kind}, ref.Version)
+		if err != nil {
+			return fmt.Errorf("failed to resolve item action additional item %s/%s %s: %w", ref.Group, ref.Kind, ref.Name, err)
+		}
+
+		var resourceClient dynamic.ResourceInterface = dynamicClient.Resource(mapping.Resource)
+		objectName := fmt.Sprintf("%s/itemactions/%s/%s.json", backupPath, mapping.Resource.Resource, ref.Name)
+		if ref.Namespace != "" {
+			resourceClient = dynamicClient.Resource(mapping.Resource).Namespace(ref.Namespace)
+			objectName = fmt.Sprintf("%s/itemactions/%s/%s/%s.json", backupPath, mapping.Resource.Resource, ref.Namespace, ref.Name)
+		}
+
+		obj, err := resourceClient.Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to fetch item action additional item %s/%s %s: %w", ref.Group, ref.Kind, ref.Name, err)
+		}
+
+		jsonData, err := json.Marshal(obj.Object)
+		if err != nil {
+			return fmt.Errorf("failed to marshal item action additional item %s: %w", obj.GetName(), err)
+		}
+		if err := provider.PutObject(ctx, bucket, objectName, bytes.NewReader(jsonData), int64(len(jsonData))); err != nil {
+			return fmt.Errorf("failed to upload item action additional item %s: %w", obj.GetName(), err)
+		}
+	}
+
+	return nil
+}
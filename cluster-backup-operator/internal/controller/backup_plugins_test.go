@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/plugin"
+)
+
+// TestItemActionStateApplyNilIsNoOp verifies a nil *itemActionState (no ItemActions
+// configured) returns raw unchanged, so every uploadResource call site can call apply
+// unconditionally without a nil check of its own.
+func TestItemActionStateApplyNilIsNoOp(t *testing.T) {
+	var actions *itemActionState
+	raw := []byte(`{"metadata":{"name":"demo"}}`)
+
+	transformed, additional, skip, err := actions.apply("", "ConfigMap", "default", raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if skip {
+		t.Error("expected skip to be false")
+	}
+	if len(additional) != 0 {
+		t.Errorf("expected no additional items, got %v", additional)
+	}
+	if string(transformed) != string(raw) {
+		t.Error("expected a nil itemActionState to return raw unchanged")
+	}
+}
+
+// TestItemActionStateApplyStripsClusterFields runs the built-in strip-cluster-fields
+// plugin through itemActionState.apply end to end.
+func TestItemActionStateApplyStripsClusterFields(t *testing.T) {
+	actions := &itemActionState{
+		registry: plugin.NewRegistry(),
+		refs:     []backupv1.ItemActionRef{{Name: "strip-cluster-fields"}},
+	}
+	raw := []byte(`{"metadata":{"name":"demo","resourceVersion":"123"}}`)
+
+	transformed, _, skip, err := actions.apply("", "ConfigMap", "default", raw)
+	if err != nil {
+		t.Fatalf("apply() returned error: %v", err)
+	}
+	if skip {
+		t.Fatal("expected skip to be false")
+	}
+	if string(transformed) == string(raw) {
+		t.Error("expected transformed output to differ from input")
+	}
+}
+
+// TestItemActionStateApplyUnknownPlugin confirms an ItemActionRef naming a plugin that
+// isn't registered surfaces an error instead of silently skipping it, matching
+// applyItemActions on the restore side.
+func TestItemActionStateApplyUnknownPlugin(t *testing.T) {
+	actions := &itemActionState{
+		registry: plugin.NewRegistry(),
+		refs:     []backupv1.ItemActionRef{{Name: "does-not-exist"}},
+	}
+
+	if _, _, _, err := actions.apply("", "ConfigMap", "default", []byte(`{}`)); err == nil {
+		t.Error("expected an error for an unregistered item action")
+	}
+}
+
+// TestNewItemActionStateEmptyReturnsNil verifies a Backup with no ItemActions gets a
+// nil *itemActionState rather than an empty, pointlessly-allocated registry.
+func TestNewItemActionStateEmptyReturnsNil(t *testing.T) {
+	r := &BackupReconciler{}
+	backup := &backupv1.Backup{}
+
+	actions, err := r.newItemActionState(backup)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actions != nil {
+		t.Error("expected a nil itemActionState when Spec.ItemActions is empty")
+	}
+}
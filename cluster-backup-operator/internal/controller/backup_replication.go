@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+)
+
+// newReplicatingProvider builds a storage.Provider for every one of backup's
+// StorageLocation.ReplicaTargets, ensures each target's bucket exists, and wraps
+// primary so every object this run writes also fans out to them.
+func (r *BackupReconciler) newReplicatingProvider(ctx context.Context, backup *backupv1.Backup, primary storage.Provider) (*storage.ReplicatingProvider, error) {
+	replicas := make(map[string]storage.Provider, len(backup.Spec.StorageLocation.ReplicaTargets))
+	for _, target := range backup.Spec.StorageLocation.ReplicaTargets {
+		replicaProvider, err := storage.NewProvider(ctx, r.Client, r.AllowedCredentialsNamespaces, backup.Namespace, target.StorageLocation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize replica %q: %w", target.Name, err)
+		}
+		if err := replicaProvider.EnsureBucket(ctx, target.StorageLocation.Bucket); err != nil {
+			return nil, fmt.Errorf("failed to ensure bucket for replica %q: %w", target.Name, err)
+		}
+		replicas[target.Name] = replicaProvider
+	}
+
+	return storage.NewReplicatingProvider(primary, replicas), nil
+}
+
+// buildReplicaStatuses translates replicatingProvider's accumulated write stats into
+// BackupStatus.Replicas, carrying forward each target's LastSyncedGeneration from the
+// backup's existing status when this run degraded it rather than improving on it.
+func (r *BackupReconciler) buildReplicaStatuses(backup *backupv1.Backup, replicatingProvider *storage.ReplicatingProvider) []backupv1.ReplicaStatus {
+	previous := make(map[string]backupv1.ReplicaStatus, len(backup.Status.Replicas))
+	for _, status := range backup.Status.Replicas {
+		previous[status.Name] = status
+	}
+
+	statuses := make([]backupv1.ReplicaStatus, 0, len(replicatingProvider.Stats()))
+	for _, stats := range replicatingProvider.Stats() {
+		status := backupv1.ReplicaStatus{
+			Name:                 stats.Name,
+			LastSyncedGeneration: previous[stats.Name].LastSyncedGeneration,
+		}
+
+		if stats.Failed == 0 {
+			status.Phase = backupv1.ReplicaPhaseSynced
+			status.LastSyncedGeneration = backup.Generation
+		} else {
+			status.Phase = backupv1.ReplicaPhaseDegraded
+			status.Error = stats.LastError.Error()
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+)
+
+// failingPutObjectProvider wraps a storage.Provider and always fails PutObject, so
+// tests can exercise a degraded replica without a real unreachable endpoint.
+type failingPutObjectProvider struct {
+	storage.Provider
+}
+
+func (p *failingPutObjectProvider) PutObject(ctx context.Context, bucket, key string, r io.Reader, size int64) error {
+	return fmt.Errorf("simulated replica write failure")
+}
+
+func TestBuildReplicaStatusesSyncedWhenAllReplicasSucceed(t *testing.T) {
+	ctx := context.Background()
+	primary := newLocalProviderForTest(t)
+	replica := newLocalProviderForTest(t)
+	if err := primary.EnsureBucket(ctx, "bucket"); err != nil {
+		t.Fatalf("failed to ensure primary bucket: %v", err)
+	}
+	if err := replica.EnsureBucket(ctx, "bucket"); err != nil {
+		t.Fatalf("failed to ensure replica bucket: %v", err)
+	}
+
+	rp := storage.NewReplicatingProvider(primary, map[string]storage.Provider{"secondary-site": replica})
+	data := []byte(`{"data":"v1"}`)
+	if err := rp.PutObject(ctx, "bucket", "backups/t1/resource.json", bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	backup := &backupv1.Backup{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+	r := &BackupReconciler{}
+	statuses := r.buildReplicaStatuses(backup, rp)
+
+	if len(statuses) != 1 {
+		t.Fatalf("expected 1 replica status, got %d", len(statuses))
+	}
+	if statuses[0].Phase != backupv1.ReplicaPhaseSynced {
+		t.Errorf("expected phase %q, got %q", backupv1.ReplicaPhaseSynced, statuses[0].Phase)
+	}
+	if statuses[0].LastSyncedGeneration != 3 {
+		t.Errorf("expected lastSyncedGeneration 3, got %d", statuses[0].LastSyncedGeneration)
+	}
+}
+
+// TestBuildReplicaStatusesDegradedKeepsPriorSyncedGeneration verifies that a replica
+// failing this run is reported Degraded with an Error, and that its
+// LastSyncedGeneration is carried forward from the last run that actually synced it
+// rather than being bumped to the current (failed) generation.
+func TestBuildReplicaStatusesDegradedKeepsPriorSyncedGeneration(t *testing.T) {
+	ctx := context.Background()
+	primary := newLocalProviderForTest(t)
+	if err := primary.EnsureBucket(ctx, "bucket"); err != nil {
+		t.Fatalf("failed to ensure primary bucket: %v", err)
+	}
+	failing := &failingPutObjectProvider{Provider: newLocalProviderForTest(t)}
+
+	rp := storage.NewReplicatingProvider(primary, map[string]storage.Provider{"secondary-site": failing})
+	data := []byte(`{"data":"v1"}`)
+	if err := rp.PutObject(ctx, "bucket", "backups/t2/resource.json", bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatalf("expected the primary write to succeed despite a failing replica, got: %v", err)
+	}
+
+	backup := &backupv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Generation: 5},
+		Status: backupv1.BackupStatus{
+			Replicas: []backupv1.ReplicaStatus{
+				{Name: "secondary-site", Phase: backupv1.ReplicaPhaseSynced, LastSyncedGeneration: 4},
+			},
+		},
+	}
+	r := &BackupReconciler{}
+	statuses := r.buildReplicaStatuses(backup, rp)
+
+	if len(statuses) != 1 {
+		t.Fatalf("expected 1 replica status, got %d", len(statuses))
+	}
+	if statuses[0].Phase != backupv1.ReplicaPhaseDegraded {
+		t.Errorf("expected phase %q, got %q", backupv1.ReplicaPhaseDegraded, statuses[0].Phase)
+	}
+	if statuses[0].Error == "" {
+		t.Error("expected a non-empty Error for the degraded replica")
+	}
+	if statuses[0].LastSyncedGeneration != 4 {
+		t.Errorf("expected lastSyncedGeneration to stay at the prior synced generation 4, got %d", statuses[0].LastSyncedGeneration)
+	}
+}
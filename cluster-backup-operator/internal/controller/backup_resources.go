@@ -0,0 +1,355 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/filter"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResourceBackupHandler backs up every object of one built-in Kubernetes resource
+// type. Each handler is registered once, in namespacedResourceHandlers or
+// clusterResourceHandlers below, so adding a new built-in type means adding one
+// handler instead of threading a new case through backupNamespacedResourceType and
+// backupClusterResourceType. Types with no dedicated handler can still be backed up
+// via BackupSource.IncludeCRDs, which lists+uploads through the dynamic client.
+type ResourceBackupHandler interface {
+	// GVK identifies the resource type this handler backs up.
+	GVK() schema.GroupVersionKind
+
+	// List returns every object of this type visible to source's label selector,
+	// scoped to namespace (empty for cluster-scoped handlers).
+	List(ctx context.Context, r *BackupReconciler, namespace string, source backupv1.BackupSource) ([]client.Object, error)
+
+	// PathPrefix is the on-disk folder name under namespaces/<ns>/ (or cluster/ for
+	// cluster-scoped handlers), matching what the restore controller expects.
+	PathPrefix() string
+}
+
+// namespacedResourceHandlers is keyed by the same resourceType strings accepted by
+// BackupSource.ResourceTypes.
+var namespacedResourceHandlers = map[string]ResourceBackupHandler{
+	"deployments":            deploymentsHandler{},
+	"services":               servicesHandler{},
+	"configmaps":             configMapsHandler{},
+	"secrets":                secretsHandler{},
+	"persistentvolumeclaims": persistentVolumeClaimsHandler{},
+	"ingresses":              ingressesHandler{},
+}
+
+// clusterResourceHandlers is keyed by the cluster resource type strings returned by
+// getClusterResourceTypes.
+var clusterResourceHandlers = map[string]ResourceBackupHandler{
+	"clusterroles":        clusterRolesHandler{},
+	"clusterrolebindings": clusterRoleBindingsHandler{},
+	"persistentvolumes":   persistentVolumesHandler{},
+	"storageclasses":      storageClassesHandler{},
+}
+
+// listOptsFor builds the common namespace + label selector ListOptions shared by
+// every handler below. When OrLabelSelectors is set it takes precedence over
+// LabelSelector, matching restoreFilter's precedence rule; since the API server's
+// label selector matching can only express an AND of requirements, OR semantics are
+// instead applied client-side afterwards by filterBackupObjects, so LabelSelector is
+// omitted here to avoid narrowing the list before the OR filter ever sees it.
+func listOptsFor(namespace string, source backupv1.BackupSource) ([]client.ListOption, error) {
+	var opts []client.ListOption
+	if namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	if source.LabelSelector != nil && len(source.OrLabelSelectors) == 0 {
+		selector, err := metav1.LabelSelectorAsSelector(source.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert label selector: %w", err)
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+	return opts, nil
+}
+
+// backupFilterPredicate compiles source's ExcludedResources/OrLabelSelectors into a
+// filter.Predicate. ResourceTypes/IncludedResources is intentionally left empty here:
+// it has already been used upstream to decide which resourceType to call, and
+// re-applying it here would be redundant.
+func backupFilterPredicate(source backupv1.BackupSource) (*filter.Predicate, error) {
+	return filter.Compile(filter.Policy{
+		ExcludedResources: source.ExcludedResources,
+		OrLabelSelectors:  source.OrLabelSelectors,
+	})
+}
+
+// filterBackupObjects drops objects excluded by source.ExcludedResources, and, when
+// source.OrLabelSelectors is set, drops objects that don't match any of them. The
+// AND-only LabelSelector is not checked here: listOptsFor applies it server-side only
+// when OrLabelSelectors is empty, and ignores it otherwise.
+func filterBackupObjects(objects []client.Object, resourceType string, source backupv1.BackupSource) ([]client.Object, error) {
+	if len(source.ExcludedResources) == 0 && len(source.OrLabelSelectors) == 0 {
+		return objects, nil
+	}
+
+	predicate, err := backupFilterPredicate(source)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]client.Object, 0, len(objects))
+	for _, obj := range objects {
+		if !predicate.AllowsResource(resourceType, obj.GetName()) {
+			continue
+		}
+		if len(source.OrLabelSelectors) > 0 && !predicate.AllowsLabels(obj.GetLabels()) {
+			continue
+		}
+		filtered = append(filtered, obj)
+	}
+	return filtered, nil
+}
+
+type deploymentsHandler struct{}
+
+func (deploymentsHandler) GVK() schema.GroupVersionKind { return appsv1.SchemeGroupVersion.WithKind("Deployment") }
+func (deploymentsHandler) PathPrefix() string           { return "deployments" }
+func (deploymentsHandler) List(ctx context.Context, r *BackupReconciler, namespace string, source backupv1.BackupSource) ([]client.Object, error) {
+	opts, err := listOptsFor(namespace, source)
+	if err != nil {
+		return nil, err
+	}
+	var list appsv1.DeploymentList
+	if err := r.List(ctx, &list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	objects := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		objects = append(objects, &list.Items[i])
+	}
+	return objects, nil
+}
+
+type servicesHandler struct{}
+
+func (servicesHandler) GVK() schema.GroupVersionKind { return corev1.SchemeGroupVersion.WithKind("Service") }
+func (servicesHandler) PathPrefix() string           { return "services" }
+func (servicesHandler) List(ctx context.Context, r *BackupReconciler, namespace string, source backupv1.BackupSource) ([]client.Object, error) {
+	opts, err := listOptsFor(namespace, source)
+	if err != nil {
+		return nil, err
+	}
+	var list corev1.ServiceList
+	if err := r.List(ctx, &list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	objects := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		service := &list.Items[i]
+		// Skip the default kubernetes service and system services.
+		if service.Name == "kubernetes" || service.Namespace == "kube-system" || service.Namespace == "kube-public" {
+			continue
+		}
+		objects = append(objects, service)
+	}
+	return objects, nil
+}
+
+type configMapsHandler struct{}
+
+func (configMapsHandler) GVK() schema.GroupVersionKind { return corev1.SchemeGroupVersion.WithKind("ConfigMap") }
+func (configMapsHandler) PathPrefix() string           { return "configmaps" }
+func (configMapsHandler) List(ctx context.Context, r *BackupReconciler, namespace string, source backupv1.BackupSource) ([]client.Object, error) {
+	opts, err := listOptsFor(namespace, source)
+	if err != nil {
+		return nil, err
+	}
+	var list corev1.ConfigMapList
+	if err := r.List(ctx, &list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list configmaps: %w", err)
+	}
+	objects := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		objects = append(objects, &list.Items[i])
+	}
+	return objects, nil
+}
+
+type secretsHandler struct{}
+
+func (secretsHandler) GVK() schema.GroupVersionKind { return corev1.SchemeGroupVersion.WithKind("Secret") }
+func (secretsHandler) PathPrefix() string           { return "secrets" }
+func (secretsHandler) List(ctx context.Context, r *BackupReconciler, namespace string, source backupv1.BackupSource) ([]client.Object, error) {
+	opts, err := listOptsFor(namespace, source)
+	if err != nil {
+		return nil, err
+	}
+	var list corev1.SecretList
+	if err := r.List(ctx, &list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	objects := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		secret := &list.Items[i]
+		// Skip service account tokens and other system secrets.
+		if secret.Type == corev1.SecretTypeServiceAccountToken ||
+			strings.HasPrefix(secret.Name, "default-token-") ||
+			strings.Contains(secret.Name, "token-") {
+			continue
+		}
+		objects = append(objects, secret)
+	}
+	return objects, nil
+}
+
+type persistentVolumeClaimsHandler struct{}
+
+func (persistentVolumeClaimsHandler) GVK() schema.GroupVersionKind {
+	return corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim")
+}
+func (persistentVolumeClaimsHandler) PathPrefix() string { return "persistentvolumeclaims" }
+func (persistentVolumeClaimsHandler) List(ctx context.Context, r *BackupReconciler, namespace string, source backupv1.BackupSource) ([]client.Object, error) {
+	opts, err := listOptsFor(namespace, source)
+	if err != nil {
+		return nil, err
+	}
+	var list corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list persistentvolumeclaims: %w", err)
+	}
+	objects := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		objects = append(objects, &list.Items[i])
+	}
+	return objects, nil
+}
+
+type ingressesHandler struct{}
+
+func (ingressesHandler) GVK() schema.GroupVersionKind { return networkingv1.SchemeGroupVersion.WithKind("Ingress") }
+func (ingressesHandler) PathPrefix() string           { return "ingresses" }
+func (ingressesHandler) List(ctx context.Context, r *BackupReconciler, namespace string, source backupv1.BackupSource) ([]client.Object, error) {
+	opts, err := listOptsFor(namespace, source)
+	if err != nil {
+		return nil, err
+	}
+	var list networkingv1.IngressList
+	if err := r.List(ctx, &list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %w", err)
+	}
+	objects := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		objects = append(objects, &list.Items[i])
+	}
+	return objects, nil
+}
+
+type clusterRolesHandler struct{}
+
+func (clusterRolesHandler) GVK() schema.GroupVersionKind {
+	return rbacv1.SchemeGroupVersion.WithKind("ClusterRole")
+}
+func (clusterRolesHandler) PathPrefix() string { return "clusterroles" }
+func (clusterRolesHandler) List(ctx context.Context, r *BackupReconciler, namespace string, source backupv1.BackupSource) ([]client.Object, error) {
+	opts, err := listOptsFor("", source)
+	if err != nil {
+		return nil, err
+	}
+	var list rbacv1.ClusterRoleList
+	if err := r.List(ctx, &list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list clusterroles: %w", err)
+	}
+	objects := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		objects = append(objects, &list.Items[i])
+	}
+	return objects, nil
+}
+
+type clusterRoleBindingsHandler struct{}
+
+func (clusterRoleBindingsHandler) GVK() schema.GroupVersionKind {
+	return rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding")
+}
+func (clusterRoleBindingsHandler) PathPrefix() string { return "clusterrolebindings" }
+func (clusterRoleBindingsHandler) List(ctx context.Context, r *BackupReconciler, namespace string, source backupv1.BackupSource) ([]client.Object, error) {
+	opts, err := listOptsFor("", source)
+	if err != nil {
+		return nil, err
+	}
+	var list rbacv1.ClusterRoleBindingList
+	if err := r.List(ctx, &list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list clusterrolebindings: %w", err)
+	}
+	objects := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		objects = append(objects, &list.Items[i])
+	}
+	return objects, nil
+}
+
+type persistentVolumesHandler struct{}
+
+func (persistentVolumesHandler) GVK() schema.GroupVersionKind {
+	return corev1.SchemeGroupVersion.WithKind("PersistentVolume")
+}
+func (persistentVolumesHandler) PathPrefix() string { return "persistentvolumes" }
+func (persistentVolumesHandler) List(ctx context.Context, r *BackupReconciler, namespace string, source backupv1.BackupSource) ([]client.Object, error) {
+	opts, err := listOptsFor("", source)
+	if err != nil {
+		return nil, err
+	}
+	var list corev1.PersistentVolumeList
+	if err := r.List(ctx, &list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list persistentvolumes: %w", err)
+	}
+	objects := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		objects = append(objects, &list.Items[i])
+	}
+	return objects, nil
+}
+
+type storageClassesHandler struct{}
+
+func (storageClassesHandler) GVK() schema.GroupVersionKind {
+	return storagev1.SchemeGroupVersion.WithKind("StorageClass")
+}
+func (storageClassesHandler) PathPrefix() string { return "storageclasses" }
+func (storageClassesHandler) List(ctx context.Context, r *BackupReconciler, namespace string, source backupv1.BackupSource) ([]client.Object, error) {
+	opts, err := listOptsFor("", source)
+	if err != nil {
+		return nil, err
+	}
+	var list storagev1.StorageClassList
+	if err := r.List(ctx, &list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list storageclasses: %w", err)
+	}
+	objects := make([]client.Object, 0, len(list.Items))
+	for i := range list.Items {
+		objects = append(objects, &list.Items[i])
+	}
+	return objects, nil
+}
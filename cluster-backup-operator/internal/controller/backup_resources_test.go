@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// TestResourceHandlerRegistries verifies every resourceType string accepted elsewhere
+// in the package (getResourceTypesToBackup, getClusterResourceTypes) resolves to a
+// registered handler whose PathPrefix matches the on-disk folder name the restore
+// controller expects.
+func TestResourceHandlerRegistries(t *testing.T) {
+	reconciler := &BackupReconciler{}
+
+	for _, resourceType := range reconciler.getResourceTypesToBackup(backupv1.BackupSource{}) {
+		handler, ok := namespacedResourceHandlers[resourceType]
+		if !ok {
+			t.Errorf("no namespaced handler registered for %q", resourceType)
+			continue
+		}
+		if handler.PathPrefix() != resourceType {
+			t.Errorf("handler for %q has PathPrefix %q, want %q", resourceType, handler.PathPrefix(), resourceType)
+		}
+	}
+
+	for _, resourceType := range reconciler.getClusterResourceTypes() {
+		handler, ok := clusterResourceHandlers[resourceType]
+		if !ok {
+			t.Errorf("no cluster handler registered for %q", resourceType)
+			continue
+		}
+		if handler.PathPrefix() != resourceType {
+			t.Errorf("handler for %q has PathPrefix %q, want %q", resourceType, handler.PathPrefix(), resourceType)
+		}
+	}
+}
+
+func testConfigMap(name, namespace string, labels map[string]string) client.Object {
+	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}}
+}
+
+func TestFilterBackupObjectsExcludedResources(t *testing.T) {
+	objects := []client.Object{
+		testConfigMap("app-config", "ns", nil),
+		testConfigMap("db-creds", "ns", nil),
+	}
+
+	filtered, err := filterBackupObjects(objects, "configmaps", backupv1.BackupSource{
+		ExcludedResources: []string{"configmaps/db-creds"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].GetName() != "app-config" {
+		t.Fatalf("expected only app-config to survive, got %v", filtered)
+	}
+}
+
+func TestFilterBackupObjectsNoPolicyIsNoOp(t *testing.T) {
+	objects := []client.Object{testConfigMap("app-config", "ns", nil)}
+
+	filtered, err := filterBackupObjects(objects, "configmaps", backupv1.BackupSource{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(filtered) != 1 {
+		t.Fatalf("expected the object list to pass through unchanged, got %v", filtered)
+	}
+}
+
+func TestFilterBackupObjectsOrLabelSelectors(t *testing.T) {
+	objects := []client.Object{
+		testConfigMap("team-a", "ns", map[string]string{"team": "a"}),
+		testConfigMap("team-b", "ns", map[string]string{"team": "b"}),
+		testConfigMap("team-c", "ns", map[string]string{"team": "c"}),
+	}
+
+	filtered, err := filterBackupObjects(objects, "configmaps", backupv1.BackupSource{
+		OrLabelSelectors: []metav1.LabelSelector{
+			{MatchLabels: map[string]string{"team": "a"}},
+			{MatchLabels: map[string]string{"team": "b"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(filtered) != 2 {
+		t.Fatalf("expected 2 objects to match either selector, got %d", len(filtered))
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// enforceRetention finds every backup under backupRootPrefix not covered by policy and
+// requests its deletion via a BackupDeletionRequest (see requestRetentionDeletion)
+// rather than deleting its objects inline, so a long-running delete never blocks this
+// reconcile from getting to scheduling the next backup. It also syncs an equivalent
+// bucket lifecycle rule (for providers implementing LifecycleConfigurer) so pruning
+// still happens if the operator is offline. A nil policy is a no-op.
+func (r *BackupReconciler) enforceRetention(ctx context.Context, backup *backupv1.Backup, provider storage.Provider, bucket string, policy *backupv1.RetentionPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	log := logf.FromContext(ctx)
+
+	keys, err := provider.ListObjects(ctx, bucket, backupRootPrefix+"/")
+	if err != nil {
+		return fmt.Errorf("failed to list backups for retention: %w", err)
+	}
+
+	names := backupNamesFromKeys(keys)
+	keep := backupsToKeep(names, *policy)
+
+	pruned := 0
+	for _, name := range names {
+		if keep[name] {
+			continue
+		}
+		if err := r.requestRetentionDeletion(ctx, backup, name); err != nil {
+			return fmt.Errorf("failed to request deletion of pruned backup %s: %w", name, err)
+		}
+		pruned++
+	}
+	if pruned > 0 {
+		log.Info("Requested deletion of backups outside retention policy", "bucket", bucket, "backupsRequested", pruned)
+	}
+
+	if lc, ok := provider.(storage.LifecycleConfigurer); ok {
+		if err := lc.ConfigureLifecycle(ctx, bucket, *policy); err != nil {
+			return fmt.Errorf("failed to sync bucket lifecycle for retention: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// backupNameFromKey returns the backup path segment (e.g. "2026-01-02T15-04-05")
+// immediately under backupRootPrefix that key belongs to, or "" if key is not under
+// backupRootPrefix at all.
+func backupNameFromKey(key string) string {
+	rest := strings.TrimPrefix(key, backupRootPrefix+"/")
+	if rest == key {
+		return ""
+	}
+	if idx := strings.Index(rest, "/"); idx >= 0 {
+		return rest[:idx]
+	}
+	return rest
+}
+
+// datedBackup pairs a backup name with its parsed timestamp, for the Keep* window
+// calculations in backupsToKeep.
+type datedBackup struct {
+	name string
+	ts   time.Time
+}
+
+// backupsToKeep returns the set of backup names retained by policy out of names
+// (oldest-first, as returned by backupNamesFromKeys). A name that does not parse as
+// backupTimestampLayout is always kept, since automatically deleting something this
+// code cannot date would be unsafe.
+func backupsToKeep(names []string, policy backupv1.RetentionPolicy) map[string]bool {
+	keep := make(map[string]bool)
+
+	var dated []datedBackup
+	for _, name := range names {
+		ts, err := time.Parse(backupTimestampLayout, name)
+		if err != nil {
+			keep[name] = true
+			continue
+		}
+		dated = append(dated, datedBackup{name: name, ts: ts})
+	}
+
+	// MaxAge is a hard ceiling applied before the Keep* windows below are considered,
+	// so a backup it covers is removed even if it would also be the most recent in its
+	// KeepDaily/KeepWeekly/KeepMonthly window.
+	if policy.MaxAge != nil {
+		cutoff := time.Now().Add(-policy.MaxAge.Duration)
+		var withinMaxAge []datedBackup
+		for _, d := range dated {
+			if !d.ts.Before(cutoff) {
+				withinMaxAge = append(withinMaxAge, d)
+			}
+		}
+		dated = withinMaxAge
+	}
+
+	if n := int(policy.KeepLast); n > 0 {
+		if n > len(dated) {
+			n = len(dated)
+		}
+		for _, d := range dated[len(dated)-n:] {
+			keep[d.name] = true
+		}
+	}
+
+	keepMostRecentPerBucket(dated, int(policy.KeepDaily), keep, func(t time.Time) string {
+		return t.Format("2006-01-02")
+	})
+	keepMostRecentPerBucket(dated, int(policy.KeepWeekly), keep, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	})
+	keepMostRecentPerBucket(dated, int(policy.KeepMonthly), keep, func(t time.Time) string {
+		return t.Format("2006-01")
+	})
+
+	// MaxCount is applied last: once the Keep* windows above have decided what would
+	// otherwise be retained, trim that set down to the N most recent if it still
+	// exceeds the cap.
+	if n := int(policy.MaxCount); n > 0 {
+		applyMaxCount(dated, n, keep)
+	}
+
+	return keep
+}
+
+// applyMaxCount drops the oldest entries of dated currently marked in keep until at
+// most maxCount remain, leaving names not in keep (and names outside dated entirely,
+// i.e. ones that failed to parse) untouched.
+func applyMaxCount(dated []datedBackup, maxCount int, keep map[string]bool) {
+	var kept []datedBackup
+	for _, d := range dated {
+		if keep[d.name] {
+			kept = append(kept, d)
+		}
+	}
+	if len(kept) <= maxCount {
+		return
+	}
+	for _, d := range kept[:len(kept)-maxCount] {
+		delete(keep, d.name)
+	}
+}
+
+// keepMostRecentPerBucket keeps the most recent backup in each of the last n distinct
+// buckets (as returned by bucketOf), scanning dated newest-first, and marks it in the
+// shared keep set so KeepDaily/KeepWeekly/KeepMonthly/KeepLast compose into one union
+// of retained backups rather than overwriting each other.
+func keepMostRecentPerBucket(dated []datedBackup, n int, keep map[string]bool, bucketOf func(time.Time) string) {
+	if n <= 0 {
+		return
+	}
+	seen := make(map[string]bool, n)
+	for i := len(dated) - 1; i >= 0 && len(seen) < n; i-- {
+		b := bucketOf(dated[i].ts)
+		if seen[b] {
+			continue
+		}
+		seen[b] = true
+		keep[dated[i].name] = true
+	}
+}
+
+// requestRetentionDeletion creates a BackupDeletionRequest pruning backupName's
+// objects out from under backup (used for a historical run retention decided is no
+// longer covered by the policy), tolerating AlreadyExists so a reconcile retried after
+// a partial failure elsewhere doesn't error out re-requesting the same prune.
+func (r *BackupReconciler) requestRetentionDeletion(ctx context.Context, backup *backupv1.Backup, backupName string) error {
+	req := &backupv1.BackupDeletionRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      retentionDeletionRequestName(backup.Name, backupName),
+			Namespace: backup.Namespace,
+		},
+		Spec: backupv1.BackupDeletionRequestSpec{
+			BackupName:      backup.Name,
+			BackupNamespace: backup.Namespace,
+			BackupPath:      backupRootPrefix + "/" + backupName,
+		},
+	}
+	if err := r.Create(ctx, req); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// retentionDeletionRequestName derives a stable, idempotent BackupDeletionRequest name
+// for pruning one historical run of backup.
+func retentionDeletionRequestName(backupOwnerName, prunedBackupName string) string {
+	return fmt.Sprintf("%s-retention-%s", backupOwnerName, strings.ToLower(prunedBackupName))
+}
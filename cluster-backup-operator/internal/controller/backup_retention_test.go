@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestBackupsToKeepKeepLast verifies that KeepLast alone retains only the N most
+// recent backups.
+func TestBackupsToKeepKeepLast(t *testing.T) {
+	names := []string{
+		"2026-01-01T00-00-00",
+		"2026-01-02T00-00-00",
+		"2026-01-03T00-00-00",
+	}
+	keep := backupsToKeep(names, backupv1.RetentionPolicy{KeepLast: 2})
+
+	if keep["2026-01-01T00-00-00"] {
+		t.Errorf("expected oldest backup to be pruned, but it was kept")
+	}
+	if !keep["2026-01-02T00-00-00"] || !keep["2026-01-03T00-00-00"] {
+		t.Errorf("expected the 2 most recent backups to be kept, got %+v", keep)
+	}
+}
+
+// TestBackupsToKeepKeepDailyDedupesPerDay verifies that KeepDaily keeps only the most
+// recent backup from each distinct calendar day, not every backup taken that day.
+func TestBackupsToKeepKeepDailyDedupesPerDay(t *testing.T) {
+	names := []string{
+		"2026-01-01T00-00-00",
+		"2026-01-01T12-00-00",
+		"2026-01-02T00-00-00",
+	}
+	keep := backupsToKeep(names, backupv1.RetentionPolicy{KeepDaily: 2})
+
+	if keep["2026-01-01T00-00-00"] {
+		t.Errorf("expected the earlier same-day backup to be pruned")
+	}
+	if !keep["2026-01-01T12-00-00"] {
+		t.Errorf("expected the later same-day backup to be kept")
+	}
+	if !keep["2026-01-02T00-00-00"] {
+		t.Errorf("expected the most recent day's backup to be kept")
+	}
+}
+
+// TestBackupsToKeepUnparsableNameAlwaysKept verifies that a backup name not matching
+// backupTimestampLayout is never pruned, since the policy cannot reason about its age.
+func TestBackupsToKeepUnparsableNameAlwaysKept(t *testing.T) {
+	names := []string{"not-a-timestamp", "2026-01-01T00-00-00", "2026-01-02T00-00-00"}
+	keep := backupsToKeep(names, backupv1.RetentionPolicy{KeepLast: 1})
+
+	if !keep["not-a-timestamp"] {
+		t.Errorf("expected unparsable backup name to be kept unconditionally")
+	}
+	if !keep["2026-01-02T00-00-00"] {
+		t.Errorf("expected the most recent parsable backup to be kept")
+	}
+	if keep["2026-01-01T00-00-00"] {
+		t.Errorf("expected the older parsable backup to be pruned")
+	}
+}
+
+// TestBackupsToKeepMaxAge verifies that MaxAge prunes backups older than the cutoff
+// even though KeepLast would otherwise have covered them.
+func TestBackupsToKeepMaxAge(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-48 * time.Hour).Format(backupTimestampLayout)
+	recent := now.Add(-1 * time.Hour).Format(backupTimestampLayout)
+	names := []string{old, recent}
+
+	keep := backupsToKeep(names, backupv1.RetentionPolicy{
+		MaxAge:   &metav1.Duration{Duration: 24 * time.Hour},
+		KeepLast: 2,
+	})
+
+	if keep[old] {
+		t.Errorf("expected backup older than MaxAge to be pruned despite KeepLast, got %+v", keep)
+	}
+	if !keep[recent] {
+		t.Errorf("expected backup within MaxAge to be kept, got %+v", keep)
+	}
+}
+
+// TestBackupsToKeepMaxCount verifies that MaxCount trims the Keep* windows' result
+// down to the N most recent backups when more than N would otherwise be kept.
+func TestBackupsToKeepMaxCount(t *testing.T) {
+	names := []string{
+		"2026-01-01T00-00-00",
+		"2026-01-02T00-00-00",
+		"2026-01-03T00-00-00",
+	}
+	keep := backupsToKeep(names, backupv1.RetentionPolicy{KeepLast: 3, MaxCount: 1})
+
+	if keep["2026-01-01T00-00-00"] || keep["2026-01-02T00-00-00"] {
+		t.Errorf("expected MaxCount to prune all but the most recent backup, got %+v", keep)
+	}
+	if !keep["2026-01-03T00-00-00"] {
+		t.Errorf("expected the most recent backup to be kept, got %+v", keep)
+	}
+}
+
+// TestBackupNameFromKey covers keys under backupRootPrefix, its chunks sub-prefix, and
+// keys outside it entirely.
+func TestBackupNameFromKey(t *testing.T) {
+	cases := map[string]string{
+		"backups/cluster-backup/2026-01-01T00-00-00/namespaces/default/configmaps/a.json": "2026-01-01T00-00-00",
+		"backups/cluster-backup/2026-01-01T00-00-00/manifest.json":                        "2026-01-01T00-00-00",
+		"backups/cluster-backup/chunks/ab/abcdef.json":                                    "chunks",
+		"some/other/key.json":                                                             "",
+	}
+	for key, want := range cases {
+		if got := backupNameFromKey(key); got != want {
+			t.Errorf("backupNameFromKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+)
+
+const (
+	// defaultVolumeSnapshotClassKey is the VolumeSnapshotClassMapping key used when
+	// a PVC's StorageClass has no explicit entry.
+	defaultVolumeSnapshotClassKey = "default"
+
+	// snapshotReadyPollInterval is how often we poll a VolumeSnapshot for readiness.
+	snapshotReadyPollInterval = 5 * time.Second
+
+	// snapshotReadyTimeout bounds how long we wait for a VolumeSnapshot to become ready
+	// before giving up and recording it as not-ready-yet rather than failing the backup.
+	snapshotReadyTimeout = 5 * time.Minute
+
+	// snapshotRetainDeletionPolicy is patched onto the bound VolumeSnapshotContent so that
+	// deleting the VolumeSnapshot (e.g. alongside its namespace) does not delete the
+	// underlying storage-side snapshot the backup depends on.
+	snapshotRetainDeletionPolicy = snapshotv1.VolumeSnapshotContentRetain
+)
+
+// snapshotPersistentVolumeClaim creates a CSI VolumeSnapshot for pvc, waits for it to
+// become ready (best-effort, bounded by snapshotReadyTimeout), uploads the VolumeSnapshot
+// and its bound VolumeSnapshotContent alongside the PVC manifest, and returns a status
+// record. If the snapshot.storage.k8s.io CRDs are not installed on the cluster, it logs
+// and returns (nil, nil) so clusters without CSI snapshotting still back up successfully.
+func (r *BackupReconciler) snapshotPersistentVolumeClaim(ctx context.Context, provider storage.Provider, bucket, backupPath string, backup *backupv1.Backup, pvc *corev1.PersistentVolumeClaim, dedup *dedupState, pack *packagingState) (*backupv1.VolumeSnapshotRecord, error) {
+	log := logf.FromContext(ctx)
+
+	snapshotClass := resolveVolumeSnapshotClass(backup.Spec.Source.VolumeSnapshotClassMapping, pvc.Spec.StorageClassName)
+
+	vs := &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", pvc.Name, backup.Name),
+			Namespace: pvc.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(backup, backupv1.GroupVersion.WithKind("Backup")),
+			},
+		},
+		Spec: snapshotv1.VolumeSnapshotSpec{
+			Source: snapshotv1.VolumeSnapshotSource{
+				PersistentVolumeClaimName: &pvc.Name,
+			},
+			VolumeSnapshotClassName: &snapshotClass,
+		},
+	}
+
+	if err := r.Create(ctx, vs); err != nil {
+		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			log.Info("snapshot.storage.k8s.io CRDs not installed, skipping volume snapshot",
+				"persistentvolumeclaim", pvc.Name, "namespace", pvc.Namespace)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to create volumesnapshot for pvc %s: %w", pvc.Name, err)
+	}
+
+	readyTimeout := snapshotReadyTimeout
+	if backup.Spec.Source.CSISnapshotTimeout != nil {
+		readyTimeout = backup.Spec.Source.CSISnapshotTimeout.Duration
+	}
+
+	ready, err := r.waitForSnapshotReady(ctx, vs, readyTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for volumesnapshot %s to become ready: %w", vs.Name, err)
+	}
+	vs = ready
+
+	record := &backupv1.VolumeSnapshotRecord{
+		PVCName:            pvc.Name,
+		Namespace:          pvc.Namespace,
+		VolumeSnapshotName: vs.Name,
+		ReadyToUse:         vs.Status != nil && vs.Status.ReadyToUse != nil && *vs.Status.ReadyToUse,
+	}
+	if pvc.Spec.StorageClassName != nil {
+		record.SourceStorageClass = *pvc.Spec.StorageClassName
+	}
+
+	// VolumeSnapshot/VolumeSnapshotContent are operator-internal bookkeeping objects
+	// uploaded alongside a PVC's own manifest, not resources a user-facing ItemAction
+	// plugin is expected to target, so no itemActionState is threaded through here.
+	objectPrefix := fmt.Sprintf("%s/namespaces/%s/volumesnapshots/%s", backupPath, pvc.Namespace, vs.Name)
+	if err := r.uploadResource(ctx, provider, bucket, backupPath, objectPrefix+".json", vs, schema.GroupVersionKind{}, "", nil, dedup, pack); err != nil {
+		return nil, fmt.Errorf("failed to upload volumesnapshot %s: %w", vs.Name, err)
+	}
+
+	if vs.Status != nil && vs.Status.BoundVolumeSnapshotContentName != nil {
+		contentName := *vs.Status.BoundVolumeSnapshotContentName
+		record.VolumeSnapshotContentName = contentName
+
+		vsc := &snapshotv1.VolumeSnapshotContent{}
+		if err := r.Get(ctx, client.ObjectKey{Name: contentName}, vsc); err != nil {
+			return nil, fmt.Errorf("failed to get volumesnapshotcontent %s: %w", contentName, err)
+		}
+
+		if vsc.Status != nil && vsc.Status.SnapshotHandle != nil {
+			record.SnapshotHandle = *vsc.Status.SnapshotHandle
+		}
+
+		if vsc.Spec.DeletionPolicy != snapshotRetainDeletionPolicy {
+			vsc.Spec.DeletionPolicy = snapshotRetainDeletionPolicy
+			if err := r.Update(ctx, vsc); err != nil {
+				return nil, fmt.Errorf("failed to patch deletionPolicy on volumesnapshotcontent %s: %w", contentName, err)
+			}
+		}
+
+		if err := r.uploadResource(ctx, provider, bucket, backupPath, objectPrefix+"-content.json", vsc, schema.GroupVersionKind{}, "", nil, dedup, pack); err != nil {
+			return nil, fmt.Errorf("failed to upload volumesnapshotcontent %s: %w", contentName, err)
+		}
+	}
+
+	return record, nil
+}
+
+// resolveVolumeSnapshotClass looks up the entry for key in mapping, falling back to
+// the "default" entry, and finally to an empty string. Used both to pick a
+// VolumeSnapshotClass for a PVC's StorageClass during backup, and to remap a backed-up
+// VolumeSnapshot's VolumeSnapshotClassName to one that exists in the restore target
+// cluster.
+func resolveVolumeSnapshotClass(mapping map[string]string, key *string) string {
+	if mapping == nil {
+		return ""
+	}
+	if key != nil {
+		if class, ok := mapping[*key]; ok {
+			return class
+		}
+	}
+	return mapping[defaultVolumeSnapshotClassKey]
+}
+
+// waitForSnapshotReady polls the VolumeSnapshot until status.readyToUse is true or
+// timeout elapses, in which case the last observed state is returned without error so
+// the backup can proceed with a not-yet-ready snapshot recorded.
+func (r *BackupReconciler) waitForSnapshotReady(ctx context.Context, vs *snapshotv1.VolumeSnapshot, timeout time.Duration) (*snapshotv1.VolumeSnapshot, error) {
+	log := logf.FromContext(ctx)
+	deadline := time.Now().Add(timeout)
+
+	for {
+		current := &snapshotv1.VolumeSnapshot{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(vs), current); err != nil {
+			return nil, fmt.Errorf("failed to get volumesnapshot %s: %w", vs.Name, err)
+		}
+
+		if current.Status != nil && current.Status.ReadyToUse != nil && *current.Status.ReadyToUse {
+			return current, nil
+		}
+
+		if time.Now().After(deadline) {
+			log.Info("timed out waiting for volumesnapshot to become ready, recording current state",
+				"volumesnapshot", vs.Name, "namespace", vs.Namespace)
+			return current, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(snapshotReadyPollInterval):
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+)
+
+func newSnapshotTestScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	if err := backupv1.AddToScheme(s); err != nil {
+		t.Fatalf("failed to add backupv1 to scheme: %v", err)
+	}
+	if err := snapshotv1.AddToScheme(s); err != nil {
+		t.Fatalf("failed to add snapshotv1 to scheme: %v", err)
+	}
+	return s
+}
+
+// TestWaitForSnapshotReadyReturnsImmediatelyWhenReady verifies the happy path never
+// sleeps: a VolumeSnapshot that is already readyToUse is returned on the first poll.
+func TestWaitForSnapshotReadyReturnsImmediatelyWhenReady(t *testing.T) {
+	scheme := newSnapshotTestScheme(t)
+	ready := true
+	vs := &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-data-backup", Namespace: "default"},
+		Status:     &snapshotv1.VolumeSnapshotStatus{ReadyToUse: &ready},
+	}
+	r := &BackupReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(vs).Build()}
+
+	got, err := r.waitForSnapshotReady(context.Background(), vs, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status == nil || got.Status.ReadyToUse == nil || !*got.Status.ReadyToUse {
+		t.Errorf("expected the already-ready volumesnapshot to be reported ready, got status=%+v", got.Status)
+	}
+}
+
+// TestWaitForSnapshotReadyTimesOutWithoutError verifies that a VolumeSnapshot that
+// never becomes ready is returned as-is once the timeout elapses, rather than failing
+// the backup outright.
+func TestWaitForSnapshotReadyTimesOutWithoutError(t *testing.T) {
+	scheme := newSnapshotTestScheme(t)
+	vs := &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-data-backup", Namespace: "default"},
+	}
+	r := &BackupReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(vs).Build()}
+
+	got, err := r.waitForSnapshotReady(context.Background(), vs, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != nil && got.Status.ReadyToUse != nil && *got.Status.ReadyToUse {
+		t.Errorf("expected the not-ready volumesnapshot to still be reported not ready after timing out")
+	}
+}
+
+// TestResolveVolumeSnapshotClass tests storage-class-to-snapshot-class resolution
+func TestResolveVolumeSnapshotClass(t *testing.T) {
+	fastClass := "fast-ssd"
+
+	mapping := map[string]string{
+		"fast-ssd": "fast-ssd-snapclass",
+		"default":  "default-snapclass",
+	}
+
+	if got := resolveVolumeSnapshotClass(mapping, &fastClass); got != "fast-ssd-snapclass" {
+		t.Errorf("expected mapped snapshot class 'fast-ssd-snapclass', got '%s'", got)
+	}
+
+	otherClass := "unmapped-class"
+	if got := resolveVolumeSnapshotClass(mapping, &otherClass); got != "default-snapclass" {
+		t.Errorf("expected fallback to 'default-snapclass', got '%s'", got)
+	}
+
+	if got := resolveVolumeSnapshotClass(nil, &fastClass); got != "" {
+		t.Errorf("expected empty string when no mapping configured, got '%s'", got)
+	}
+}
+
+// TestSnapshotPersistentVolumeClaimRecordsSourceStorageClass verifies the returned
+// VolumeSnapshotRecord captures the PVC's StorageClassName, so a restore can re-resolve
+// a VolumeSnapshotClass without needing the original PVC manifest.
+func TestSnapshotPersistentVolumeClaimRecordsSourceStorageClass(t *testing.T) {
+	scheme := newSnapshotTestScheme(t)
+	backup := &backupv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+		Spec: backupv1.BackupSpec{
+			Source: backupv1.BackupSource{CSISnapshotTimeout: &metav1.Duration{Duration: 0}},
+		},
+	}
+	r := &BackupReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(backup).Build()}
+
+	provider, err := storage.NewLocalProvider(backupv1.StorageLocation{Provider: "local", Endpoint: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create local provider: %v", err)
+	}
+
+	storageClass := "fast-ssd"
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-data", Namespace: "default"},
+		Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass},
+	}
+
+	record, err := r.snapshotPersistentVolumeClaim(context.Background(), provider, "backups", "backups/ts", backup, pvc, newDedupState(), newPackagingState())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record == nil {
+		t.Fatal("expected a VolumeSnapshotRecord, got nil")
+	}
+	if record.SourceStorageClass != "fast-ssd" {
+		t.Errorf("expected SourceStorageClass 'fast-ssd', got '%s'", record.SourceStorageClass)
+	}
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+	"github.com/rfashwall/cnd-workshop/pkg/volumehelper"
+)
+
+const (
+	// fsBackupDaemonSetName is the per-namespace DaemonSet ensured before a "fs-backup"
+	// action runs, one pod per node with the host's kubelet pod-volumes directory
+	// mounted, so the backup can exec in on whichever node currently hosts the PVC.
+	fsBackupDaemonSetName = "cnd-workshop-fsbackup-agent"
+
+	// fsBackupContainerName is the single container name inside fsBackupDaemonSetName.
+	fsBackupContainerName = "agent"
+
+	// fsBackupAgentImage is the image the agent pods run; it only needs `tar`, so a
+	// minimal image is sufficient.
+	fsBackupAgentImage = "busybox:1.36"
+
+	// fsBackupHostPodsDir is where kubelet keeps each pod's mounted volumes, used to
+	// locate a PVC's data on the node that currently mounts it.
+	fsBackupHostPodsDir = "/var/lib/kubelet/pods"
+)
+
+// resolveVolumeAction decides how pvc's data should be backed up: by evaluating
+// backup.Spec.Source.VolumePolicy against pvc's attributes, or, if VolumePolicy is
+// empty or no rule matches, by falling back to the legacy SnapshotVolumes toggle. The
+// returned reason is recorded alongside the decision in Status.VolumeBackups.
+func (r *BackupReconciler) resolveVolumeAction(ctx context.Context, backup *backupv1.Backup, pvc *corev1.PersistentVolumeClaim) (backupv1.VolumeAction, string, error) {
+	rules := backup.Spec.Source.VolumePolicy
+	if len(rules) == 0 {
+		return legacyVolumeAction(backup.Spec.Source.SnapshotVolumes), "no VolumePolicy configured, fell back to SnapshotVolumes", nil
+	}
+
+	vctx, err := r.buildVolumeContext(ctx, pvc)
+	if err != nil {
+		return backupv1.VolumeAction{}, "", err
+	}
+
+	action, ruleIndex, matched := volumehelper.Resolve(rules, vctx)
+	if !matched {
+		return legacyVolumeAction(backup.Spec.Source.SnapshotVolumes), "no VolumePolicy rule matched, fell back to SnapshotVolumes", nil
+	}
+	return action, fmt.Sprintf("matched VolumePolicy rule %d", ruleIndex), nil
+}
+
+// legacyVolumeAction reproduces SnapshotVolumes' pre-VolumePolicy behavior: snapshot
+// every PVC if true, skip every PVC if false.
+func legacyVolumeAction(snapshotVolumes bool) backupv1.VolumeAction {
+	if snapshotVolumes {
+		return backupv1.VolumeAction{Type: backupv1.VolumeActionSnapshot}
+	}
+	return backupv1.VolumeAction{Type: backupv1.VolumeActionSkip}
+}
+
+// buildVolumeContext gathers the attributes pvc's VolumePolicy rules can match on: its
+// StorageClass's CSI driver and whether any running pod currently mounts it.
+func (r *BackupReconciler) buildVolumeContext(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (volumehelper.VolumeContext, error) {
+	vctx := volumehelper.VolumeContext{
+		AccessModes: pvc.Spec.AccessModes,
+	}
+
+	if pvc.Spec.StorageClassName != nil {
+		vctx.StorageClassName = *pvc.Spec.StorageClassName
+	}
+	if q, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		vctx.CapacityBytes = q.Value()
+	}
+
+	if vctx.StorageClassName != "" {
+		sc := &storagev1.StorageClass{}
+		if err := r.Get(ctx, client.ObjectKey{Name: vctx.StorageClassName}, sc); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return vctx, fmt.Errorf("failed to get storageclass %s: %w", vctx.StorageClassName, err)
+			}
+		} else {
+			vctx.CSIDriver = sc.Provisioner
+		}
+	}
+
+	mountingPod, err := r.podMountingPVC(ctx, pvc)
+	if err != nil {
+		return vctx, err
+	}
+	vctx.PodMounted = mountingPod != nil
+
+	return vctx, nil
+}
+
+// podMountingPVC returns a running pod in pvc's namespace that currently mounts it, or
+// nil if none does.
+func (r *BackupReconciler) podMountingPVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (*corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(pvc.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list pods to resolve mount state for persistentvolumeclaim %s: %w", pvc.Name, err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, vol := range pod.Spec.Volumes {
+			if vol.PersistentVolumeClaim != nil && vol.PersistentVolumeClaim.ClaimName == pvc.Name {
+				return pod, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// fsBackupPersistentVolumeClaim backs up pvc's data by tar-streaming it, via the
+// fsBackupDaemonSetName agent pod on the node currently mounting it, to
+// backupPath/namespaces/<namespace>/persistentvolumeclaims/<name>-fsbackup.tar.gz. It
+// requires pvc to be mounted by a running pod, since that is the only way this
+// controller can learn which node holds its data.
+func (r *BackupReconciler) fsBackupPersistentVolumeClaim(ctx context.Context, provider storage.Provider, bucket, backupPath string, pvc *corev1.PersistentVolumeClaim) error {
+	mountingPod, err := r.podMountingPVC(ctx, pvc)
+	if err != nil {
+		return err
+	}
+	if mountingPod == nil {
+		return fmt.Errorf("fs-backup requires persistentvolumeclaim %s to be mounted by a running pod to identify its node", pvc.Name)
+	}
+
+	if err := ensureFsBackupDaemonSet(ctx, r.Client, pvc.Namespace); err != nil {
+		return fmt.Errorf("failed to ensure fs-backup agent daemonset: %w", err)
+	}
+
+	agentPod, err := fsBackupAgentPodOnNode(ctx, r.Client, pvc.Namespace, mountingPod.Spec.NodeName)
+	if err != nil {
+		return err
+	}
+
+	hostPath := fmt.Sprintf("%s/%s/volumes/kubernetes.io~csi/%s/mount", fsBackupHostPodsDir, mountingPod.UID, pvc.Spec.VolumeName)
+	objectName := fmt.Sprintf("%s/namespaces/%s/persistentvolumeclaims/%s-fsbackup.tar.gz", backupPath, pvc.Namespace, pvc.Name)
+
+	return r.streamTarToStorage(ctx, provider, bucket, objectName, agentPod, hostPath)
+}
+
+// ensureFsBackupDaemonSet creates the fs-backup agent DaemonSet in namespace if it does
+// not already exist. The agent container only needs to sit idle and wait to be exec'd
+// into, so its Command replaces the image's entrypoint with /bin/sh -c "sleep infinity".
+// Shared by BackupReconciler (to stream a PVC's contents out) and RestoreReconciler (to
+// stream a data-mover manifest's contents back in), since both just need an agent pod
+// with the node's kubelet pod-volumes directory mounted to exec into.
+func ensureFsBackupDaemonSet(ctx context.Context, cl client.Client, namespace string) error {
+	existing := &appsv1.DaemonSet{}
+	err := cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: fsBackupDaemonSetName}, existing)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	hostPathDirectory := corev1.HostPathDirectory
+	privileged := true
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fsBackupDaemonSetName,
+			Namespace: namespace,
+			Labels:    map[string]string{"app": fsBackupDaemonSetName},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": fsBackupDaemonSetName},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": fsBackupDaemonSetName},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:            fsBackupContainerName,
+							Image:           fsBackupAgentImage,
+							Command:         []string{"/bin/sh", "-c", "sleep infinity"},
+							SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "kubelet-pods", MountPath: fsBackupHostPodsDir, ReadOnly: true},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "kubelet-pods",
+							VolumeSource: corev1.VolumeSource{
+								HostPath: &corev1.HostPathVolumeSource{Path: fsBackupHostPodsDir, Type: &hostPathDirectory},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := cl.Create(ctx, ds); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// fsBackupAgentPodOnNode returns a running fsBackupDaemonSetName pod scheduled on
+// nodeName, erroring if the DaemonSet has not yet scheduled one there (e.g. it was just
+// created and the node has not reconciled it yet). Shared by BackupReconciler and
+// RestoreReconciler; see ensureFsBackupDaemonSet.
+func fsBackupAgentPodOnNode(ctx context.Context, cl client.Client, namespace, nodeName string) (*corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := cl.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels{"app": fsBackupDaemonSetName}); err != nil {
+		return nil, fmt.Errorf("failed to list fs-backup agent pods: %w", err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == nodeName && pod.Status.Phase == corev1.PodRunning {
+			return pod, nil
+		}
+	}
+	return nil, fmt.Errorf("no running fs-backup agent pod scheduled on node %s yet", nodeName)
+}
+
+// streamTarToStorage execs `tar -C path -czf - .` in agentPod and streams its stdout
+// straight to bucket/objectName, through an io.Pipe so the archive is never fully
+// materialized in memory, mirroring how pkg/storage.copyOne streams object copies.
+func (r *BackupReconciler) streamTarToStorage(ctx context.Context, provider storage.Provider, bucket, objectName string, agentPod *corev1.Pod, path string) error {
+	stream, streamErrCh, err := r.execTarStream(ctx, agentPod, path)
+	if err != nil {
+		return err
+	}
+
+	if err := provider.PutObject(ctx, bucket, objectName, stream, -1); err != nil {
+		return fmt.Errorf("failed to upload fs-backup archive %s: %w", objectName, err)
+	}
+
+	if streamErr := <-streamErrCh; streamErr != nil {
+		return fmt.Errorf("fs-backup tar exec in pod %s/%s failed: %w", agentPod.Namespace, agentPod.Name, streamErr)
+	}
+
+	return nil
+}
+
+// execTarStream execs `tar -C path -czf - .` in agentPod and returns a reader for its
+// stdout, through an io.Pipe so the archive is never fully materialized in memory, plus
+// a channel that receives the exec's result once the stream closes. streamTarToStorage
+// and dataMoverPersistentVolumeClaim both build on this to turn a PVC's on-disk
+// contents into a byte stream without needing a local tar binary.
+func (r *BackupReconciler) execTarStream(ctx context.Context, agentPod *corev1.Pod, path string) (io.Reader, <-chan error, error) {
+	if r.RestConfig == nil {
+		return nil, nil, fmt.Errorf("no RestConfig configured for fs-backup exec")
+	}
+
+	clientset, err := kubernetes.NewForConfig(r.RestConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build clientset for fs-backup exec: %w", err)
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(agentPod.Name).
+		Namespace(agentPod.Namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: fsBackupContainerName,
+			Command:   []string{"tar", "-C", path, "-czf", "-", "."},
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(r.RestConfig, "POST", req.URL())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create exec executor for fs-backup: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+	streamErrCh := make(chan error, 1)
+	go func() {
+		streamErr := executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: pw})
+		pw.CloseWithError(streamErr)
+		streamErrCh <- streamErr
+	}()
+
+	return pr, streamErrCh, nil
+}
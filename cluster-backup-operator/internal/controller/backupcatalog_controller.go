@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupCatalogReconciler reconciles a BackupCatalog object
+type BackupCatalogReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// AllowedCredentialsNamespaces restricts which namespaces a StorageLocation's
+	// CredentialsRef may point Secrets into. Empty means no restriction.
+	AllowedCredentialsNamespaces []string
+}
+
+// +kubebuilder:rbac:groups=backup.cnd.dk,resources=backupcatalogs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=backup.cnd.dk,resources=backupcatalogs/status,verbs=get;update;patch
+
+// Reconcile refreshes Status.Entries with one page of backups listed from the
+// BackupCatalog's StorageLocation. Unlike Backup/Restore, a BackupCatalog has no
+// multi-phase lifecycle: every reconcile simply re-lists the page described by the
+// current spec.
+func (r *BackupCatalogReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	catalog := &backupv1.BackupCatalog{}
+	if err := r.Get(ctx, req.NamespacedName, catalog); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			log.Info("BackupCatalog resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get BackupCatalog")
+		return ctrl.Result{}, err
+	}
+
+	provider, err := storage.NewProvider(ctx, r.Client, r.AllowedCredentialsNamespaces, catalog.Namespace, catalog.Spec.StorageLocation)
+	if err != nil {
+		return r.failCatalog(ctx, catalog, fmt.Errorf("failed to initialize storage provider: %w", err))
+	}
+
+	entries, nextToken, err := catalogBackups(ctx, provider, catalog.Spec.StorageLocation.Bucket, catalog.Spec.PageToken, int(catalog.Spec.PageSize))
+	if err != nil {
+		return r.failCatalog(ctx, catalog, fmt.Errorf("failed to list backups: %w", err))
+	}
+
+	catalog.Status.Entries = entries
+	catalog.Status.NextPageToken = nextToken
+	catalog.Status.RefreshTime = &metav1.Time{Time: time.Now()}
+	catalog.Status.Message = fmt.Sprintf("listed %d backups", len(entries))
+
+	if err := r.Status().Update(ctx, catalog); err != nil {
+		log.Error(err, "Failed to update BackupCatalog status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// failCatalog records err on catalog's status and returns it so Reconcile retries
+// with backoff.
+func (r *BackupCatalogReconciler) failCatalog(ctx context.Context, catalog *backupv1.BackupCatalog, err error) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	catalog.Status.Message = err.Error()
+	if updateErr := r.Status().Update(ctx, catalog); updateErr != nil {
+		log.Error(updateErr, "Failed to update BackupCatalog status")
+	}
+	return ctrl.Result{}, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BackupCatalogReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&backupv1.BackupCatalog{}).
+		Named("backupcatalog").
+		Complete(r)
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Steps a BackupDeletionRequest tracks in Status.CompletedSteps, so a reconcile
+// retried after a partial failure skips work that already succeeded.
+const (
+	stepDeleteStorageObjects  = "deleteStorageObjects"
+	stepDeleteVolumeSnapshots = "deleteVolumeSnapshots"
+	stepDeleteBackup          = "deleteBackup"
+)
+
+// backupDeletionRetryInterval bounds how long a failed step waits before the next
+// reconcile retries it.
+const backupDeletionRetryInterval = 30 * time.Second
+
+// BackupDeletionRequestReconciler reconciles a BackupDeletionRequest object
+type BackupDeletionRequestReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// AllowedCredentialsNamespaces restricts which namespaces a StorageLocation's
+	// CredentialsRef may point Secrets into. Empty means no restriction.
+	AllowedCredentialsNamespaces []string
+}
+
+// +kubebuilder:rbac:groups=backup.cnd.dk,resources=backupdeletionrequests,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=backup.cnd.dk,resources=backupdeletionrequests/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=backup.cnd.dk,resources=backups,verbs=get;list;watch;update;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshotcontents,verbs=get;list;watch;delete
+
+// Reconcile drives a BackupDeletionRequest's steps to completion, retrying only the
+// ones that failed on a previous attempt.
+func (r *BackupDeletionRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	deletion := &backupv1.BackupDeletionRequest{}
+	if err := r.Get(ctx, req.NamespacedName, deletion); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if deletion.Status.Phase == backupv1.BackupDeletionRequestPhaseCompleted {
+		return ctrl.Result{}, nil
+	}
+
+	backupNamespace := deletion.Spec.BackupNamespace
+	if backupNamespace == "" {
+		backupNamespace = deletion.Namespace
+	}
+
+	backup := &backupv1.Backup{}
+	getErr := r.Get(ctx, client.ObjectKey{Name: deletion.Spec.BackupName, Namespace: backupNamespace}, backup)
+	if getErr != nil && !errors.IsNotFound(getErr) {
+		return ctrl.Result{}, fmt.Errorf("failed to get backup %s/%s: %w", backupNamespace, deletion.Spec.BackupName, getErr)
+	}
+	backupFound := getErr == nil
+
+	if deletion.Status.StepErrors == nil {
+		deletion.Status.StepErrors = make(map[string]string)
+	}
+	deletion.Status.Phase = backupv1.BackupDeletionRequestPhaseInProgress
+
+	allOK := true
+	runStep := func(step string, fn func() error) {
+		if stepDone(deletion, step) {
+			return
+		}
+		if err := fn(); err != nil {
+			log.Error(err, "BackupDeletionRequest step failed", "request", deletion.Name, "step", step)
+			deletion.Status.StepErrors[step] = err.Error()
+			allOK = false
+			return
+		}
+		markStepDone(deletion, step)
+		delete(deletion.Status.StepErrors, step)
+	}
+
+	runStep(stepDeleteStorageObjects, func() error {
+		return r.deleteStorageObjects(ctx, deletion, backup, backupFound)
+	})
+
+	// VolumeSnapshot/VolumeSnapshotContent cleanup and the final Backup CR delete only
+	// apply when tearing down the whole Backup (BackupPath unset); a retention prune of
+	// a single historical path leaves the live Backup and its current snapshots alone.
+	if deletion.Spec.BackupPath == "" {
+		runStep(stepDeleteVolumeSnapshots, func() error {
+			return r.deleteVolumeSnapshots(ctx, backup, backupFound)
+		})
+		runStep(stepDeleteBackup, func() error {
+			return r.deleteBackup(ctx, backup, backupFound)
+		})
+	}
+
+	if allOK {
+		deletion.Status.Phase = backupv1.BackupDeletionRequestPhaseCompleted
+		now := metav1.Now()
+		deletion.Status.CompletionTime = &now
+	} else {
+		deletion.Status.Phase = backupv1.BackupDeletionRequestPhaseFailed
+	}
+
+	if err := r.Status().Update(ctx, deletion); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update BackupDeletionRequest status: %w", err)
+	}
+
+	if !allOK {
+		return ctrl.Result{RequeueAfter: backupDeletionRetryInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// stepDone reports whether step is already recorded in deletion.Status.CompletedSteps.
+func stepDone(deletion *backupv1.BackupDeletionRequest, step string) bool {
+	for _, s := range deletion.Status.CompletedSteps {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// markStepDone appends step to deletion.Status.CompletedSteps if not already present.
+func markStepDone(deletion *backupv1.BackupDeletionRequest, step string) {
+	if stepDone(deletion, step) {
+		return
+	}
+	deletion.Status.CompletedSteps = append(deletion.Status.CompletedSteps, step)
+}
+
+// deleteStorageObjects removes every remote object under the backup path being torn
+// down - deletion.Spec.BackupPath if set (a single historical run pruned by
+// retention), or backup.Status.BackupPath otherwise (the whole Backup).
+func (r *BackupDeletionRequestReconciler) deleteStorageObjects(ctx context.Context, deletion *backupv1.BackupDeletionRequest, backup *backupv1.Backup, backupFound bool) error {
+	if !backupFound {
+		return fmt.Errorf("backup %s/%s not found, cannot resolve its storage location", deletion.Spec.BackupNamespace, deletion.Spec.BackupName)
+	}
+
+	path := deletion.Spec.BackupPath
+	if path == "" {
+		path = backup.Status.BackupPath
+	}
+	if path == "" {
+		// The Backup never completed a run, so nothing was ever uploaded.
+		return nil
+	}
+
+	provider, err := storage.NewProvider(ctx, r.Client, r.AllowedCredentialsNamespaces, backup.Namespace, backup.Spec.StorageLocation)
+	if err != nil {
+		return fmt.Errorf("failed to initialize storage provider: %w", err)
+	}
+
+	bucket := backup.Spec.StorageLocation.Bucket
+	keys, err := provider.ListObjects(ctx, bucket, path)
+	if err != nil {
+		return fmt.Errorf("failed to list objects under %s: %w", path, err)
+	}
+	for _, key := range keys {
+		if err := provider.DeleteObject(ctx, bucket, key); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// deleteVolumeSnapshots removes every VolumeSnapshot and VolumeSnapshotContent
+// recorded in backup.Status.VolumeSnapshots.
+func (r *BackupDeletionRequestReconciler) deleteVolumeSnapshots(ctx context.Context, backup *backupv1.Backup, backupFound bool) error {
+	if !backupFound {
+		return nil
+	}
+
+	for _, record := range backup.Status.VolumeSnapshots {
+		vs := &snapshotv1.VolumeSnapshot{}
+		err := r.Get(ctx, client.ObjectKey{Name: record.VolumeSnapshotName, Namespace: record.Namespace}, vs)
+		if err == nil {
+			if err := r.Delete(ctx, vs); err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete volumesnapshot %s/%s: %w", record.Namespace, record.VolumeSnapshotName, err)
+			}
+		} else if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get volumesnapshot %s/%s: %w", record.Namespace, record.VolumeSnapshotName, err)
+		}
+
+		if record.VolumeSnapshotContentName == "" {
+			continue
+		}
+		vsc := &snapshotv1.VolumeSnapshotContent{}
+		err = r.Get(ctx, client.ObjectKey{Name: record.VolumeSnapshotContentName}, vsc)
+		if err == nil {
+			if err := r.Delete(ctx, vsc); err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete volumesnapshotcontent %s: %w", record.VolumeSnapshotContentName, err)
+			}
+		} else if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get volumesnapshotcontent %s: %w", record.VolumeSnapshotContentName, err)
+		}
+	}
+	return nil
+}
+
+// deleteBackup deletes the Backup CR itself. It is a no-op if the Backup is already
+// gone, and idempotent if the Backup still carries BackupFinalizer: this just (re)sets
+// its DeletionTimestamp, letting BackupReconciler's reconcileDeletion remove the
+// finalizer once it observes this same BackupDeletionRequest Completed.
+func (r *BackupDeletionRequestReconciler) deleteBackup(ctx context.Context, backup *backupv1.Backup, backupFound bool) error {
+	if !backupFound {
+		return nil
+	}
+	if err := r.Delete(ctx, backup); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete backup %s/%s: %w", backup.Namespace, backup.Name, err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BackupDeletionRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&backupv1.BackupDeletionRequest{}).
+		Complete(r)
+}
+
+// reconcileDeletion is called by BackupReconciler.Reconcile once backup.DeletionTimestamp
+// is set. It ensures exactly one BackupDeletionRequest tears backup's remote storage
+// and VolumeSnapshots down, then removes BackupFinalizer once that request reports
+// Completed, letting the API server finish deleting backup.
+func (r *BackupReconciler) reconcileDeletion(ctx context.Context, backup *backupv1.Backup) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(backup, backupv1.BackupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if backup.Status.DeletionRequestName == "" {
+		name := backupDeletionRequestName(backup.Name)
+		deletion := &backupv1.BackupDeletionRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: backup.Namespace},
+			Spec: backupv1.BackupDeletionRequestSpec{
+				BackupName:      backup.Name,
+				BackupNamespace: backup.Namespace,
+			},
+		}
+		if err := r.Create(ctx, deletion); err != nil && !errors.IsAlreadyExists(err) {
+			log.Error(err, "Failed to create BackupDeletionRequest")
+			return ctrl.Result{}, err
+		}
+		backup.Status.DeletionRequestName = name
+		if err := r.Status().Update(ctx, backup); err != nil {
+			log.Error(err, "Failed to record BackupDeletionRequest name on Backup status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+
+	deletion := &backupv1.BackupDeletionRequest{}
+	if err := r.Get(ctx, client.ObjectKey{Name: backup.Status.DeletionRequestName, Namespace: backup.Namespace}, deletion); err != nil {
+		if errors.IsNotFound(err) {
+			// Lost track of it somehow (e.g. manually deleted); the next reconcile
+			// will create a fresh one.
+			backup.Status.DeletionRequestName = ""
+			if uerr := r.Status().Update(ctx, backup); uerr != nil {
+				return ctrl.Result{}, uerr
+			}
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get BackupDeletionRequest %s: %w", backup.Status.DeletionRequestName, err)
+	}
+
+	if deletion.Status.Phase != backupv1.BackupDeletionRequestPhaseCompleted {
+		log.Info("Waiting for BackupDeletionRequest to finish tearing down remote objects",
+			"request", deletion.Name, "phase", deletion.Status.Phase)
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+
+	controllerutil.RemoveFinalizer(backup, backupv1.BackupFinalizer)
+	if err := r.Update(ctx, backup); err != nil {
+		log.Error(err, "Failed to remove Backup finalizer")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// backupDeletionRequestName derives a stable, idempotent BackupDeletionRequest name
+// for tearing down the whole Backup named backupName.
+func backupDeletionRequestName(backupName string) string {
+	return backupName + "-deletion"
+}
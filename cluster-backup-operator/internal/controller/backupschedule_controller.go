@@ -0,0 +1,270 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/robfig/cron/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// scheduleLabel marks the Backup objects created by a BackupSchedule so the
+// schedule controller can list and garbage-collect its children.
+const scheduleLabel = "backup.cnd.dk/schedule"
+
+// BackupScheduleReconciler reconciles a BackupSchedule object
+type BackupScheduleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=backup.cnd.dk,resources=backupschedules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=backup.cnd.dk,resources=backupschedules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=backup.cnd.dk,resources=backups,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile creates a new per-run Backup whenever the cron schedule fires and
+// garbage-collects old child Backups beyond the configured retention limits.
+func (r *BackupScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	schedule := &backupv1.BackupSchedule{}
+	if err := r.Get(ctx, req.NamespacedName, schedule); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			log.Info("BackupSchedule resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get BackupSchedule")
+		return ctrl.Result{}, err
+	}
+
+	if schedule.Spec.Pause {
+		schedule.Status.Message = "Schedule paused, no new backups will be created"
+		if err := r.Status().Update(ctx, schedule); err != nil {
+			log.Error(err, "Failed to update paused status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+
+	// due is true the very first reconcile (no prior firing), or once the
+	// schedule's next run after the last firing has passed.
+	due := schedule.Status.LastScheduledBackupTime == nil
+	if !due {
+		nextFromLast, err := nextBackupTimeAfter(schedule.Spec.Schedule, schedule.Status.LastScheduledBackupTime.Time)
+		if err != nil {
+			schedule.Status.Message = fmt.Sprintf("Invalid cron schedule '%s': %v", schedule.Spec.Schedule, err)
+			if updateErr := r.Status().Update(ctx, schedule); updateErr != nil {
+				log.Error(updateErr, "Failed to update invalid-schedule status")
+			}
+			return ctrl.Result{}, err
+		}
+		due = now.After(nextFromLast)
+	}
+
+	nextTime, err := calculateNextBackupTime(schedule.Spec.Schedule)
+	if err != nil {
+		schedule.Status.Message = fmt.Sprintf("Invalid cron schedule '%s': %v", schedule.Spec.Schedule, err)
+		if updateErr := r.Status().Update(ctx, schedule); updateErr != nil {
+			log.Error(updateErr, "Failed to update invalid-schedule status")
+		}
+		return ctrl.Result{}, err
+	}
+
+	if due {
+		running, err := r.runningChildBackups(ctx, schedule)
+		if err != nil {
+			log.Error(err, "Failed to list running child Backups")
+			return ctrl.Result{}, err
+		}
+
+		switch schedule.Spec.ConcurrencyPolicy {
+		case backupv1.ConcurrencyPolicyForbid:
+			if len(running) > 0 {
+				schedule.Status.Message = fmt.Sprintf("Skipped firing: %d Backup(s) from this schedule still running and ConcurrencyPolicy is Forbid", len(running))
+				if err := r.Status().Update(ctx, schedule); err != nil {
+					log.Error(err, "Failed to update status for a skipped firing")
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{RequeueAfter: time.Until(nextTime)}, nil
+			}
+		case backupv1.ConcurrencyPolicyReplace:
+			for i := range running {
+				if err := r.Delete(ctx, &running[i]); err != nil && client.IgnoreNotFound(err) != nil {
+					log.Error(err, "Failed to delete running Backup for replacement", "backup", running[i].Name)
+					return ctrl.Result{}, err
+				}
+			}
+		}
+
+		if err := r.createBackupRun(ctx, schedule, now); err != nil {
+			log.Error(err, "Failed to create scheduled Backup")
+			return ctrl.Result{}, err
+		}
+		schedule.Status.LastScheduledBackupTime = &metav1.Time{Time: now}
+		schedule.Status.Message = "Created a new Backup for this schedule firing"
+	}
+
+	if err := r.garbageCollectBackups(ctx, schedule); err != nil {
+		log.Error(err, "Failed to garbage-collect child Backups")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.Status().Update(ctx, schedule); err != nil {
+		log.Error(err, "Failed to update BackupSchedule status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: time.Until(nextTime)}, nil
+}
+
+// createBackupRun creates an immutable per-run Backup from the schedule's template.
+func (r *BackupScheduleReconciler) createBackupRun(ctx context.Context, schedule *backupv1.BackupSchedule, at time.Time) error {
+	spec := schedule.Spec.Template
+	spec.Schedule = "" // scheduling belongs to BackupSchedule, not the per-run Backup
+
+	run := &backupv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", schedule.Name, at.Format("20060102150405")),
+			Namespace: schedule.Namespace,
+			Labels:    map[string]string{scheduleLabel: schedule.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(schedule, backupv1.GroupVersion.WithKind("BackupSchedule")),
+			},
+		},
+		Spec: spec,
+	}
+
+	return r.Create(ctx, run)
+}
+
+// runningChildBackups returns this schedule's child Backups that have not yet
+// reached a terminal phase (Completed or Failed), for ConcurrencyPolicy to act on.
+func (r *BackupScheduleReconciler) runningChildBackups(ctx context.Context, schedule *backupv1.BackupSchedule) ([]backupv1.Backup, error) {
+	children := &backupv1.BackupList{}
+	if err := r.List(ctx, children, client.InNamespace(schedule.Namespace), client.MatchingLabels{scheduleLabel: schedule.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list child backups: %w", err)
+	}
+
+	var running []backupv1.Backup
+	for _, b := range children.Items {
+		if b.Status.Phase != backupv1.BackupPhaseCompleted && b.Status.Phase != backupv1.BackupPhaseFailed {
+			running = append(running, b)
+		}
+	}
+	return running, nil
+}
+
+// garbageCollectBackups deletes completed/failed child Backups beyond the
+// configured retention limits, oldest first by status.completionTime.
+func (r *BackupScheduleReconciler) garbageCollectBackups(ctx context.Context, schedule *backupv1.BackupSchedule) error {
+	children := &backupv1.BackupList{}
+	if err := r.List(ctx, children, client.InNamespace(schedule.Namespace), client.MatchingLabels{scheduleLabel: schedule.Name}); err != nil {
+		return fmt.Errorf("failed to list child backups: %w", err)
+	}
+
+	var successful, failed []backupv1.Backup
+	for _, b := range children.Items {
+		switch b.Status.Phase {
+		case backupv1.BackupPhaseCompleted:
+			successful = append(successful, b)
+			if schedule.Status.LastSuccessfulBackupTime == nil || (b.Status.CompletionTime != nil && b.Status.CompletionTime.After(schedule.Status.LastSuccessfulBackupTime.Time)) {
+				schedule.Status.LastSuccessfulBackupTime = b.Status.CompletionTime
+			}
+		case backupv1.BackupPhaseFailed:
+			failed = append(failed, b)
+		}
+	}
+
+	if err := r.pruneOldest(ctx, successful, schedule.Spec.MaxSuccessfulBackups); err != nil {
+		return err
+	}
+	return r.pruneOldest(ctx, failed, schedule.Spec.MaxFailedBackups)
+}
+
+// pruneOldest deletes the oldest backups in the slice until at most limit remain.
+// limit == 0 means unlimited (nothing is pruned).
+func (r *BackupScheduleReconciler) pruneOldest(ctx context.Context, backups []backupv1.Backup, limit int32) error {
+	if limit <= 0 || int32(len(backups)) <= limit {
+		return nil
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return completionTime(&backups[i]).Before(completionTime(&backups[j]))
+	})
+
+	toDelete := backups[:int32(len(backups))-limit]
+	for i := range toDelete {
+		if err := r.Delete(ctx, &toDelete[i]); err != nil && client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete backup %s: %w", toDelete[i].Name, err)
+		}
+	}
+	return nil
+}
+
+func completionTime(b *backupv1.Backup) time.Time {
+	if b.Status.CompletionTime != nil {
+		return b.Status.CompletionTime.Time
+	}
+	return b.CreationTimestamp.Time
+}
+
+// calculateNextBackupTime parses a cron schedule and returns its next run
+// time from now. Shared by BackupReconciler and BackupScheduleReconciler so
+// both compute firing times identically.
+func calculateNextBackupTime(schedule string) (time.Time, error) {
+	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+	cronSchedule, err := parser.Parse(schedule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse cron schedule '%s': %w", schedule, err)
+	}
+
+	return cronSchedule.Next(time.Now()), nil
+}
+
+// nextBackupTimeAfter parses a cron schedule and returns its next run time after `after`.
+func nextBackupTimeAfter(schedule string, after time.Time) (time.Time, error) {
+	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+	cronSchedule, err := parser.Parse(schedule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse cron schedule '%s': %w", schedule, err)
+	}
+
+	return cronSchedule.Next(after), nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BackupScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&backupv1.BackupSchedule{}).
+		Named("backupschedule").
+		Complete(r)
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestBackupSchedule(namespace, name string, policy backupv1.ConcurrencyPolicyMode) *backupv1.BackupSchedule {
+	return &backupv1.BackupSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: backupv1.BackupScheduleSpec{
+			Schedule:          "* * * * *",
+			ConcurrencyPolicy: policy,
+			Template: backupv1.BackupSpec{
+				Source: backupv1.BackupSource{Namespace: namespace},
+				StorageLocation: backupv1.StorageLocation{
+					Provider: "minio",
+					Bucket:   "schedule-test-bucket",
+				},
+			},
+		},
+	}
+}
+
+func newTestChildBackup(namespace, name, scheduleName string, phase backupv1.BackupPhase) *backupv1.Backup {
+	return &backupv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{scheduleLabel: scheduleName},
+		},
+		Status: backupv1.BackupStatus{Phase: phase},
+	}
+}
+
+// TestRunningChildBackupsFiltersTerminalPhases verifies only Backups in a
+// non-terminal phase (not Completed or Failed) are reported as running.
+func TestRunningChildBackupsFiltersTerminalPhases(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	schedule := newTestBackupSchedule("default", "nightly", "")
+	running := newTestChildBackup("default", "nightly-1", "nightly", backupv1.BackupPhaseRunning)
+	completed := newTestChildBackup("default", "nightly-2", "nightly", backupv1.BackupPhaseCompleted)
+	failed := newTestChildBackup("default", "nightly-3", "nightly", backupv1.BackupPhaseFailed)
+
+	r := &BackupScheduleReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(schedule, running, completed, failed).Build()}
+
+	got, err := r.runningChildBackups(context.Background(), schedule)
+	if err != nil {
+		t.Fatalf("runningChildBackups() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "nightly-1" {
+		t.Errorf("runningChildBackups() = %v, want only nightly-1", got)
+	}
+}
+
+// TestConcurrencyPolicyForbidSkipsFiringWhileRunning verifies a due firing under
+// ConcurrencyPolicy Forbid creates no new Backup while a prior one is still running.
+func TestConcurrencyPolicyForbidSkipsFiringWhileRunning(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	schedule := newTestBackupSchedule("default", "nightly", backupv1.ConcurrencyPolicyForbid)
+	running := newTestChildBackup("default", "nightly-1", "nightly", backupv1.BackupPhaseRunning)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(schedule, running).WithStatusSubresource(schedule).Build()
+	r := &BackupScheduleReconciler{Client: fakeClient}
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(schedule)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	children := &backupv1.BackupList{}
+	if err := fakeClient.List(context.Background(), children, client.InNamespace("default")); err != nil {
+		t.Fatalf("failed to list backups: %v", err)
+	}
+	if len(children.Items) != 1 {
+		t.Errorf("expected no new Backup to be created, got %d child Backups: %v", len(children.Items), children.Items)
+	}
+}
+
+// TestConcurrencyPolicyReplaceDeletesRunningBackup verifies a due firing under
+// ConcurrencyPolicy Replace deletes the still-running Backup and creates a new one.
+func TestConcurrencyPolicyReplaceDeletesRunningBackup(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	schedule := newTestBackupSchedule("default", "nightly", backupv1.ConcurrencyPolicyReplace)
+	running := newTestChildBackup("default", "nightly-1", "nightly", backupv1.BackupPhaseRunning)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(schedule, running).WithStatusSubresource(schedule).Build()
+	r := &BackupScheduleReconciler{Client: fakeClient}
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(schedule)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	children := &backupv1.BackupList{}
+	if err := fakeClient.List(context.Background(), children, client.InNamespace("default")); err != nil {
+		t.Fatalf("failed to list backups: %v", err)
+	}
+	if len(children.Items) != 1 {
+		t.Fatalf("expected exactly 1 Backup after replacement, got %d: %v", len(children.Items), children.Items)
+	}
+	if children.Items[0].Name == "nightly-1" {
+		t.Errorf("expected the running Backup to be replaced by a new one, but nightly-1 still exists")
+	}
+}
+
+// TestConcurrencyPolicyAllowCreatesAlongsideRunning verifies the default (empty,
+// i.e. Allow) policy creates a new Backup without touching the still-running one.
+func TestConcurrencyPolicyAllowCreatesAlongsideRunning(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	schedule := newTestBackupSchedule("default", "nightly", "")
+	running := newTestChildBackup("default", "nightly-1", "nightly", backupv1.BackupPhaseRunning)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(schedule, running).WithStatusSubresource(schedule).Build()
+	r := &BackupScheduleReconciler{Client: fakeClient}
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(schedule)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	children := &backupv1.BackupList{}
+	if err := fakeClient.List(context.Background(), children, client.InNamespace("default")); err != nil {
+		t.Fatalf("failed to list backups: %v", err)
+	}
+	if len(children.Items) != 2 {
+		t.Errorf("expected the running Backup plus a new one (2 total), got %d: %v", len(children.Items), children.Items)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import "fmt"
+
+// OperatorRunMode restricts which of the Backup/Restore reconcilers are allowed to do
+// their normal work, mirroring Velero's --restore-only server flag: a DR cluster's
+// operator should only ever restore into it and never take a new backup of it. Set
+// BackupReconciler.RunMode/RestoreReconciler.RunMode from a manager flag (this tree
+// has no cmd/main.go to wire the flag.StringVar itself, the way kubebuilder scaffolds
+// one) rather than a dedicated OperatorConfig CRD, consistent with how
+// AllowedCredentialsNamespaces/PluginDir are already threaded onto both reconcilers.
+type OperatorRunMode string
+
+const (
+	// OperatorRunModeFull is the default: both Backup and Restore reconcile normally.
+	OperatorRunModeFull OperatorRunMode = ""
+	// OperatorRunModeBackupOnly refuses to reconcile Restore objects other than their
+	// deletion/teardown.
+	OperatorRunModeBackupOnly OperatorRunMode = "backup-only"
+	// OperatorRunModeRestoreOnly refuses to reconcile Backup objects other than their
+	// deletion/teardown.
+	OperatorRunModeRestoreOnly OperatorRunMode = "restore-only"
+)
+
+// runModeRefusalMessage explains, for status.message, why RunMode blocked this
+// reconcile.
+func runModeRefusalMessage(kind string, mode OperatorRunMode) string {
+	return fmt.Sprintf("operator is running in %s mode; %s objects are not reconciled", mode, kind)
+}
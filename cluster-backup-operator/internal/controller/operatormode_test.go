@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestBackupReconcileRefusesNewBackupInRestoreOnlyMode verifies a restore-only
+// operator marks a Backup Failed with a clear message instead of running it.
+func TestBackupReconcileRefusesNewBackupInRestoreOnlyMode(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	backup := &backupv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default", Finalizers: []string{backupv1.BackupFinalizer}},
+		Spec: backupv1.BackupSpec{
+			Source:          backupv1.BackupSource{Namespace: "default"},
+			StorageLocation: backupv1.StorageLocation{Provider: "minio", Bucket: "backups"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(backup).WithStatusSubresource(backup).Build()
+	r := &BackupReconciler{Client: c, RunMode: OperatorRunModeRestoreOnly}
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(backup)})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	got := &backupv1.Backup{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(backup), got); err != nil {
+		t.Fatalf("failed to get Backup: %v", err)
+	}
+	if got.Status.Phase != backupv1.BackupPhaseFailed {
+		t.Errorf("Phase = %q, want Failed", got.Status.Phase)
+	}
+	if got.Status.Message != runModeRefusalMessage("Backup", OperatorRunModeRestoreOnly) {
+		t.Errorf("Message = %q, want the run-mode refusal message", got.Status.Message)
+	}
+}
+
+// TestRestoreReconcileRefusesRestoreInBackupOnlyMode verifies a backup-only operator
+// marks a Restore Failed with a clear message instead of running it.
+func TestRestoreReconcileRefusesRestoreInBackupOnlyMode(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	restore := &backupv1.Restore{
+		ObjectMeta: metav1.ObjectMeta{Name: "restore-1", Namespace: "default"},
+		Spec: backupv1.RestoreSpec{
+			Source: backupv1.RestoreSource{
+				StorageLocation: backupv1.StorageLocation{Provider: "minio", Bucket: "backups"},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(restore).WithStatusSubresource(restore).Build()
+	r := &RestoreReconciler{Client: c, RunMode: OperatorRunModeBackupOnly}
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(restore)})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	got := &backupv1.Restore{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(restore), got); err != nil {
+		t.Fatalf("failed to get Restore: %v", err)
+	}
+	if got.Status.Phase != backupv1.RestorePhaseFailed {
+		t.Errorf("Phase = %q, want Failed", got.Status.Phase)
+	}
+	if got.Status.Message != runModeRefusalMessage("Restore", OperatorRunModeBackupOnly) {
+		t.Errorf("Message = %q, want the run-mode refusal message", got.Status.Message)
+	}
+}
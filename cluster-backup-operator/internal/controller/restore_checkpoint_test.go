@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// TestCheckpointIntervalForDefaultsWhenUnset verifies a non-positive
+// CheckpointInterval falls back to defaultCheckpointInterval.
+func TestCheckpointIntervalForDefaultsWhenUnset(t *testing.T) {
+	if got := checkpointIntervalFor(0); got != defaultCheckpointInterval {
+		t.Errorf("checkpointIntervalFor(0) = %d, want %d", got, defaultCheckpointInterval)
+	}
+	if got := checkpointIntervalFor(-5); got != defaultCheckpointInterval {
+		t.Errorf("checkpointIntervalFor(-5) = %d, want %d", got, defaultCheckpointInterval)
+	}
+	if got := checkpointIntervalFor(10); got != 10 {
+		t.Errorf("checkpointIntervalFor(10) = %d, want 10", got)
+	}
+}
+
+// TestResumeOffsetContinuesPastLastProcessed verifies resuming picks up one past the
+// last resource the checkpoint recorded as processed, not at it again.
+func TestResumeOffsetContinuesPastLastProcessed(t *testing.T) {
+	checkpoint := &backupv1.RestoreCheckpoint{ResourceKey: "backups/cluster-backup/ts/namespaces/default/configmaps/a.json", Offset: 4}
+	if got := resumeOffset(checkpoint); got != 5 {
+		t.Errorf("resumeOffset(offset=4) = %d, want 5", got)
+	}
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestIsClusterResourcePath distinguishes a cluster-scoped backup object key
+// (".../cluster/<resourceType>/<name>.json") from a namespaced one
+// (".../namespaces/<namespace>/<resourceType>/<name>.json").
+func TestIsClusterResourcePath(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"backups/cluster-backup/ts/cluster/clusterroles/admin.json", true},
+		{"backups/cluster-backup/ts/namespaces/default/configmaps/app.json", false},
+		{"short/path.json", false},
+	}
+
+	for _, tt := range tests {
+		got := isClusterResourcePath(strings.Split(tt.key, "/"))
+		if got != tt.want {
+			t.Errorf("isClusterResourcePath(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+// TestRestoreResourceCreatesClusterScopedResourceWithEmptyNamespace verifies that
+// restoreResource, called with targetNamespace="" as performRestore's cluster-scoped
+// branch does, creates a cluster-scoped resource (no namespace) rather than treating
+// the empty namespace as "leave it where the manifest says".
+func TestRestoreResourceCreatesClusterScopedResourceWithEmptyNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r := &RestoreReconciler{Client: fakeClient}
+
+	ctx := context.Background()
+	provider := newLocalProviderForTest(t)
+	bucket := "test-bucket"
+	if err := provider.EnsureBucket(ctx, bucket); err != nil {
+		t.Fatalf("failed to ensure bucket: %v", err)
+	}
+
+	clusterRole := &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: "admin"},
+	}
+	data, err := json.Marshal(clusterRole)
+	if err != nil {
+		t.Fatalf("failed to marshal clusterrole: %v", err)
+	}
+	objectKey := "backups/cluster-backup/ts/cluster/clusterroles/admin.json"
+	if err := provider.PutObject(ctx, bucket, objectKey, bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatalf("failed to put object: %v", err)
+	}
+
+	filter, err := compileRestoreFilter(backupv1.RestoreTarget{})
+	if err != nil {
+		t.Fatalf("compileRestoreFilter() error = %v", err)
+	}
+
+	restore := &backupv1.Restore{}
+	result, err := r.restoreResource(ctx, provider, bucket, objectKey, "", "clusterroles", "", restore, "backups/cluster-backup/ts", "", nil, nil, filter)
+	if err != nil {
+		t.Fatalf("restoreResource() error = %v", err)
+	}
+	if result.Action != "created" {
+		t.Errorf("restoreResource().Action = %q, want created", result.Action)
+	}
+
+	got := &rbacv1.ClusterRole{}
+	if err := fakeClient.Get(ctx, client.ObjectKey{Name: "admin"}, got); err != nil {
+		t.Fatalf("expected ClusterRole to be created, Get failed: %v", err)
+	}
+}
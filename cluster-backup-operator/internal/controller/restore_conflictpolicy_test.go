@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// TestResolveConflictPolicy covers PerResource overrides, Default, and the legacy
+// conflictResolution fallback, in that precedence order.
+func TestResolveConflictPolicy(t *testing.T) {
+	cases := []struct {
+		name     string
+		policy   *backupv1.ConflictPolicy
+		legacy   string
+		resource string
+		want     backupv1.ConflictPolicyMode
+	}{
+		{"no policy, legacy overwrite", nil, "overwrite", "configmaps", backupv1.ConflictPolicyOverwrite},
+		{"no policy, legacy fail", nil, "fail", "configmaps", backupv1.ConflictPolicyFail},
+		{"no policy, legacy empty", nil, "", "configmaps", backupv1.ConflictPolicySkip},
+		{
+			"policy default only",
+			&backupv1.ConflictPolicy{Default: backupv1.ConflictPolicyMerge},
+			"overwrite", "configmaps",
+			backupv1.ConflictPolicyMerge,
+		},
+		{
+			"per-resource override beats default",
+			&backupv1.ConflictPolicy{
+				Default:     backupv1.ConflictPolicyOverwrite,
+				PerResource: map[string]backupv1.ConflictPolicyMode{"secrets": backupv1.ConflictPolicyFail},
+			},
+			"overwrite", "secrets",
+			backupv1.ConflictPolicyFail,
+		},
+		{
+			"per-resource override falls through to default for other types",
+			&backupv1.ConflictPolicy{
+				Default:     backupv1.ConflictPolicyOverwrite,
+				PerResource: map[string]backupv1.ConflictPolicyMode{"secrets": backupv1.ConflictPolicyFail},
+			},
+			"overwrite", "configmaps",
+			backupv1.ConflictPolicyOverwrite,
+		},
+	}
+
+	for _, c := range cases {
+		if got := resolveConflictPolicy(c.policy, c.legacy, c.resource); got != c.want {
+			t.Errorf("%s: resolveConflictPolicy() = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestValidateConflictPolicy(t *testing.T) {
+	if err := validateConflictPolicy(nil); err != nil {
+		t.Errorf("nil policy: unexpected error: %v", err)
+	}
+
+	valid := &backupv1.ConflictPolicy{
+		Default:     backupv1.ConflictPolicyOverwrite,
+		PerResource: map[string]backupv1.ConflictPolicyMode{"secrets": backupv1.ConflictPolicyMerge},
+	}
+	if err := validateConflictPolicy(valid); err != nil {
+		t.Errorf("valid policy: unexpected error: %v", err)
+	}
+
+	if err := validateConflictPolicy(&backupv1.ConflictPolicy{Default: "Replace"}); err == nil {
+		t.Error("expected an error for an invalid default mode, got nil")
+	}
+	if err := validateConflictPolicy(&backupv1.ConflictPolicy{
+		PerResource: map[string]backupv1.ConflictPolicyMode{"secrets": "Replace"},
+	}); err == nil {
+		t.Error("expected an error for an invalid perResource mode, got nil")
+	}
+}
+
+// TestMergeExistingResourceMergesDisjointFields verifies the no-prior-annotation path:
+// the backup's change (a new data key) and the live object's own change (a label added
+// directly against the cluster) both survive the merge.
+func TestMergeExistingResourceMergesDisjointFields(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	live := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-config",
+			Namespace: "default",
+			Labels:    map[string]string{"added-live": "true"},
+		},
+		Data: map[string]string{"existing-key": "live-value"},
+	}
+	r := &RestoreReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(live).Build()}
+
+	desired := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      "app-config",
+				"namespace": "default",
+			},
+			"data": map[string]interface{}{
+				"existing-key":  "live-value",
+				"backed-up-key": "backup-value",
+			},
+		},
+	}
+
+	merged, err := r.mergeExistingResource(context.Background(), desired)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, _, _ := unstructured.NestedStringMap(merged.Object, "data")
+	if data["backed-up-key"] != "backup-value" {
+		t.Errorf("expected merged object to carry the backup's new key, got data=%v", data)
+	}
+	labels := merged.GetLabels()
+	if labels["added-live"] != "true" {
+		t.Errorf("expected merged object to keep the live object's own label, got labels=%v", labels)
+	}
+	if merged.GetAnnotations()[lastAppliedRestoreAnnotation] == "" {
+		t.Error("expected mergeExistingResource to record lastAppliedRestoreAnnotation")
+	}
+}
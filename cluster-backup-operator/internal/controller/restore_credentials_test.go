@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestStorageProviderRecordsCredentialsResolvedCondition verifies a successful minio
+// provider init reports the satisfying credentials chain link as the condition Reason.
+func TestStorageProviderRecordsCredentialsResolvedCondition(t *testing.T) {
+	r := &RestoreReconciler{}
+	restore := &backupv1.Restore{
+		Spec: backupv1.RestoreSpec{
+			Source: backupv1.RestoreSource{
+				StorageLocation: backupv1.StorageLocation{
+					Provider:  "minio",
+					Endpoint:  "minio.backups.svc:9000",
+					AccessKey: "key",
+					SecretKey: "secret",
+				},
+			},
+		},
+	}
+
+	provider, err := r.storageProvider(context.Background(), restore)
+	require.NoError(t, err)
+	require.NotNil(t, provider)
+
+	cond := apimeta.FindStatusCondition(restore.Status.Conditions, conditionTypeCredentialsResolved)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, "Static", cond.Reason)
+}
+
+// TestStorageProviderRecordsFailureCondition verifies a provider that fails to
+// initialize (here, a minio StorageLocation with no endpoint) reports a False
+// CredentialsResolved condition instead of leaving it unset.
+func TestStorageProviderRecordsFailureCondition(t *testing.T) {
+	r := &RestoreReconciler{}
+	restore := &backupv1.Restore{
+		Spec: backupv1.RestoreSpec{
+			Source: backupv1.RestoreSource{
+				StorageLocation: backupv1.StorageLocation{Provider: "minio"},
+			},
+		},
+	}
+
+	_, err := r.storageProvider(context.Background(), restore)
+	require.Error(t, err)
+
+	cond := apimeta.FindStatusCondition(restore.Status.Conditions, conditionTypeCredentialsResolved)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "ProviderInitFailed", cond.Reason)
+}
+
+// TestStorageProviderLeavesConditionUnsetForNonReportingProvider verifies a provider
+// that does not implement storage.CredentialsReporter (the local filesystem backend)
+// is not forced to report a condition it has no credentials source to name.
+func TestStorageProviderLeavesConditionUnsetForNonReportingProvider(t *testing.T) {
+	r := &RestoreReconciler{}
+	restore := &backupv1.Restore{
+		Spec: backupv1.RestoreSpec{
+			Source: backupv1.RestoreSource{
+				StorageLocation: backupv1.StorageLocation{Provider: "local", Endpoint: t.TempDir()},
+			},
+		},
+	}
+
+	provider, err := r.storageProvider(context.Background(), restore)
+	require.NoError(t, err)
+	require.NotNil(t, provider)
+
+	cond := apimeta.FindStatusCondition(restore.Status.Conditions, conditionTypeCredentialsResolved)
+	assert.Nil(t, cond)
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+)
+
+const (
+	// dataMoverRestorePollInterval is how often waitForDataMoverTarget re-checks
+	// whether a restored PVC has been bound and mounted.
+	dataMoverRestorePollInterval = 5 * time.Second
+
+	// dataMoverRestoreTimeout bounds how long restoreDataMoverPVC waits for a consuming
+	// pod before giving up and recording the restore as pending rather than failing it.
+	dataMoverRestoreTimeout = 5 * time.Minute
+)
+
+// dataMoverRestoreEnabled reports whether restore opted into recreating PVC data from
+// backed-up data-mover chunk manifests via RestoreSpec.VolumeRestore.RestoreDataMoverVolumes.
+func dataMoverRestoreEnabled(restore *backupv1.Restore) bool {
+	vr := restore.Spec.VolumeRestore
+	return vr != nil && vr.RestoreDataMoverVolumes != nil && *vr.RestoreDataMoverVolumes
+}
+
+// restoreDataMoverPVC looks for a data-mover chunk manifest that BackupReconciler.
+// dataMoverPersistentVolumeClaim uploaded for pvcName in sourceNamespace, and if one is
+// found, waits (bounded by dataMoverRestoreTimeout) for pvcName - already created in
+// targetNamespace by restoreResource - to be bound and mounted by a running pod, then
+// downloads its chunks in order and extracts them onto the volume via the fs-backup
+// agent DaemonSet. Returns (nil, nil) when the backup has no matching manifest, so
+// PVCs restore as plain manifests same as before this feature existed.
+//
+// Unlike restoreVolumeSnapshotForPVC, this must run after pvc is created: data-mover
+// has no dataSource hook to set beforehand, so the earliest point the volume can be
+// written to is once some consumer actually has it mounted, the same precondition
+// fsBackupPersistentVolumeClaim requires on the backup side.
+func (r *RestoreReconciler) restoreDataMoverPVC(ctx context.Context, provider storage.Provider, bucket, backupPath, sourceNamespace, targetNamespace, pvcName string) (*backupv1.RestoredResource, error) {
+	log := logf.FromContext(ctx)
+
+	manifest, err := loadDataMoverManifest(ctx, provider, bucket, backupPath, sourceNamespace, pvcName)
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		return nil, nil
+	}
+
+	if err := ensureFsBackupDaemonSet(ctx, r.Client, targetNamespace); err != nil {
+		return nil, fmt.Errorf("failed to ensure fs-backup agent daemonset: %w", err)
+	}
+
+	pvc, mountingPod, err := r.waitForDataMoverTarget(ctx, targetNamespace, pvcName, dataMoverRestoreTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if pvc == nil {
+		log.Info("timed out waiting for a pod to mount the restored persistentvolumeclaim, leaving its data-mover restore pending",
+			"persistentvolumeclaim", pvcName, "namespace", targetNamespace)
+		return &backupv1.RestoredResource{
+			APIVersion: "v1",
+			Kind:       "PersistentVolumeClaim",
+			Name:       pvcName,
+			Namespace:  targetNamespace,
+			Action:     "data-mover-pending",
+		}, nil
+	}
+
+	agentPod, err := fsBackupAgentPodOnNode(ctx, r.Client, targetNamespace, mountingPod.Spec.NodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	hostPath := fmt.Sprintf("%s/%s/volumes/kubernetes.io~csi/%s/mount", fsBackupHostPodsDir, mountingPod.UID, pvc.Spec.VolumeName)
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(copyDataMoverChunks(ctx, provider, bucket, manifest, pw))
+	}()
+
+	if err := execTarExtract(ctx, r.RestConfig, agentPod, hostPath, pr); err != nil {
+		return nil, fmt.Errorf("data-mover tar extract in pod %s/%s failed: %w", agentPod.Namespace, agentPod.Name, err)
+	}
+
+	return &backupv1.RestoredResource{
+		APIVersion: "v1",
+		Kind:       "PersistentVolumeClaim",
+		Name:       pvcName,
+		Namespace:  targetNamespace,
+		Action:     "data-mover-restored",
+	}, nil
+}
+
+// copyDataMoverChunks downloads manifest's chunks from provider in order and writes
+// them to w, reassembling the original tar stream dataMoverPersistentVolumeClaim split
+// apart.
+func copyDataMoverChunks(ctx context.Context, provider storage.Provider, bucket string, manifest *dataMoverManifest, w io.Writer) error {
+	for _, hash := range manifest.Chunks {
+		chunk, err := provider.GetObject(ctx, bucket, volumeChunkObjectKey(hash))
+		if err != nil {
+			return fmt.Errorf("failed to download volume chunk %s: %w", hash, err)
+		}
+		_, err = io.Copy(w, chunk)
+		chunk.Close()
+		if err != nil {
+			return fmt.Errorf("failed to stream volume chunk %s: %w", hash, err)
+		}
+	}
+	return nil
+}
+
+// loadDataMoverManifest fetches and parses the data-mover chunk manifest for pvcName in
+// sourceNamespace, returning (nil, nil) if none was uploaded - most PVCs in most
+// backups weren't backed up with a VolumeActionDataMover policy action.
+func loadDataMoverManifest(ctx context.Context, provider storage.Provider, bucket, backupPath, sourceNamespace, pvcName string) (*dataMoverManifest, error) {
+	reader, err := provider.GetObject(ctx, bucket, dataMoverManifestKey(backupPath, sourceNamespace, pvcName))
+	if err != nil {
+		return nil, nil
+	}
+	defer reader.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(reader); err != nil {
+		return nil, fmt.Errorf("failed to read data-mover manifest for persistentvolumeclaim %s: %w", pvcName, err)
+	}
+
+	var manifest dataMoverManifest
+	if err := json.Unmarshal(buf.Bytes(), &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse data-mover manifest for persistentvolumeclaim %s: %w", pvcName, err)
+	}
+	return &manifest, nil
+}
+
+// waitForDataMoverTarget polls until pvcName in namespace is Bound and mounted by a
+// running pod, or timeout elapses, in which case (nil, nil, nil) is returned so the
+// caller can record the restore as pending instead of failing it outright - the
+// consuming workload may simply not have been restored yet.
+func (r *RestoreReconciler) waitForDataMoverTarget(ctx context.Context, namespace, pvcName string, timeout time.Duration) (*corev1.PersistentVolumeClaim, *corev1.Pod, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: pvcName}, pvc); err != nil {
+			return nil, nil, fmt.Errorf("failed to get persistentvolumeclaim %s: %w", pvcName, err)
+		}
+
+		if pvc.Status.Phase == corev1.ClaimBound {
+			pods := &corev1.PodList{}
+			if err := r.List(ctx, pods, client.InNamespace(namespace)); err != nil {
+				return nil, nil, fmt.Errorf("failed to list pods to resolve mount state for persistentvolumeclaim %s: %w", pvcName, err)
+			}
+			for i := range pods.Items {
+				pod := &pods.Items[i]
+				if pod.Status.Phase != corev1.PodRunning {
+					continue
+				}
+				for _, vol := range pod.Spec.Volumes {
+					if vol.PersistentVolumeClaim != nil && vol.PersistentVolumeClaim.ClaimName == pvcName {
+						return pvc, pod, nil
+					}
+				}
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, nil, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(dataMoverRestorePollInterval):
+		}
+	}
+}
+
+// execTarExtract execs `tar -C path -xzf -` in agentPod, streaming data in as stdin, to
+// apply a data-mover chunk manifest's reassembled tar archive onto the volume currently
+// mounted at path.
+func execTarExtract(ctx context.Context, restConfig *rest.Config, agentPod *corev1.Pod, path string, data io.Reader) error {
+	if restConfig == nil {
+		return fmt.Errorf("no RestConfig configured for data-mover restore exec")
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to build clientset for data-mover restore exec: %w", err)
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(agentPod.Name).
+		Namespace(agentPod.Namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: fsBackupContainerName,
+			Command:   []string{"tar", "-C", path, "-xzf", "-"},
+			Stdin:     true,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(restConfig, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create exec executor for data-mover restore: %w", err)
+	}
+
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdin: data, Stdout: io.Discard, Stderr: io.Discard})
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// recordPhaseTransition emits a Kubernetes Event each time restore moves into phase,
+// the same `kubectl describe restore` observability recordRestoreHookResult already
+// gives hook failures. RestorePhaseFailed is reported as a Warning; every other phase
+// as Normal.
+func (r *RestoreReconciler) recordPhaseTransition(restore *backupv1.Restore, phase backupv1.RestorePhase, message string) {
+	if r.Recorder == nil {
+		return
+	}
+
+	eventType := corev1.EventTypeNormal
+	if phase == backupv1.RestorePhaseFailed {
+		eventType = corev1.EventTypeWarning
+	}
+
+	r.Recorder.Event(restore, eventType, "RestorePhase"+string(phase), message)
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// TestRecordPhaseTransitionReportsWarningOnlyForFailed verifies RestorePhaseFailed is
+// reported as a Warning Event and every other phase as Normal.
+func TestRecordPhaseTransitionReportsWarningOnlyForFailed(t *testing.T) {
+	recorder := record.NewFakeRecorder(2)
+	r := &RestoreReconciler{Recorder: recorder}
+	restore := &backupv1.Restore{}
+
+	r.recordPhaseTransition(restore, backupv1.RestorePhaseValidating, "Validating backup source and restore configuration")
+	r.recordPhaseTransition(restore, backupv1.RestorePhaseFailed, "Validation failed: boom")
+
+	normal := <-recorder.Events
+	if !strings.HasPrefix(normal, corev1.EventTypeNormal) || !strings.Contains(normal, "RestorePhaseValidating") {
+		t.Errorf("expected a Normal RestorePhaseValidating event, got %q", normal)
+	}
+
+	warning := <-recorder.Events
+	if !strings.HasPrefix(warning, corev1.EventTypeWarning) || !strings.Contains(warning, "RestorePhaseFailed") {
+		t.Errorf("expected a Warning RestorePhaseFailed event, got %q", warning)
+	}
+}
+
+// TestRecordPhaseTransitionNoopsWithoutRecorder verifies a nil Recorder (e.g. in tests
+// that don't set one up) is silently ignored rather than panicking.
+func TestRecordPhaseTransitionNoopsWithoutRecorder(t *testing.T) {
+	r := &RestoreReconciler{}
+	r.recordPhaseTransition(&backupv1.Restore{}, backupv1.RestorePhaseCompleted, "Restore completed successfully")
+}
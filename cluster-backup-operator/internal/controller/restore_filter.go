@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"path"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// restoreFilter is the compiled "should this object be restored?" predicate built from
+// RestoreTarget's include/exclude lists and label selectors, applied to each manifest
+// read from storage before it reaches the apply loop. Exclusion always wins over
+// inclusion, and an empty Included* list (or a literal "*" entry) means "all".
+type restoreFilter struct {
+	includedNamespaces []string
+	excludedNamespaces []string
+	includedResources  []string
+
+	labelSelector    labels.Selector
+	orLabelSelectors []labels.Selector
+}
+
+// compileRestoreFilter builds a restoreFilter from target, validating its label
+// selectors up front so a typo fails the restore immediately instead of silently
+// matching nothing.
+func compileRestoreFilter(target backupv1.RestoreTarget) (*restoreFilter, error) {
+	f := &restoreFilter{
+		includedNamespaces: target.IncludedNamespaces,
+		excludedNamespaces: target.ExcludedNamespaces,
+		includedResources:  target.IncludedResources,
+	}
+
+	if target.LabelSelector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(target.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target.labelSelector: %w", err)
+		}
+		f.labelSelector = sel
+	}
+
+	for i := range target.OrLabelSelectors {
+		sel, err := metav1.LabelSelectorAsSelector(&target.OrLabelSelectors[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid target.orLabelSelectors[%d]: %w", i, err)
+		}
+		f.orLabelSelectors = append(f.orLabelSelectors, sel)
+	}
+
+	return f, nil
+}
+
+// allowsNamespace reports whether sourceNamespace passes IncludedNamespaces/
+// ExcludedNamespaces.
+func (f *restoreFilter) allowsNamespace(sourceNamespace string) bool {
+	if matchesAny(f.excludedNamespaces, sourceNamespace) {
+		return false
+	}
+	return len(f.includedNamespaces) == 0 || matchesAny(f.includedNamespaces, sourceNamespace)
+}
+
+// allowsResourceType reports whether resourceType passes IncludedResources. Exclusion
+// by resource type is handled separately by isResourceExcluded/ExcludedResources,
+// which also supports per-name exclusion.
+func (f *restoreFilter) allowsResourceType(resourceType string) bool {
+	return len(f.includedResources) == 0 || matchesAny(f.includedResources, resourceType)
+}
+
+// allowsLabels reports whether objLabels passes the configured selectors: if
+// OrLabelSelectors is set, any one of them matching is sufficient; otherwise
+// LabelSelector must match (or there is none, in which case everything passes).
+func (f *restoreFilter) allowsLabels(objLabels map[string]string) bool {
+	set := labels.Set(objLabels)
+
+	if len(f.orLabelSelectors) > 0 {
+		for _, sel := range f.orLabelSelectors {
+			if sel.Matches(set) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if f.labelSelector != nil {
+		return f.labelSelector.Matches(set)
+	}
+
+	return true
+}
+
+// matchesAny reports whether value matches any pattern in patterns. "*" matches
+// everything; any other pattern is a path.Match glob (e.g. "dev-*").
+func matchesAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if matchesGlob(p, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesGlob reports whether value matches pattern, treating "*" as "match
+// everything" before falling back to path.Match so a bare "*" isn't limited by
+// path.Match's single-path-segment semantics.
+func matchesGlob(pattern, value string) bool {
+	if pattern == "*" {
+		return true
+	}
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
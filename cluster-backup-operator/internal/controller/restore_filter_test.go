@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestRestoreFilterExclusionBeatsInclusion verifies that an ExcludedNamespaces entry
+// always wins over a matching IncludedNamespaces entry.
+func TestRestoreFilterExclusionBeatsInclusion(t *testing.T) {
+	filter, err := compileRestoreFilter(backupv1.RestoreTarget{
+		IncludedNamespaces: []string{"*"},
+		ExcludedNamespaces: []string{"kube-system"},
+	})
+	if err != nil {
+		t.Fatalf("compileRestoreFilter() error = %v", err)
+	}
+
+	if filter.allowsNamespace("kube-system") {
+		t.Errorf("expected kube-system to be excluded despite matching IncludedNamespaces \"*\"")
+	}
+	if !filter.allowsNamespace("default") {
+		t.Errorf("expected default to be allowed by IncludedNamespaces \"*\"")
+	}
+}
+
+// TestRestoreFilterIncludedNamespacesGlob verifies glob matching for IncludedNamespaces.
+func TestRestoreFilterIncludedNamespacesGlob(t *testing.T) {
+	filter, err := compileRestoreFilter(backupv1.RestoreTarget{
+		IncludedNamespaces: []string{"dev-*"},
+	})
+	if err != nil {
+		t.Fatalf("compileRestoreFilter() error = %v", err)
+	}
+
+	if !filter.allowsNamespace("dev-team-a") {
+		t.Errorf("expected dev-team-a to match glob dev-*")
+	}
+	if filter.allowsNamespace("prod") {
+		t.Errorf("expected prod not to match glob dev-*")
+	}
+}
+
+// TestRestoreFilterIncludedResourcesEmptyMeansAll verifies that an empty
+// IncludedResources allows every resource type through.
+func TestRestoreFilterIncludedResourcesEmptyMeansAll(t *testing.T) {
+	filter, err := compileRestoreFilter(backupv1.RestoreTarget{})
+	if err != nil {
+		t.Fatalf("compileRestoreFilter() error = %v", err)
+	}
+
+	if !filter.allowsResourceType("deployments") {
+		t.Errorf("expected an empty IncludedResources to allow every resource type")
+	}
+}
+
+// TestRestoreFilterIncludedResourcesGlob verifies glob matching for IncludedResources.
+func TestRestoreFilterIncludedResourcesGlob(t *testing.T) {
+	filter, err := compileRestoreFilter(backupv1.RestoreTarget{
+		IncludedResources: []string{"config*"},
+	})
+	if err != nil {
+		t.Fatalf("compileRestoreFilter() error = %v", err)
+	}
+
+	if !filter.allowsResourceType("configmaps") {
+		t.Errorf("expected configmaps to match glob config*")
+	}
+	if filter.allowsResourceType("secrets") {
+		t.Errorf("expected secrets not to match glob config*")
+	}
+}
+
+// TestRestoreFilterLabelSelector verifies that a resource must match LabelSelector
+// when one is configured.
+func TestRestoreFilterLabelSelector(t *testing.T) {
+	filter, err := compileRestoreFilter(backupv1.RestoreTarget{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "frontend"}},
+	})
+	if err != nil {
+		t.Fatalf("compileRestoreFilter() error = %v", err)
+	}
+
+	if !filter.allowsLabels(map[string]string{"tier": "frontend"}) {
+		t.Errorf("expected a matching label set to be allowed")
+	}
+	if filter.allowsLabels(map[string]string{"tier": "backend"}) {
+		t.Errorf("expected a non-matching label set to be rejected")
+	}
+}
+
+// TestRestoreFilterOrLabelSelectors verifies OR-of-selectors semantics: a resource
+// matching any one of OrLabelSelectors is allowed.
+func TestRestoreFilterOrLabelSelectors(t *testing.T) {
+	filter, err := compileRestoreFilter(backupv1.RestoreTarget{
+		OrLabelSelectors: []metav1.LabelSelector{
+			{MatchLabels: map[string]string{"tier": "frontend"}},
+			{MatchLabels: map[string]string{"tier": "backend"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("compileRestoreFilter() error = %v", err)
+	}
+
+	if !filter.allowsLabels(map[string]string{"tier": "backend"}) {
+		t.Errorf("expected a label set matching the second selector to be allowed")
+	}
+	if filter.allowsLabels(map[string]string{"tier": "database"}) {
+		t.Errorf("expected a label set matching neither selector to be rejected")
+	}
+}
+
+// TestCompileRestoreFilterRejectsInvalidSelector verifies that an invalid selector is
+// caught at compile time rather than silently matching nothing at restore time.
+func TestCompileRestoreFilterRejectsInvalidSelector(t *testing.T) {
+	_, err := compileRestoreFilter(backupv1.RestoreTarget{
+		LabelSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "tier", Operator: "NotAnOperator"},
+			},
+		},
+	})
+	if err == nil {
+		t.Errorf("expected an error for an invalid label selector, got nil")
+	}
+}
+
+// TestIsResourceExcludedGlob verifies that ExcludedResources supports glob patterns on
+// both the resource-type and resourceType/name forms.
+func TestIsResourceExcludedGlob(t *testing.T) {
+	excluded := []string{"secrets/db-*"}
+
+	if !isResourceExcluded("secrets", "db-creds", excluded) {
+		t.Errorf("expected secrets/db-creds to match glob secrets/db-*")
+	}
+	if isResourceExcluded("secrets", "api-key", excluded) {
+		t.Errorf("expected secrets/api-key not to match glob secrets/db-*")
+	}
+}
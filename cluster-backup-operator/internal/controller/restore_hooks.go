@@ -0,0 +1,322 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// restoreHookDefaultTimeout bounds how long a RestoreHook waits for a Pod to become
+// Ready, an init container to finish, or a wait-for-condition Condition to be met,
+// when RestoreHook.Timeout is unset.
+const restoreHookDefaultTimeout = 5 * time.Minute
+
+// restoreHookPollInterval is how often a RestoreHook re-checks Pod readiness or its
+// wait-for-condition Condition.
+const restoreHookPollInterval = 5 * time.Second
+
+// runRestoreHooks runs every restore.Spec.Hooks entry matching resource, recording a
+// RestoreHookResult for each. Hook failures are recorded and logged as Events, not
+// returned as errors: a hook that times out shouldn't fail the whole restore any more
+// than a slow post-backup hook fails a backup.
+func (r *RestoreReconciler) runRestoreHooks(ctx context.Context, restore *backupv1.Restore, resource *unstructured.Unstructured) {
+	for _, hook := range restore.Spec.Hooks {
+		if !restoreHookMatches(hook.Selector, resource.GetAPIVersion(), resource.GetKind(), resource.GetNamespace(), resource.GetLabels()) {
+			continue
+		}
+
+		timeout := hook.Timeout.Duration
+		if timeout <= 0 {
+			timeout = restoreHookDefaultTimeout
+		}
+
+		var err error
+		switch hook.Type {
+		case backupv1.RestoreHookTypeExec:
+			err = r.runExecHook(ctx, hook, resource, timeout)
+		case backupv1.RestoreHookTypeInitContainer:
+			err = r.runInitContainerHook(ctx, hook, resource, timeout)
+		case backupv1.RestoreHookTypeWaitForCondition:
+			err = r.runWaitForConditionHook(ctx, hook, resource, timeout)
+		default:
+			err = fmt.Errorf("unknown restore hook type %q", hook.Type)
+		}
+
+		r.recordRestoreHookResult(restore, resource, hook.Type, err)
+	}
+}
+
+// restoreHookMatches reports whether selector applies to a resource with the given
+// apiVersion/kind/namespace/labels.
+func restoreHookMatches(selector backupv1.RestoreHookSelector, apiVersion, kind, namespace string, resourceLabels map[string]string) bool {
+	if selector.APIVersion != apiVersion || selector.Kind != kind {
+		return false
+	}
+
+	if len(selector.Namespaces) > 0 {
+		found := false
+		for _, ns := range selector.Namespaces {
+			if ns == namespace {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if selector.LabelSelector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(selector.LabelSelector)
+		if err != nil {
+			return false
+		}
+		if !sel.Matches(labels.Set(resourceLabels)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// runExecHook waits for resource (which must be a Pod) to become Ready, then runs
+// hook.Command inside hook.Container via the Kubernetes exec subresource.
+func (r *RestoreReconciler) runExecHook(ctx context.Context, hook backupv1.RestoreHook, resource *unstructured.Unstructured, timeout time.Duration) error {
+	if resource.GetKind() != "Pod" {
+		return fmt.Errorf("exec hook requires a Pod, got %s", resource.GetKind())
+	}
+	if len(hook.Command) == 0 {
+		return fmt.Errorf("exec hook has no command configured")
+	}
+
+	pod, err := r.waitForPodReady(ctx, resource.GetNamespace(), resource.GetName(), timeout)
+	if err != nil {
+		return err
+	}
+
+	container := hook.Container
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+	if r.RestConfig == nil {
+		return fmt.Errorf("no RestConfig configured for exec hooks")
+	}
+
+	clientset, err := kubernetes.NewForConfig(r.RestConfig)
+	if err != nil {
+		return fmt.Errorf("failed to build clientset for exec hook: %w", err)
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   hook.Command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(r.RestConfig, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create exec executor: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := executor.StreamWithContext(execCtx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr}); err != nil {
+		return fmt.Errorf("exec %v in pod %s/%s container %s failed: %w (stderr: %s)",
+			hook.Command, pod.Namespace, pod.Name, container, err, truncate(stderr.String(), 256))
+	}
+
+	return nil
+}
+
+// runInitContainerHook waits for hook.Container, an init container of resource (which
+// must be a Pod), to terminate successfully.
+func (r *RestoreReconciler) runInitContainerHook(ctx context.Context, hook backupv1.RestoreHook, resource *unstructured.Unstructured, timeout time.Duration) error {
+	if resource.GetKind() != "Pod" {
+		return fmt.Errorf("init-container hook requires a Pod, got %s", resource.GetKind())
+	}
+	if hook.Container == "" {
+		return fmt.Errorf("init-container hook has no container configured")
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		pod := &corev1.Pod{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: resource.GetNamespace(), Name: resource.GetName()}, pod); err != nil {
+			return fmt.Errorf("failed to get pod %s/%s: %w", resource.GetNamespace(), resource.GetName(), err)
+		}
+
+		for _, status := range pod.Status.InitContainerStatuses {
+			if status.Name != hook.Container {
+				continue
+			}
+			if status.State.Terminated != nil {
+				if status.State.Terminated.ExitCode == 0 {
+					return nil
+				}
+				return fmt.Errorf("init container %s exited with code %d", hook.Container, status.State.Terminated.ExitCode)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for init container %s to finish", hook.Container)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(restoreHookPollInterval):
+		}
+	}
+}
+
+// runWaitForConditionHook polls resource until hook.Condition (a JSONPath
+// expression) evaluates to a non-empty, non-"false" result.
+func (r *RestoreReconciler) runWaitForConditionHook(ctx context.Context, hook backupv1.RestoreHook, resource *unstructured.Unstructured, timeout time.Duration) error {
+	if hook.Condition == "" {
+		return fmt.Errorf("wait-for-condition hook has no condition configured")
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(resource.GroupVersionKind())
+		if err := r.Get(ctx, client.ObjectKey{Namespace: resource.GetNamespace(), Name: resource.GetName()}, current); err != nil {
+			return fmt.Errorf("failed to get %s %s/%s: %w", resource.GetKind(), resource.GetNamespace(), resource.GetName(), err)
+		}
+
+		met, err := evaluateJSONPathCondition(hook.Condition, current.Object)
+		if err != nil {
+			return err
+		}
+		if met {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for condition %q", hook.Condition)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(restoreHookPollInterval):
+		}
+	}
+}
+
+// evaluateJSONPathCondition reports whether condition, a JSONPath expression such as
+// "{.status.conditions[?(@.type=='Ready')].status}", selects at least one non-empty,
+// non-"false" value out of obj.
+func evaluateJSONPathCondition(condition string, obj map[string]interface{}) (bool, error) {
+	jp := jsonpath.New("restoreHookCondition")
+	jp.AllowMissingKeys(true)
+	if err := jp.Parse(condition); err != nil {
+		return false, fmt.Errorf("invalid condition %q: %w", condition, err)
+	}
+
+	results, err := jp.FindResults(obj)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate condition %q: %w", condition, err)
+	}
+
+	for _, set := range results {
+		for _, value := range set {
+			s := fmt.Sprintf("%v", value.Interface())
+			if s != "" && s != "false" {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// waitForPodReady polls the named Pod until its Ready condition is True, or timeout
+// elapses.
+func (r *RestoreReconciler) waitForPodReady(ctx context.Context, namespace, name string, timeout time.Duration) (*corev1.Pod, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		pod := &corev1.Pod{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, pod); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get pod %s/%s: %w", namespace, name, err)
+			}
+		} else {
+			for _, cond := range pod.Status.Conditions {
+				if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+					return pod, nil
+				}
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for pod %s/%s to become ready", namespace, name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(restoreHookPollInterval):
+		}
+	}
+}
+
+// recordRestoreHookResult appends a RestoreHookResult to restore.Status and, on
+// failure, emits a Warning Event on the Restore object.
+func (r *RestoreReconciler) recordRestoreHookResult(restore *backupv1.Restore, resource *unstructured.Unstructured, hookType string, hookErr error) {
+	result := backupv1.RestoreHookResult{
+		Kind:      resource.GetKind(),
+		Name:      resource.GetName(),
+		Namespace: resource.GetNamespace(),
+		Type:      hookType,
+		Success:   hookErr == nil,
+	}
+
+	if hookErr != nil {
+		result.Message = hookErr.Error()
+		if r.Recorder != nil {
+			r.Recorder.Eventf(restore, corev1.EventTypeWarning, "RestoreHookFailed",
+				"%s hook failed for %s %s/%s: %v", hookType, resource.GetKind(), resource.GetNamespace(), resource.GetName(), hookErr)
+		}
+	}
+
+	restore.Status.HookResults = append(restore.Status.HookResults, result)
+}
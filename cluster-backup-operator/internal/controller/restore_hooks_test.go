@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRestoreHookMatches(t *testing.T) {
+	selector := backupv1.RestoreHookSelector{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Namespaces: []string{"prod"},
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "db"},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		apiVer    string
+		kind      string
+		namespace string
+		labels    map[string]string
+		want      bool
+	}{
+		{"full match", "v1", "Pod", "prod", map[string]string{"app": "db"}, true},
+		{"wrong kind", "v1", "Deployment", "prod", map[string]string{"app": "db"}, false},
+		{"wrong namespace", "v1", "Pod", "staging", map[string]string{"app": "db"}, false},
+		{"missing label", "v1", "Pod", "prod", map[string]string{"app": "other"}, false},
+	}
+
+	for _, c := range cases {
+		if got := restoreHookMatches(selector, c.apiVer, c.kind, c.namespace, c.labels); got != c.want {
+			t.Errorf("%s: restoreHookMatches() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestEvaluateJSONPathCondition(t *testing.T) {
+	obj := map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+	}
+
+	met, err := evaluateJSONPathCondition(`{.status.conditions[?(@.type=='Ready')].status}`, obj)
+	if err != nil {
+		t.Fatalf("evaluateJSONPathCondition() returned error: %v", err)
+	}
+	if !met {
+		t.Error("expected condition to be met")
+	}
+
+	met, err = evaluateJSONPathCondition(`{.status.conditions[?(@.type=='Ready')].status}`, map[string]interface{}{"status": map[string]interface{}{}})
+	if err != nil {
+		t.Fatalf("evaluateJSONPathCondition() returned error for missing field: %v", err)
+	}
+	if met {
+		t.Error("expected condition to be unmet when the field is absent")
+	}
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// RestoreItemActionResult is what a RestoreItemAction.Execute returns for one resource.
+type RestoreItemActionResult struct {
+	// Skip, when true, tells restoreResource to drop this resource from the restore
+	// entirely instead of creating/updating it.
+	Skip bool
+}
+
+// RestoreItemAction mutates or filters one resource, in process, as restoreResource
+// prepares it for Create/Update. Unlike the gRPC-based plugin.ItemAction pipeline (see
+// pkg/plugin and RestoreSpec.ItemActions), which runs user-configured, possibly
+// out-of-process plugins named in the Restore spec, a RestoreItemAction is compiled
+// into the operator and runs unconditionally for every restore - it's the extension
+// point cleanResourceForRestore's resource-kind switch statement used to be. Register
+// custom ones with RestoreReconciler.RegisterRestoreItemAction.
+type RestoreItemAction interface {
+	// Name identifies this action in error messages.
+	Name() string
+
+	// Applies reports whether this action should run against resources of the given
+	// GroupVersionKind (Version is ignored, matching the way cleanResourceForRestore's
+	// old switch only ever keyed off Kind).
+	Applies(gvk schema.GroupVersionKind) bool
+
+	// Execute mutates resource in place. restore is the Restore being processed, for
+	// actions that need its spec (e.g. VolumeRestore.StorageClassMapping).
+	Execute(resource *unstructured.Unstructured, restore *backupv1.Restore) (RestoreItemActionResult, error)
+}
+
+// runRestoreItemActions runs every registered RestoreItemAction whose Applies matches
+// resource's GVK, in order, stopping as soon as one returns Skip. r.restoreItemActions
+// defaults to defaultRestoreItemActions() lazily, so a zero-value RestoreReconciler
+// (as most of this package's tests construct) still gets the built-in behavior without
+// every test having to call RegisterRestoreItemAction itself.
+func (r *RestoreReconciler) runRestoreItemActions(resource *unstructured.Unstructured, restore *backupv1.Restore) (bool, error) {
+	actions := r.restoreItemActions
+	if actions == nil {
+		actions = defaultRestoreItemActions()
+	}
+
+	gvk := resource.GroupVersionKind()
+	for _, action := range actions {
+		if !action.Applies(gvk) {
+			continue
+		}
+		result, err := action.Execute(resource, restore)
+		if err != nil {
+			return false, fmt.Errorf("restore item action %q: %w", action.Name(), err)
+		}
+		if result.Skip {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RegisterRestoreItemAction appends action to the restore item action pipeline every
+// restored resource runs through, after the built-ins (see defaultRestoreItemActions).
+// Call before the reconciler starts processing restores; not safe to call concurrently
+// with an in-flight Reconcile.
+func (r *RestoreReconciler) RegisterRestoreItemAction(action RestoreItemAction) {
+	if r.restoreItemActions == nil {
+		r.restoreItemActions = defaultRestoreItemActions()
+	}
+	r.restoreItemActions = append(r.restoreItemActions, action)
+}
+
+// defaultRestoreItemActions are the resource-kind special cases cleanResourceForRestore
+// used to hard-code, now implemented as the first-class extension point other users of
+// it plug into the same way.
+func defaultRestoreItemActions() []RestoreItemAction {
+	return []RestoreItemAction{
+		podOwnedSkipAction{},
+		serviceClusterIPResetAction{},
+		pvcVolumeAndStorageClassRemapAction{},
+		serviceAccountTokenSecretPruneAction{},
+		jobSelectorResetAction{},
+	}
+}
+
+func appliesToKind(gvk schema.GroupVersionKind, group, kind string) bool {
+	return gvk.Group == group && gvk.Kind == kind
+}
+
+// podOwnedSkipAction drops Pods owned by a controller (ReplicaSet, Job, DaemonSet,
+// StatefulSet, ...) from the restore: the controller that owns them doesn't exist yet
+// at this point in the restore and will recreate them on its own once it is restored,
+// so restoring the Pod directly would just leave a duplicate, orphaned copy behind
+// once the controller's replacement shows up. A standalone Pod with no controller
+// owner (e.g. one created directly, not via a Deployment) restores as normal. This
+// must run before cleanResourceForRestore strips controller owner references, or the
+// check below would never see them.
+type podOwnedSkipAction struct{}
+
+func (podOwnedSkipAction) Name() string { return "pod-owned-skip" }
+
+func (podOwnedSkipAction) Applies(gvk schema.GroupVersionKind) bool {
+	return appliesToKind(gvk, "", "Pod")
+}
+
+func (podOwnedSkipAction) Execute(resource *unstructured.Unstructured, _ *backupv1.Restore) (RestoreItemActionResult, error) {
+	for _, ref := range resource.GetOwnerReferences() {
+		if ref.Controller != nil && *ref.Controller {
+			return RestoreItemActionResult{Skip: true}, nil
+		}
+	}
+	return RestoreItemActionResult{}, nil
+}
+
+// serviceClusterIPResetAction clears the cluster-assigned networking fields a restored
+// Service must not bring with it: clusterIP/clusterIPs are allocated from the target
+// cluster's own service CIDR, and a stale value would either collide with something
+// already using it or simply not be a valid address in that cluster; each port's
+// nodePort is allocated from the target cluster's own node-port range for the same
+// reason.
+type serviceClusterIPResetAction struct{}
+
+func (serviceClusterIPResetAction) Name() string { return "service-cluster-ip-reset" }
+
+func (serviceClusterIPResetAction) Applies(gvk schema.GroupVersionKind) bool {
+	return appliesToKind(gvk, "", "Service")
+}
+
+func (serviceClusterIPResetAction) Execute(resource *unstructured.Unstructured, _ *backupv1.Restore) (RestoreItemActionResult, error) {
+	unstructured.RemoveNestedField(resource.Object, "spec", "clusterIP")
+	unstructured.RemoveNestedField(resource.Object, "spec", "clusterIPs")
+
+	ports, found, err := unstructured.NestedSlice(resource.Object, "spec", "ports")
+	if err != nil || !found {
+		return RestoreItemActionResult{}, nil
+	}
+	changed := false
+	for i, p := range ports {
+		port, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, found := port["nodePort"]; found {
+			delete(port, "nodePort")
+			ports[i] = port
+			changed = true
+		}
+	}
+	if changed {
+		_ = unstructured.SetNestedSlice(resource.Object, ports, "spec", "ports")
+	}
+	return RestoreItemActionResult{}, nil
+}
+
+// pvcVolumeAndStorageClassRemapAction clears a restored PersistentVolumeClaim's
+// spec.volumeName (the PersistentVolume it was bound to in the source cluster almost
+// certainly doesn't exist in the target one, and a dangling reference would leave the
+// PVC stuck Pending forever instead of letting the target cluster bind or provision a
+// new volume for it) and remaps spec.storageClassName via
+// RestoreSpec.VolumeRestore.StorageClassMapping, for target clusters whose storage
+// classes are named differently than the source's.
+type pvcVolumeAndStorageClassRemapAction struct{}
+
+func (pvcVolumeAndStorageClassRemapAction) Name() string { return "pvc-volume-storageclass-remap" }
+
+func (pvcVolumeAndStorageClassRemapAction) Applies(gvk schema.GroupVersionKind) bool {
+	return appliesToKind(gvk, "", "PersistentVolumeClaim")
+}
+
+func (pvcVolumeAndStorageClassRemapAction) Execute(resource *unstructured.Unstructured, restore *backupv1.Restore) (RestoreItemActionResult, error) {
+	unstructured.RemoveNestedField(resource.Object, "spec", "volumeName")
+
+	var mapping map[string]string
+	if restore.Spec.VolumeRestore != nil {
+		mapping = restore.Spec.VolumeRestore.StorageClassMapping
+	}
+	if mapping == nil {
+		return RestoreItemActionResult{}, nil
+	}
+
+	class, found, err := unstructured.NestedString(resource.Object, "spec", "storageClassName")
+	if err != nil || !found || class == "" {
+		return RestoreItemActionResult{}, nil
+	}
+	if remapped := resolveVolumeSnapshotClass(mapping, &class); remapped != "" {
+		_ = unstructured.SetNestedField(resource.Object, remapped, "spec", "storageClassName")
+	}
+	return RestoreItemActionResult{}, nil
+}
+
+// serviceAccountTokenSecretPruneAction drops auto-generated token Secret references
+// from a restored ServiceAccount's spec.secrets: those Secrets belonged to the source
+// cluster's API server signing key and either don't exist in the target cluster or, if
+// restored verbatim alongside the ServiceAccount, hold a token nothing can validate
+// against the target cluster's own key. Leaving the dangling reference behind is
+// harmless to the API server but surprises anyone reading the restored object expecting
+// a working token; the target cluster (or token request API) issues a fresh one once
+// something actually needs it.
+type serviceAccountTokenSecretPruneAction struct{}
+
+func (serviceAccountTokenSecretPruneAction) Name() string { return "serviceaccount-token-secret-prune" }
+
+func (serviceAccountTokenSecretPruneAction) Applies(gvk schema.GroupVersionKind) bool {
+	return appliesToKind(gvk, "", "ServiceAccount")
+}
+
+func (serviceAccountTokenSecretPruneAction) Execute(resource *unstructured.Unstructured, _ *backupv1.Restore) (RestoreItemActionResult, error) {
+	name := resource.GetName()
+	secrets, found, err := unstructured.NestedSlice(resource.Object, "secrets")
+	if err != nil || !found {
+		return RestoreItemActionResult{}, nil
+	}
+
+	kept := secrets[:0]
+	for _, s := range secrets {
+		secret, ok := s.(map[string]interface{})
+		if !ok {
+			kept = append(kept, s)
+			continue
+		}
+		secretName, _ := secret["name"].(string)
+		if strings.HasPrefix(secretName, name+"-token-") {
+			continue
+		}
+		kept = append(kept, s)
+	}
+
+	if len(kept) == 0 {
+		unstructured.RemoveNestedField(resource.Object, "secrets")
+	} else {
+		_ = unstructured.SetNestedSlice(resource.Object, kept, "secrets")
+	}
+	return RestoreItemActionResult{}, nil
+}
+
+// jobSelectorResetAction clears the label selector and matching pod-template label the
+// Job controller stamps onto every Job it creates (spec.selector and the
+// controller-uid/batch.kubernetes.io/controller-uid template label), for both Job and,
+// nested under spec.jobTemplate, CronJob. Both are derived from the Job's UID, which
+// changes on restore; restoring them verbatim either fails API validation (selector not
+// matching the template labels once the UID differs) or, if they happened to still
+// match, would have the new Job's pods selected by the wrong, stale identity. Leaving
+// them unset lets the Job controller regenerate both from the new object's own UID, the
+// same way it does for a Job created fresh.
+type jobSelectorResetAction struct{}
+
+func (jobSelectorResetAction) Name() string { return "job-selector-reset" }
+
+func (jobSelectorResetAction) Applies(gvk schema.GroupVersionKind) bool {
+	return appliesToKind(gvk, "batch", "Job") || appliesToKind(gvk, "batch", "CronJob")
+}
+
+func (jobSelectorResetAction) Execute(resource *unstructured.Unstructured, _ *backupv1.Restore) (RestoreItemActionResult, error) {
+	jobSpecPath := []string{"spec"}
+	if resource.GetKind() == "CronJob" {
+		jobSpecPath = []string{"spec", "jobTemplate", "spec"}
+	}
+
+	resetJobSpec(resource.Object, jobSpecPath)
+	return RestoreItemActionResult{}, nil
+}
+
+// resetJobSpec removes the controller-uid-derived selector and template label a single
+// JobSpec carries, at the given path within obj.
+func resetJobSpec(obj map[string]interface{}, jobSpecPath []string) {
+	unstructured.RemoveNestedField(obj, append(append([]string{}, jobSpecPath...), "selector")...)
+
+	labelsPath := append(append([]string{}, jobSpecPath...), "template", "metadata", "labels")
+	labels, found, err := unstructured.NestedStringMap(obj, labelsPath...)
+	if err != nil || !found {
+		return
+	}
+	delete(labels, "controller-uid")
+	delete(labels, "batch.kubernetes.io/controller-uid")
+	_ = unstructured.SetNestedStringMap(obj, labels, labelsPath...)
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// TestPodOwnedSkipActionSkipsControllerOwnedPods verifies a Pod owned by a controller
+// (e.g. a ReplicaSet) is dropped from the restore, while a standalone Pod is not.
+func TestPodOwnedSkipActionSkipsControllerOwnedPods(t *testing.T) {
+	controller := true
+	owned := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	owned.SetKind("Pod")
+	owned.SetOwnerReferences([]metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-abc123", Controller: &controller}})
+
+	result, err := podOwnedSkipAction{}.Execute(owned, &backupv1.Restore{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Skip {
+		t.Error("expected a controller-owned pod to be skipped")
+	}
+
+	standalone := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	standalone.SetKind("Pod")
+
+	result, err = podOwnedSkipAction{}.Execute(standalone, &backupv1.Restore{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Skip {
+		t.Error("expected a standalone pod not to be skipped")
+	}
+}
+
+// TestServiceClusterIPResetActionClearsNetworkingFields verifies clusterIP,
+// clusterIPs, and every port's nodePort are removed.
+func TestServiceClusterIPResetActionClearsNetworkingFields(t *testing.T) {
+	svc := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"clusterIP":  "10.0.0.5",
+			"clusterIPs": []interface{}{"10.0.0.5"},
+			"ports": []interface{}{
+				map[string]interface{}{"port": int64(80), "nodePort": int64(31080)},
+			},
+		},
+	}}
+	svc.SetKind("Service")
+
+	action := serviceClusterIPResetAction{}
+	if _, err := action.Execute(svc, &backupv1.Restore{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, found, _ := unstructured.NestedString(svc.Object, "spec", "clusterIP"); found {
+		t.Error("expected clusterIP to be removed")
+	}
+	if _, found, _ := unstructured.NestedSlice(svc.Object, "spec", "clusterIPs"); found {
+		t.Error("expected clusterIPs to be removed")
+	}
+	ports, _, _ := unstructured.NestedSlice(svc.Object, "spec", "ports")
+	port := ports[0].(map[string]interface{})
+	if _, found := port["nodePort"]; found {
+		t.Error("expected port.nodePort to be removed")
+	}
+	if port["port"] != int64(80) {
+		t.Error("expected port.port to be left untouched")
+	}
+}
+
+// TestPVCVolumeAndStorageClassRemapActionRemapsStorageClass verifies volumeName is
+// always cleared and storageClassName is remapped using an explicit
+// VolumeRestoreOptions.StorageClassMapping entry.
+func TestPVCVolumeAndStorageClassRemapActionRemapsStorageClass(t *testing.T) {
+	pvc := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"volumeName":       "pv-abc123",
+			"storageClassName": "source-ssd",
+		},
+	}}
+	pvc.SetKind("PersistentVolumeClaim")
+
+	restore := &backupv1.Restore{
+		Spec: backupv1.RestoreSpec{
+			VolumeRestore: &backupv1.VolumeRestoreOptions{
+				StorageClassMapping: map[string]string{"source-ssd": "target-ssd"},
+			},
+		},
+	}
+
+	if _, err := (pvcVolumeAndStorageClassRemapAction{}).Execute(pvc, restore); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, found, _ := unstructured.NestedString(pvc.Object, "spec", "volumeName"); found {
+		t.Error("expected volumeName to be removed")
+	}
+	class, _, _ := unstructured.NestedString(pvc.Object, "spec", "storageClassName")
+	if class != "target-ssd" {
+		t.Errorf("expected storageClassName to be remapped to target-ssd, got %q", class)
+	}
+}
+
+// TestServiceAccountTokenSecretPruneActionPrunesTokenSecretsOnly verifies only the
+// auto-generated "<name>-token-*" entries are removed, not other referenced secrets.
+func TestServiceAccountTokenSecretPruneActionPrunesTokenSecretsOnly(t *testing.T) {
+	sa := &unstructured.Unstructured{Object: map[string]interface{}{
+		"secrets": []interface{}{
+			map[string]interface{}{"name": "deploy-sa-token-xyz12"},
+			map[string]interface{}{"name": "registry-credentials"},
+		},
+	}}
+	sa.SetKind("ServiceAccount")
+	sa.SetName("deploy-sa")
+
+	if _, err := (serviceAccountTokenSecretPruneAction{}).Execute(sa, &backupv1.Restore{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secrets, _, _ := unstructured.NestedSlice(sa.Object, "secrets")
+	if len(secrets) != 1 {
+		t.Fatalf("expected 1 remaining secret, got %d: %v", len(secrets), secrets)
+	}
+	if secrets[0].(map[string]interface{})["name"] != "registry-credentials" {
+		t.Errorf("expected the non-token secret to survive, got %v", secrets[0])
+	}
+}
+
+// TestJobSelectorResetActionClearsSelectorAndControllerUIDLabel verifies both a Job
+// and, nested under spec.jobTemplate, a CronJob have their stale selector/label
+// cleared.
+func TestJobSelectorResetActionClearsSelectorAndControllerUIDLabel(t *testing.T) {
+	job := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"controller-uid": "abc"}},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"controller-uid": "abc", "job-name": "demo"},
+				},
+			},
+		},
+	}}
+	job.SetKind("Job")
+
+	if _, err := (jobSelectorResetAction{}).Execute(job, &backupv1.Restore{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, found, _ := unstructured.NestedMap(job.Object, "spec", "selector"); found {
+		t.Error("expected spec.selector to be removed")
+	}
+	labels, _, _ := unstructured.NestedStringMap(job.Object, "spec", "template", "metadata", "labels")
+	if _, found := labels["controller-uid"]; found {
+		t.Error("expected controller-uid label to be removed")
+	}
+	if labels["job-name"] != "demo" {
+		t.Error("expected unrelated labels to be left untouched")
+	}
+
+	cronJob := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"jobTemplate": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"controller-uid": "def"}},
+				},
+			},
+		},
+	}}
+	cronJob.SetKind("CronJob")
+
+	if _, err := (jobSelectorResetAction{}).Execute(cronJob, &backupv1.Restore{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, found, _ := unstructured.NestedMap(cronJob.Object, "spec", "jobTemplate", "spec", "selector"); found {
+		t.Error("expected spec.jobTemplate.spec.selector to be removed")
+	}
+}
+
+// TestDefaultRestoreItemActionsAppliesByGVK spot-checks a couple of Applies() results
+// to guard against a copy-paste group/kind mismatch.
+func TestDefaultRestoreItemActionsAppliesByGVK(t *testing.T) {
+	actions := defaultRestoreItemActions()
+	if len(actions) != 5 {
+		t.Fatalf("expected 5 built-in restore item actions, got %d", len(actions))
+	}
+
+	var matchedJob, matchedService bool
+	for _, action := range actions {
+		if action.Name() == "job-selector-reset" && action.Applies(schema.GroupVersionKind{Group: "batch", Kind: "Job"}) {
+			matchedJob = true
+		}
+		if action.Name() == "service-cluster-ip-reset" && !action.Applies(schema.GroupVersionKind{Group: "batch", Kind: "Service"}) {
+			matchedService = true
+		}
+	}
+	if !matchedJob {
+		t.Error("expected job-selector-reset to apply to batch/Job")
+	}
+	if !matchedService {
+		t.Error("expected service-cluster-ip-reset not to apply to a batch-group Service lookalike")
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"text/template"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// resourceModifierConfigMapKey is the ConfigMap data key holding the JSON-encoded
+// []resourceModifierRule that ResourceModifierRef.Name points at.
+const resourceModifierConfigMapKey = "modifiers.json"
+
+// resourceModifierRule rewrites resources matching GroupResource, ResourceNameRegex,
+// and Conditions by applying Patches before the resource is created or updated on the
+// target cluster.
+type resourceModifierRule struct {
+	// GroupResource selects which resources this rule considers, in "group/Kind"
+	// form (e.g. "apps/Deployment", "/PersistentVolumeClaim" for the core group) -
+	// this tree has no RESTMapper wired up to pluralize a Kind into the canonical
+	// "resource.group" form, so it reuses the same "group/Kind" convention
+	// RestoreSpec.ItemActions and BackupSource.IncludeCRDs already use.
+	GroupResource string `json:"groupResource"`
+
+	// ResourceNameRegex further restricts matches to resources whose name matches
+	// this regular expression. Empty matches every name.
+	ResourceNameRegex string `json:"resourceNameRegex,omitempty"`
+
+	// Conditions are JSONPath expressions (see evaluateJSONPathCondition) evaluated
+	// against the decoded resource; every condition must evaluate to a non-empty,
+	// non-false result for the rule to match. Empty always matches.
+	Conditions []string `json:"conditions,omitempty"`
+
+	// Patches are applied in order once GroupResource, ResourceNameRegex, and
+	// Conditions all match.
+	Patches []resourceModifierPatch `json:"patches"`
+}
+
+// resourceModifierPatch is one JSON Patch (RFC 6902) or JSON Merge Patch (RFC 7396)
+// applied by a resourceModifierRule.
+type resourceModifierPatch struct {
+	// Type selects the patch format: "json" (RFC 6902) or "merge" (RFC 7396).
+	// +kubebuilder:validation:Enum=json;merge
+	Type string `json:"type"`
+
+	// Value is the patch document (a JSON Patch array, or a Merge Patch object),
+	// evaluated as a Go template against resourceModifierContext before being parsed,
+	// so e.g. {{.TargetNamespace}} can appear inside it.
+	Value string `json:"value"`
+}
+
+// resourceModifierContext is the Go-template context every resourceModifierPatch.Value
+// is rendered against.
+type resourceModifierContext struct {
+	BackupName      string
+	TargetNamespace string
+	SourceNamespace string
+	Now             string
+}
+
+// compiledResourceModifiers is a resourceModifierRef's rules parsed and compiled once
+// at the start of performRestore, so restoreResource's per-resource hot path only
+// matches against already-compiled regexes instead of reparsing JSON per resource.
+type compiledResourceModifiers struct {
+	rules  []resourceModifierRule
+	names  []*regexp.Regexp // parallel to rules; nil entry means "match any name"
+	dryRun bool
+}
+
+// loadResourceModifiers reads and compiles restore.Spec.ResourceModifierRef's
+// ConfigMap, returning (nil, nil) when no ResourceModifierRef is configured.
+func (r *RestoreReconciler) loadResourceModifiers(ctx context.Context, restore *backupv1.Restore) (*compiledResourceModifiers, error) {
+	ref := restore.Spec.ResourceModifierRef
+	if ref == nil {
+		return nil, nil
+	}
+
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = restore.Namespace
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, cm); err != nil {
+		return nil, fmt.Errorf("failed to get resource modifier configmap %s/%s: %w", namespace, ref.Name, err)
+	}
+
+	raw, ok := cm.Data[resourceModifierConfigMapKey]
+	if !ok {
+		return nil, fmt.Errorf("resource modifier configmap %s/%s has no %q key", namespace, ref.Name, resourceModifierConfigMapKey)
+	}
+
+	var rules []resourceModifierRule
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse resource modifier rules in %s/%s: %w", namespace, ref.Name, err)
+	}
+
+	compiled := &compiledResourceModifiers{rules: rules, names: make([]*regexp.Regexp, len(rules)), dryRun: ref.DryRun}
+	for i, rule := range rules {
+		if rule.ResourceNameRegex == "" {
+			continue
+		}
+		re, err := regexp.Compile(rule.ResourceNameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("resource modifier rule %d: invalid resourceNameRegex %q: %w", i, rule.ResourceNameRegex, err)
+		}
+		compiled.names[i] = re
+	}
+
+	return compiled, nil
+}
+
+// applyResourceModifiers applies every matching rule's patches to resource in order,
+// returning the GroupResource of each rule that matched (whether or not DryRun
+// suppressed actually applying it).
+func (r *RestoreReconciler) applyResourceModifiers(ctx context.Context, modifiers *compiledResourceModifiers, restore *backupv1.Restore, resource *unstructured.Unstructured, targetNamespace, sourceNamespace string) ([]string, error) {
+	if modifiers == nil {
+		return nil, nil
+	}
+	log := logf.FromContext(ctx)
+
+	tmplCtx := resourceModifierContext{
+		BackupName:      restore.Spec.Source.BackupPath,
+		TargetNamespace: targetNamespace,
+		SourceNamespace: sourceNamespace,
+		Now:             time.Now().UTC().Format(time.RFC3339),
+	}
+
+	var matched []string
+	for i, rule := range modifiers.rules {
+		if rule.GroupResource != resourceGroupResource(resource) {
+			continue
+		}
+		if modifiers.names[i] != nil && !modifiers.names[i].MatchString(resource.GetName()) {
+			continue
+		}
+
+		conditionsMet := true
+		for _, condition := range rule.Conditions {
+			met, err := evaluateJSONPathCondition(condition, resource.Object)
+			if err != nil {
+				return matched, fmt.Errorf("resource modifier rule %d: %w", i, err)
+			}
+			if !met {
+				conditionsMet = false
+				break
+			}
+		}
+		if !conditionsMet {
+			continue
+		}
+
+		before, err := json.Marshal(resource.Object)
+		if err != nil {
+			return matched, fmt.Errorf("failed to marshal resource for modifier rule %d: %w", i, err)
+		}
+
+		after := before
+		for j, patch := range rule.Patches {
+			after, err = applyResourceModifierPatch(patch, after, tmplCtx)
+			if err != nil {
+				return matched, fmt.Errorf("resource modifier rule %d patch %d: %w", i, j, err)
+			}
+		}
+
+		matched = append(matched, rule.GroupResource)
+
+		if modifiers.dryRun {
+			log.Info("resource modifier dry-run: rule matched, patches not applied",
+				"rule", i, "groupResource", rule.GroupResource, "name", resource.GetName(),
+				"namespace", resource.GetNamespace(), "diff", string(after))
+			continue
+		}
+
+		var patched map[string]interface{}
+		if err := json.Unmarshal(after, &patched); err != nil {
+			return matched, fmt.Errorf("resource modifier rule %d: failed to parse patched resource: %w", i, err)
+		}
+		resource.Object = patched
+	}
+
+	return matched, nil
+}
+
+// applyResourceModifierPatch renders patch.Value as a Go template against tmplCtx,
+// then applies it to doc as a JSON Patch or JSON Merge Patch depending on patch.Type.
+func applyResourceModifierPatch(patch resourceModifierPatch, doc []byte, tmplCtx resourceModifierContext) ([]byte, error) {
+	tmpl, err := template.New("resourceModifierPatch").Parse(patch.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid patch template: %w", err)
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, tmplCtx); err != nil {
+		return nil, fmt.Errorf("failed to render patch template: %w", err)
+	}
+
+	switch patch.Type {
+	case "json":
+		jp, err := jsonpatch.DecodePatch(rendered.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("invalid json patch: %w", err)
+		}
+		result, err := jp.Apply(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply json patch: %w", err)
+		}
+		return result, nil
+	case "merge":
+		result, err := jsonpatch.MergePatch(doc, rendered.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply merge patch: %w", err)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unknown patch type %q", patch.Type)
+	}
+}
+
+// resourceGroupResource returns resource's GroupVersionKind in "group/Kind" form,
+// matching resourceModifierRule.GroupResource's convention (see its doc comment).
+func resourceGroupResource(resource *unstructured.Unstructured) string {
+	gvk := resource.GroupVersionKind()
+	return gvk.Group + "/" + gvk.Kind
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestEnsureTargetNamespacesRecordsCreatedOnes verifies a namespace that didn't exist
+// yet is both recorded on Status.CreatedNamespaces and returned as a "created"
+// RestoredResource, while one that already existed is neither.
+func TestEnsureTargetNamespacesRecordsCreatedOnes(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	existing := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "already-there"},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+	}
+	r := &RestoreReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()}
+	restore := &backupv1.Restore{}
+
+	restored, failed := r.ensureTargetNamespaces(context.Background(), restore, []string{"already-there", "brand-new"})
+	if len(failed) != 0 {
+		t.Fatalf("expected no failures, got %v", failed)
+	}
+	if len(restored) != 1 || restored[0].Name != "brand-new" || restored[0].Action != "created" {
+		t.Errorf("expected exactly one created RestoredResource for brand-new, got %v", restored)
+	}
+	if len(restore.Status.CreatedNamespaces) != 1 || restore.Status.CreatedNamespaces[0] != "brand-new" {
+		t.Errorf("expected Status.CreatedNamespaces = [brand-new], got %v", restore.Status.CreatedNamespaces)
+	}
+}
+
+// TestEnsureTargetNamespacesSkipsAlreadyKnownOnes verifies a namespace already listed
+// on Status.CreatedNamespaces (e.g. a resumed restore's earlier reconcile) is not
+// re-checked, even if it no longer exists on the cluster for some reason.
+func TestEnsureTargetNamespacesSkipsAlreadyKnownOnes(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	r := &RestoreReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+	restore := &backupv1.Restore{
+		Status: backupv1.RestoreStatus{CreatedNamespaces: []string{"already-known"}},
+	}
+
+	restored, failed := r.ensureTargetNamespaces(context.Background(), restore, []string{"already-known"})
+	if len(restored) != 0 || len(failed) != 0 {
+		t.Errorf("expected a previously-known namespace to be skipped entirely, got restored=%v failed=%v", restored, failed)
+	}
+}
+
+// TestEnsureTargetNamespacesRecordsTerminatingAsFailed verifies a Terminating
+// namespace is reported as a FailedResource rather than recorded as created.
+func TestEnsureTargetNamespacesRecordsTerminatingAsFailed(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	dying := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "dying-ns"},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+	}
+	r := &RestoreReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(dying).Build()}
+	restore := &backupv1.Restore{}
+
+	restored, failed := r.ensureTargetNamespaces(context.Background(), restore, []string{"dying-ns"})
+	if len(restored) != 0 {
+		t.Errorf("expected no restored namespaces, got %v", restored)
+	}
+	if len(failed) != 1 || failed[0].Name != "dying-ns" {
+		t.Errorf("expected dying-ns recorded as a failed resource, got %v", failed)
+	}
+	if len(restore.Status.CreatedNamespaces) != 0 {
+		t.Errorf("expected Status.CreatedNamespaces to stay empty, got %v", restore.Status.CreatedNamespaces)
+	}
+}
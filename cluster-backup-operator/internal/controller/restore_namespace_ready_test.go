@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestEnsureNamespaceExistsAndIsReadyCreatesAbsentNamespace verifies the absent case:
+// the namespace is created and reported as both created and ready.
+func TestEnsureNamespaceExistsAndIsReadyCreatesAbsentNamespace(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	r := &RestoreReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+
+	created, ready, err := r.EnsureNamespaceExistsAndIsReady(context.Background(), "new-ns")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created || !ready {
+		t.Errorf("EnsureNamespaceExistsAndIsReady() = (created=%v, ready=%v), want (true, true)", created, ready)
+	}
+
+	ns := &corev1.Namespace{}
+	if err := r.Get(context.Background(), client.ObjectKey{Name: "new-ns"}, ns); err != nil {
+		t.Errorf("expected namespace new-ns to have been created, got error: %v", err)
+	}
+}
+
+// TestEnsureNamespaceExistsAndIsReadyActiveNamespace verifies the Active case: an
+// existing, non-terminating namespace is reported ready without being recreated.
+func TestEnsureNamespaceExistsAndIsReadyActiveNamespace(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	existing := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "active-ns"},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+	}
+	r := &RestoreReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()}
+
+	created, ready, err := r.EnsureNamespaceExistsAndIsReady(context.Background(), "active-ns")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created || !ready {
+		t.Errorf("EnsureNamespaceExistsAndIsReady() = (created=%v, ready=%v), want (false, true)", created, ready)
+	}
+}
+
+// TestEnsureNamespaceExistsAndIsReadyTerminatingNamespace verifies the Terminating
+// case: the namespace is reported not ready, and left untouched rather than recreated
+// out from under the in-progress deletion.
+func TestEnsureNamespaceExistsAndIsReadyTerminatingNamespace(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	existing := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "dying-ns"},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+	}
+	r := &RestoreReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()}
+
+	created, ready, err := r.EnsureNamespaceExistsAndIsReady(context.Background(), "dying-ns")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created || ready {
+		t.Errorf("EnsureNamespaceExistsAndIsReady() = (created=%v, ready=%v), want (false, false)", created, ready)
+	}
+}
+
+// TestHandleWaitingForNamespacePhaseTimesOut verifies that once
+// RestoreOptions.NamespaceTerminationTimeout has elapsed since NamespaceWaitStartTime,
+// a still-terminating target namespace fails the restore instead of polling forever.
+func TestHandleWaitingForNamespacePhaseTimesOut(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	terminating := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "dying-ns"},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+	}
+	started := metav1.NewTime(time.Now().Add(-time.Hour))
+	restore := &backupv1.Restore{
+		ObjectMeta: metav1.ObjectMeta{Name: "timed-out-restore"},
+		Spec: backupv1.RestoreSpec{
+			Target:  backupv1.RestoreTarget{Namespaces: []string{"dying-ns"}},
+			Options: backupv1.RestoreOptions{NamespaceTerminationTimeout: metav1.Duration{Duration: time.Minute}},
+		},
+		Status: backupv1.RestoreStatus{
+			Phase:                  backupv1.RestorePhaseWaitingForNamespace,
+			NamespaceWaitStartTime: &started,
+		},
+	}
+
+	r := &RestoreReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(terminating, restore).WithStatusSubresource(restore).Build()}
+
+	if _, err := r.handleWaitingForNamespacePhase(context.Background(), restore); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if restore.Status.Phase != backupv1.RestorePhaseFailed {
+		t.Errorf("expected phase %q after timeout, got %q", backupv1.RestorePhaseFailed, restore.Status.Phase)
+	}
+}
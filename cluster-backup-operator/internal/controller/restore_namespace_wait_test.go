@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"sort"
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// TestTargetNamespaceCandidatesDedupesAcrossNamespacesAndMapping verifies the
+// candidate set merges Target.Namespaces and Target.NamespaceMapping's values
+// without duplicates.
+func TestTargetNamespaceCandidatesDedupesAcrossNamespacesAndMapping(t *testing.T) {
+	target := backupv1.RestoreTarget{
+		Namespaces: []string{"prod", "staging"},
+		NamespaceMapping: map[string]string{
+			"source-a": "staging",
+			"source-b": "dr",
+		},
+	}
+
+	got := targetNamespaceCandidates(target)
+	sort.Strings(got)
+
+	want := []string{"dr", "prod", "staging"}
+	if len(got) != len(want) {
+		t.Fatalf("targetNamespaceCandidates() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("targetNamespaceCandidates() = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+// TestTargetNamespaceCandidatesEmpty verifies an empty target yields no candidates.
+func TestTargetNamespaceCandidatesEmpty(t *testing.T) {
+	if got := targetNamespaceCandidates(backupv1.RestoreTarget{}); len(got) != 0 {
+		t.Errorf("targetNamespaceCandidates(empty) = %v, want empty", got)
+	}
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TestValidateNamespaceMappingRejectsConflictingDestinations verifies that two sources
+// mapping to the same destination is rejected as ambiguous.
+func TestValidateNamespaceMappingRejectsConflictingDestinations(t *testing.T) {
+	target := backupv1.RestoreTarget{
+		NamespaceMapping: map[string]string{
+			"source-a": "shared",
+			"source-b": "shared",
+		},
+	}
+
+	if err := validateNamespaceMapping(target); err == nil {
+		t.Errorf("expected an error for two sources mapping to the same destination, got nil")
+	}
+}
+
+// TestValidateNamespaceMappingRejectsDestinationExcludedByNamespaces verifies that a
+// mapping destination not present in Target.Namespaces is rejected.
+func TestValidateNamespaceMappingRejectsDestinationExcludedByNamespaces(t *testing.T) {
+	target := backupv1.RestoreTarget{
+		Namespaces: []string{"prod"},
+		NamespaceMapping: map[string]string{
+			"source-a": "dr",
+		},
+	}
+
+	if err := validateNamespaceMapping(target); err == nil {
+		t.Errorf("expected an error for a mapping destination excluded by target.namespaces, got nil")
+	}
+}
+
+// TestValidateNamespaceMappingAllowsConsistentMapping verifies that a non-conflicting
+// mapping whose destinations are all covered by Target.Namespaces passes.
+func TestValidateNamespaceMappingAllowsConsistentMapping(t *testing.T) {
+	target := backupv1.RestoreTarget{
+		Namespaces: []string{"prod", "dr"},
+		NamespaceMapping: map[string]string{
+			"source-a": "prod",
+			"source-b": "dr",
+		},
+	}
+
+	if err := validateNamespaceMapping(target); err != nil {
+		t.Errorf("expected no error for a consistent mapping, got %v", err)
+	}
+}
+
+// TestValidateNamespaceMappingEmptyIsNoop verifies an empty mapping never errors,
+// regardless of Target.Namespaces.
+func TestValidateNamespaceMappingEmptyIsNoop(t *testing.T) {
+	target := backupv1.RestoreTarget{Namespaces: []string{"prod"}}
+
+	if err := validateNamespaceMapping(target); err != nil {
+		t.Errorf("expected no error for an empty mapping, got %v", err)
+	}
+}
+
+// TestRemapSubjectNamespacesRewritesServiceAccountSubjects verifies that a
+// RoleBinding/ClusterRoleBinding's ServiceAccount subjects follow NamespaceMapping.
+func TestRemapSubjectNamespacesRewritesServiceAccountSubjects(t *testing.T) {
+	resource := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind": "RoleBinding",
+			"subjects": []interface{}{
+				map[string]interface{}{
+					"kind":      "ServiceAccount",
+					"name":      "deployer",
+					"namespace": "source-a",
+				},
+				map[string]interface{}{
+					"kind": "User",
+					"name": "alice",
+				},
+			},
+		},
+	}
+	target := backupv1.RestoreTarget{
+		NamespaceMapping: map[string]string{"source-a": "dest-a"},
+	}
+
+	r := &RestoreReconciler{}
+	r.remapSubjectNamespaces(resource, target)
+
+	subjects, _, _ := unstructured.NestedSlice(resource.Object, "subjects")
+	sa := subjects[0].(map[string]interface{})
+	if sa["namespace"] != "dest-a" {
+		t.Errorf("expected ServiceAccount subject namespace to be remapped to dest-a, got %v", sa["namespace"])
+	}
+	user := subjects[1].(map[string]interface{})
+	if _, hasNamespace := user["namespace"]; hasNamespace {
+		t.Errorf("expected non-ServiceAccount subject to be left untouched, got %v", user)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// defaultRestoreWaveOrder ranks resource types into the waves Velero-style restores
+// use so dependencies land before the resources that reference them:
+// CustomResourceDefinitions before any custom resource instance, Namespaces and
+// StorageClasses before anything that lives in a namespace or claims a volume,
+// PersistentVolumes before the PersistentVolumeClaims that bind them, the
+// config/identity primitives workloads mount or run as, RBAC and Services, and
+// finally the workloads themselves, with Ingresses last since they reference
+// Services. restoreWaveOrder lets RestoreOptions.RestoreOrder override this.
+var defaultRestoreWaveOrder = []string{
+	"customresourcedefinitions",
+	"namespaces",
+	"storageclasses",
+	"persistentvolumes",
+	"persistentvolumeclaims",
+	"secrets",
+	"configmaps",
+	"serviceaccounts",
+	"roles",
+	"rolebindings",
+	"clusterroles",
+	"clusterrolebindings",
+	"services",
+	"deployments",
+	"statefulsets",
+	"daemonsets",
+	"jobs",
+	"cronjobs",
+	"ingresses",
+}
+
+// restoreWaveOrder resolves the effective wave order for a restore: its
+// Spec.Options.RestoreOrder override, or defaultRestoreWaveOrder.
+func restoreWaveOrder(restore *backupv1.Restore) []string {
+	if len(restore.Spec.Options.RestoreOrder) > 0 {
+		return restore.Spec.Options.RestoreOrder
+	}
+	return defaultRestoreWaveOrder
+}
+
+// crdEstablishmentTimeout bounds how long waitForCRDEstablished polls a freshly
+// restored CustomResourceDefinition before giving up, so a wave of custom resource
+// instances that depend on it don't get created against a CRD the API server hasn't
+// finished registering yet.
+const crdEstablishmentTimeout = 1 * time.Minute
+
+// crdEstablishmentPollInterval is how often waitForCRDEstablished rechecks a
+// CustomResourceDefinition's Established condition.
+const crdEstablishmentPollInterval = 2 * time.Second
+
+var customResourceDefinitionGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+// waitForCRDEstablished polls a just-restored CustomResourceDefinition until its
+// Established condition is True, or timeout elapses. CustomResourceDefinitions are
+// restored as unstructured objects like everything else in this controller (see
+// restoreResource), so this reads status.conditions directly rather than pulling in
+// the apiextensions-apiserver client types for one field.
+func waitForCRDEstablished(ctx context.Context, cl client.Client, name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(customResourceDefinitionGVK)
+		if err := cl.Get(ctx, client.ObjectKey{Name: name}, crd); err != nil {
+			return fmt.Errorf("failed to check establishment state of CustomResourceDefinition %s: %w", name, err)
+		}
+
+		conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if condition["type"] == "Established" && condition["status"] == "True" {
+				return nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for CustomResourceDefinition %s to become Established", timeout, name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(crdEstablishmentPollInterval):
+		}
+	}
+}
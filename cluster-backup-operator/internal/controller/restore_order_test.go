@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// TestRestoreWaveOrderFallsBackToDefault verifies an unset RestoreOrder uses
+// defaultRestoreWaveOrder, and a set one overrides it.
+func TestRestoreWaveOrderFallsBackToDefault(t *testing.T) {
+	restore := &backupv1.Restore{}
+	got := restoreWaveOrder(restore)
+	if len(got) != len(defaultRestoreWaveOrder) || got[0] != defaultRestoreWaveOrder[0] {
+		t.Errorf("expected defaultRestoreWaveOrder when RestoreOrder is unset, got %v", got)
+	}
+
+	restore.Spec.Options.RestoreOrder = []string{"secrets", "deployments"}
+	got = restoreWaveOrder(restore)
+	if len(got) != 2 || got[0] != "secrets" || got[1] != "deployments" {
+		t.Errorf("expected RestoreOrder override to take effect, got %v", got)
+	}
+}
+
+// TestFilterAndOrderRestoreKeysRespectsCustomOrder verifies a caller-supplied order
+// (e.g. from Spec.Options.RestoreOrder) is honored over defaultRestoreWaveOrder.
+func TestFilterAndOrderRestoreKeysRespectsCustomOrder(t *testing.T) {
+	keys := []string{
+		"backups/cluster-backup/ts/namespaces/default/deployments/web.json",
+		"backups/cluster-backup/ts/namespaces/default/secrets/db-creds.json",
+	}
+
+	got := filterAndOrderRestoreKeys(keys, []string{"deployments", "secrets"})
+	if got[0] != keys[0] || got[1] != keys[1] {
+		t.Errorf("expected deployments before secrets under the custom order, got %v", got)
+	}
+}
+
+// crdTestScheme extends newHookTestScheme with the CustomResourceDefinition GVK,
+// which isn't part of client-go's scheme, registered generically as unstructured the
+// way fake.NewClientBuilder needs to recognize and store it.
+func crdTestScheme(t *testing.T) *runtime.Scheme {
+	scheme := newHookTestScheme(t)
+	scheme.AddKnownTypeWithName(customResourceDefinitionGVK, &unstructured.Unstructured{})
+	listGVK := customResourceDefinitionGVK.GroupVersion().WithKind("CustomResourceDefinitionList")
+	scheme.AddKnownTypeWithName(listGVK, &unstructured.UnstructuredList{})
+	return scheme
+}
+
+// TestWaitForCRDEstablishedReturnsOnceEstablishedConditionIsTrue verifies the poll
+// loop succeeds once it observes an Established=True condition on the CRD.
+func TestWaitForCRDEstablishedReturnsOnceEstablishedConditionIsTrue(t *testing.T) {
+	scheme := crdTestScheme(t)
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "NamesAccepted", "status": "True"},
+				map[string]interface{}{"type": "Established", "status": "True"},
+			},
+		},
+	}}
+	crd.SetGroupVersionKind(customResourceDefinitionGVK)
+	crd.SetName("widgets.example.com")
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(crd).Build()
+
+	if err := waitForCRDEstablished(context.Background(), cl, "widgets.example.com", time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestWaitForCRDEstablishedTimesOutWithoutEstablishedCondition verifies a CRD stuck
+// without an Established=True condition fails after timeout instead of hanging.
+func TestWaitForCRDEstablishedTimesOutWithoutEstablishedCondition(t *testing.T) {
+	scheme := crdTestScheme(t)
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	crd.SetGroupVersionKind(customResourceDefinitionGVK)
+	crd.SetName("widgets.example.com")
+	crd.SetCreationTimestamp(metav1.Now())
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(crd).Build()
+
+	if err := waitForCRDEstablished(context.Background(), cl, "widgets.example.com", 10*time.Millisecond); err == nil {
+		t.Error("expected a timeout error")
+	}
+}
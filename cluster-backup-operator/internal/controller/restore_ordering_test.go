@@ -0,0 +1,61 @@
+package controller
+
+import "testing"
+
+// TestFilterAndOrderRestoreKeysRespectsDependencies verifies PVCs, ConfigMaps/Secrets,
+// and Services are restored before the Deployments/Ingresses that depend on them.
+func TestFilterAndOrderRestoreKeysRespectsDependencies(t *testing.T) {
+	keys := []string{
+		"backups/cluster-backup/ts/namespaces/default/ingresses/web.json",
+		"backups/cluster-backup/ts/namespaces/default/deployments/web.json",
+		"backups/cluster-backup/ts/namespaces/default/services/web.json",
+		"backups/cluster-backup/ts/namespaces/default/secrets/db-creds.json",
+		"backups/cluster-backup/ts/namespaces/default/configmaps/app-config.json",
+		"backups/cluster-backup/ts/namespaces/default/persistentvolumeclaims/data.json",
+		"backups/cluster-backup/ts/namespaces/default/manifest.txt",
+	}
+
+	got := filterAndOrderRestoreKeys(keys, defaultRestoreWaveOrder)
+
+	if len(got) != 6 {
+		t.Fatalf("expected non-JSON objects to be dropped, got %d keys: %v", len(got), got)
+	}
+
+	indexOf := func(suffix string) int {
+		for i, k := range got {
+			if k == suffix {
+				return i
+			}
+		}
+		t.Fatalf("key %q missing from ordered result: %v", suffix, got)
+		return -1
+	}
+
+	pvc := indexOf("backups/cluster-backup/ts/namespaces/default/persistentvolumeclaims/data.json")
+	configMap := indexOf("backups/cluster-backup/ts/namespaces/default/configmaps/app-config.json")
+	service := indexOf("backups/cluster-backup/ts/namespaces/default/services/web.json")
+	deployment := indexOf("backups/cluster-backup/ts/namespaces/default/deployments/web.json")
+	ingress := indexOf("backups/cluster-backup/ts/namespaces/default/ingresses/web.json")
+
+	if !(pvc < configMap && configMap < service && service < deployment && deployment < ingress) {
+		t.Errorf("restore order violates dependency ordering: pvc=%d configmap=%d service=%d deployment=%d ingress=%d", pvc, configMap, service, deployment, ingress)
+	}
+}
+
+// TestIsResourceExcluded covers both whole-type and single-resource exclusion.
+func TestIsResourceExcluded(t *testing.T) {
+	excluded := []string{"secrets", "configmaps/app-config"}
+
+	if !isResourceExcluded("secrets", "db-creds", excluded) {
+		t.Error("expected whole resource type 'secrets' to be excluded")
+	}
+	if !isResourceExcluded("configmaps", "app-config", excluded) {
+		t.Error("expected 'configmaps/app-config' to be excluded")
+	}
+	if isResourceExcluded("configmaps", "other-config", excluded) {
+		t.Error("did not expect 'configmaps/other-config' to be excluded")
+	}
+	if isResourceExcluded("deployments", "web", excluded) {
+		t.Error("did not expect 'deployments/web' to be excluded")
+	}
+}
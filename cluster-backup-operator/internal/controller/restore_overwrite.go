@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// overwriteConflictBackoff bounds how long ConflictPolicyOverwrite's retry loop keeps
+// retrying a 409 Conflict: up to 5 attempts, starting at 100ms and doubling each time,
+// capped at 2s between attempts, so a resource under heavy concurrent writes gets
+// several chances to land without an overwrite restore retrying indefinitely.
+var overwriteConflictBackoff = wait.Backoff{
+	Duration: 100 * time.Millisecond,
+	Factor:   2.0,
+	Cap:      2 * time.Second,
+	Steps:    5,
+}
+
+// updateWithConflictRetry applies desired onto the live object named desired.GetName(),
+// retrying on 409 Conflict instead of failing the resource outright the first time
+// another writer touched it between restoreResource's existence check and this Update:
+// each retry re-Gets the live object, carries its current resourceVersion onto desired,
+// and tries the Update again, backing off per overwriteConflictBackoff between
+// attempts. Returns the number of conflicts encountered (0 on a first-try success) so
+// the caller can record it on RestoredResource.Reason.
+func (r *RestoreReconciler) updateWithConflictRetry(ctx context.Context, desired *unstructured.Unstructured) (int, error) {
+	conflicts := 0
+	err := retry.OnError(overwriteConflictBackoff, errors.IsConflict, func() error {
+		updateErr := r.Update(ctx, desired)
+		if !errors.IsConflict(updateErr) {
+			return updateErr
+		}
+
+		conflicts++
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(desired.GroupVersionKind())
+		key := client.ObjectKey{Name: desired.GetName(), Namespace: desired.GetNamespace()}
+		if getErr := r.Get(ctx, key, live); getErr != nil {
+			return fmt.Errorf("failed to refetch live object after conflict: %w", getErr)
+		}
+		desired.SetResourceVersion(live.GetResourceVersion())
+		return updateErr
+	})
+	return conflicts, err
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// conflictingClient wraps a client.Client and makes its first conflictsRemaining Update
+// calls fail with a 409 Conflict before delegating to the embedded client, simulating
+// another writer racing updateWithConflictRetry.
+type conflictingClient struct {
+	client.Client
+	conflictsRemaining int
+}
+
+func (c *conflictingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if c.conflictsRemaining > 0 {
+		c.conflictsRemaining--
+		return apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, obj.GetName(), fmt.Errorf("stale resourceVersion"))
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+// TestUpdateWithConflictRetrySucceedsAfterConflicts verifies the retry loop re-Gets the
+// live resourceVersion and keeps retrying until an Update that initially 409s lands.
+func TestUpdateWithConflictRetrySucceedsAfterConflicts(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"},
+		Data:       map[string]string{"k": "v1"},
+	}
+	base := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+	wrapped := &conflictingClient{Client: base, conflictsRemaining: 2}
+	r := &RestoreReconciler{Client: wrapped}
+
+	live := &corev1.ConfigMap{}
+	if err := base.Get(context.Background(), client.ObjectKey{Name: "demo", Namespace: "default"}, live); err != nil {
+		t.Fatalf("failed to read seeded object: %v", err)
+	}
+
+	desired := &unstructured.Unstructured{}
+	desired.SetAPIVersion("v1")
+	desired.SetKind("ConfigMap")
+	desired.SetName("demo")
+	desired.SetNamespace("default")
+	desired.SetResourceVersion(live.ResourceVersion)
+	_ = unstructured.SetNestedField(desired.Object, "v2", "data", "k")
+
+	conflicts, err := r.updateWithConflictRetry(context.Background(), desired)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conflicts != 2 {
+		t.Errorf("expected 2 recorded conflicts, got %d", conflicts)
+	}
+
+	updated := &corev1.ConfigMap{}
+	if err := base.Get(context.Background(), client.ObjectKey{Name: "demo", Namespace: "default"}, updated); err != nil {
+		t.Fatalf("failed to read updated object: %v", err)
+	}
+	if updated.Data["k"] != "v2" {
+		t.Errorf("expected data.k = v2 after the retried update landed, got %q", updated.Data["k"])
+	}
+}
+
+// TestUpdateWithConflictRetryPropagatesNonConflictError verifies an Update failure
+// that isn't a 409 is returned immediately, without retrying.
+func TestUpdateWithConflictRetryPropagatesNonConflictError(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	base := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r := &RestoreReconciler{Client: base}
+
+	desired := &unstructured.Unstructured{}
+	desired.SetAPIVersion("v1")
+	desired.SetKind("ConfigMap")
+	desired.SetName("does-not-exist")
+	desired.SetNamespace("default")
+
+	if _, err := r.updateWithConflictRetry(context.Background(), desired); err == nil {
+		t.Error("expected an error updating a nonexistent object")
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/plugin"
+)
+
+// loadItemActions builds the plugin.Registry performRestore uses for this
+// restore: the built-in ItemActions plus, when r.PluginDir is set, every
+// plugin manifest found there. Returns a registry seeded with just the
+// built-ins, without scanning r.PluginDir, when restore.Spec.ItemActions is
+// empty, since most restores configure no item actions at all.
+func (r *RestoreReconciler) loadItemActions(restore *backupv1.Restore) (*plugin.Registry, error) {
+	registry := plugin.NewRegistry()
+	if len(restore.Spec.ItemActions) == 0 {
+		return registry, nil
+	}
+
+	if r.PluginDir != "" {
+		if err := registry.LoadDir(r.PluginDir); err != nil {
+			return nil, fmt.Errorf("failed to load plugins from %s: %w", r.PluginDir, err)
+		}
+	}
+
+	return registry, nil
+}
+
+// applyItemActions runs every restore.Spec.ItemActions entry whose ItemAction.Applies
+// matches group/kind/namespace against raw, in spec order, configuring each with its
+// ItemActionRef.Config before calling Execute. Returns the possibly transformed bytes,
+// the names of the actions that touched the resource, and whether any action asked to
+// skip it; once an action returns Skip, the remaining actions are not run.
+func applyItemActions(registry *plugin.Registry, restore *backupv1.Restore, group, kind, namespace string, raw []byte) ([]byte, []string, bool, error) {
+	var applied []string
+
+	for _, ref := range restore.Spec.ItemActions {
+		action, ok := registry.Get(ref.Name)
+		if !ok {
+			return nil, applied, false, fmt.Errorf("item action %q is not registered (check spec.itemActions and the operator's --plugin-dir)", ref.Name)
+		}
+		if !action.Applies(group, kind, namespace) {
+			continue
+		}
+		if err := action.Configure(ref.Config); err != nil {
+			return nil, applied, false, fmt.Errorf("item action %q: failed to configure: %w", ref.Name, err)
+		}
+
+		result, err := action.Execute(raw)
+		if err != nil {
+			return nil, applied, false, fmt.Errorf("item action %q: %w", ref.Name, err)
+		}
+		applied = append(applied, ref.Name)
+
+		if result.Skip {
+			return nil, applied, true, nil
+		}
+		if len(result.Transformed) > 0 {
+			raw = result.Transformed
+		}
+	}
+
+	return raw, applied, false, nil
+}
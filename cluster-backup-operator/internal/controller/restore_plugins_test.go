@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/plugin"
+)
+
+// TestApplyItemActionsStripsClusterFields runs the built-in strip-cluster-fields
+// plugin through applyItemActions end to end.
+func TestApplyItemActionsStripsClusterFields(t *testing.T) {
+	restore := &backupv1.Restore{
+		Spec: backupv1.RestoreSpec{
+			ItemActions: []backupv1.ItemActionRef{{Name: "strip-cluster-fields"}},
+		},
+	}
+	raw := []byte(`{"metadata":{"name":"demo","resourceVersion":"123"}}`)
+
+	transformed, applied, skip, err := applyItemActions(plugin.NewRegistry(), restore, "", "ConfigMap", "default", raw)
+	if err != nil {
+		t.Fatalf("applyItemActions() returned error: %v", err)
+	}
+	if skip {
+		t.Fatal("expected skip to be false")
+	}
+	if len(applied) != 1 || applied[0] != "strip-cluster-fields" {
+		t.Errorf("expected applied = [strip-cluster-fields], got %v", applied)
+	}
+	if string(transformed) == string(raw) {
+		t.Error("expected transformed output to differ from input")
+	}
+}
+
+// TestApplyItemActionsUnknownPlugin confirms an ItemActionRef naming a plugin that
+// isn't registered surfaces an error instead of silently skipping it.
+func TestApplyItemActionsUnknownPlugin(t *testing.T) {
+	restore := &backupv1.Restore{
+		Spec: backupv1.RestoreSpec{
+			ItemActions: []backupv1.ItemActionRef{{Name: "does-not-exist"}},
+		},
+	}
+
+	if _, _, _, err := applyItemActions(plugin.NewRegistry(), restore, "", "ConfigMap", "default", []byte(`{}`)); err == nil {
+		t.Error("expected an error for an unregistered item action")
+	}
+}
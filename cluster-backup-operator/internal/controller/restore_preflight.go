@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// conditionTypeRestorePreflight is the Restore status condition reporting the result
+// of runPreflightCheck: whether the target storage has room and (when the MinIO admin
+// API answered) quorum for this restore, before any resource is written.
+const conditionTypeRestorePreflight = "RestorePreflight"
+
+// runPreflightCheck sums the size of every backup object restore is about to read and
+// checks it against the target storage's free capacity (and disk quorum, when
+// available) via storage.RunPreflight, recording the outcome as a RestorePreflight
+// condition on restore.Status so a restore that would overrun a degraded cluster fails
+// fast with a clear reason rather than partially restoring into it.
+func (r *RestoreReconciler) runPreflightCheck(ctx context.Context, restore *backupv1.Restore, provider storage.Provider, keys []string) error {
+	bucketName := restore.Spec.Source.StorageLocation.Bucket
+
+	var requiredBytes int64
+	for _, key := range keys {
+		info, err := provider.StatObject(ctx, bucketName, key)
+		if err != nil {
+			return fmt.Errorf("failed to stat backup object %s for preflight sizing: %w", key, err)
+		}
+		requiredBytes += info.Size
+	}
+
+	result, err := storage.RunPreflight(ctx, r.Client, r.AllowedCredentialsNamespaces, restore.Namespace, restore.Spec.Source.StorageLocation, requiredBytes)
+	if err != nil {
+		apimeta.SetStatusCondition(&restore.Status.Conditions, metav1.Condition{
+			Type:    conditionTypeRestorePreflight,
+			Status:  metav1.ConditionFalse,
+			Reason:  "PreflightFailed",
+			Message: err.Error(),
+		})
+		return err
+	}
+
+	reason := "FallbackChecked"
+	if result.Source == "admin" {
+		reason = "AdminChecked"
+	}
+	apimeta.SetStatusCondition(&restore.Status.Conditions, metav1.Condition{
+		Type:   conditionTypeRestorePreflight,
+		Status: metav1.ConditionTrue,
+		Reason: reason,
+		Message: fmt.Sprintf(
+			"%d bytes free, %d/%d disks online (quorum %d), %d bytes required",
+			result.FreeBytes, result.OnlineDisks, result.OnlineDisks+result.OfflineDisks, result.Quorum, requiredBytes,
+		),
+	})
+	return nil
+}
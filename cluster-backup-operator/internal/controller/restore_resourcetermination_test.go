@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestWaitForResourceTerminationReturnsImmediatelyWhenAlreadyGone verifies a resource
+// that doesn't exist at all is treated the same as one whose deletion already finished.
+func TestWaitForResourceTerminationReturnsImmediatelyWhenAlreadyGone(t *testing.T) {
+	scheme := newHookTestScheme(t)
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "PersistentVolumeClaim"}
+	if err := waitForResourceTermination(context.Background(), cl, gvk, "default", "no-such-pvc", time.Minute); err != nil {
+		t.Errorf("expected no error for an already-gone resource, got %v", err)
+	}
+}
+
+// TestWaitForResourceTerminationTimesOutIfStillPresent verifies a resource that never
+// disappears within the timeout surfaces a clear error instead of blocking forever.
+func TestWaitForResourceTerminationTimesOutIfStillPresent(t *testing.T) {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-data", Namespace: "default"},
+	}
+	scheme := newHookTestScheme(t)
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pvc).Build()
+
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "PersistentVolumeClaim"}
+	err := waitForResourceTermination(context.Background(), cl, gvk, "default", "app-data", 10*time.Millisecond)
+	if err == nil {
+		t.Fatal("expected a timeout error for a resource that never disappears")
+	}
+	if !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout error, got: %v", err)
+	}
+}
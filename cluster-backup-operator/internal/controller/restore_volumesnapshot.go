@@ -0,0 +1,211 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+)
+
+// volumeSnapshotAPIGroup is the API group CSI VolumeSnapshot/VolumeSnapshotContent
+// objects belong to, as referenced from a PVC's spec.dataSource.apiGroup.
+const volumeSnapshotAPIGroup = "snapshot.storage.k8s.io"
+
+// volumeRestoreEnabled reports whether restore opted into recreating PVC data from
+// backed-up CSI VolumeSnapshots via RestoreSpec.VolumeRestore.RestorePVs.
+func volumeRestoreEnabled(restore *backupv1.Restore) bool {
+	vr := restore.Spec.VolumeRestore
+	return vr != nil && vr.RestorePVs != nil && *vr.RestorePVs
+}
+
+// restoreVolumeSnapshotForPVC looks for a VolumeSnapshot that BackupReconciler.
+// snapshotPersistentVolumeClaim uploaded for pvc, and if one is found, recreates its
+// VolumeSnapshotContent and VolumeSnapshot in the target cluster and points pvc's
+// spec.dataSource at the new VolumeSnapshot. pvc is mutated in place; it is not yet
+// created in the target cluster when this runs. Returns (nil, nil, nil) when the
+// backup has no matching VolumeSnapshot (e.g. SnapshotVolumes wasn't enabled, or the
+// snapshot CRDs weren't installed at backup time), so PVCs restore as plain manifests
+// same as before this feature existed.
+func (r *RestoreReconciler) restoreVolumeSnapshotForPVC(ctx context.Context, provider storage.Provider, bucket, backupPath, sourceNamespace string, restore *backupv1.Restore, pvc *unstructured.Unstructured) (*backupv1.RestoredResource, []backupv1.FailedResource, error) {
+	log := logf.FromContext(ctx)
+
+	vs, vsc, err := locateVolumeSnapshotForPVC(ctx, provider, bucket, backupPath, sourceNamespace, pvc.GetName())
+	if err != nil {
+		return nil, nil, err
+	}
+	if vs == nil {
+		return nil, nil, nil
+	}
+
+	if vsc == nil || vsc.Status == nil || vsc.Status.SnapshotHandle == nil || *vsc.Status.SnapshotHandle == "" {
+		return nil, []backupv1.FailedResource{{
+			Kind:      "VolumeSnapshot",
+			Name:      vs.Name,
+			Namespace: pvc.GetNamespace(),
+			Error:     "backed-up VolumeSnapshotContent has no snapshot handle recorded; restoring PVC without a dataSource",
+		}}, nil
+	}
+
+	targetNamespace := pvc.GetNamespace()
+	name := fmt.Sprintf("%s-%s", pvc.GetName(), restore.Name)
+
+	snapshotClass := ""
+	if vs.Spec.VolumeSnapshotClassName != nil {
+		var mapping map[string]string
+		if restore.Spec.VolumeRestore != nil {
+			mapping = restore.Spec.VolumeRestore.VolumeSnapshotClassMapping
+		}
+		snapshotClass = resolveVolumeSnapshotClass(mapping, vs.Spec.VolumeSnapshotClassName)
+		if snapshotClass == "" {
+			snapshotClass = *vs.Spec.VolumeSnapshotClassName
+		}
+	}
+
+	newVSC := &snapshotv1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: snapshotv1.VolumeSnapshotContentSpec{
+			DeletionPolicy: snapshotRetainDeletionPolicy,
+			Driver:         vsc.Spec.Driver,
+			Source: snapshotv1.VolumeSnapshotContentSource{
+				SnapshotHandle: vsc.Status.SnapshotHandle,
+			},
+			VolumeSnapshotRef: corev1.ObjectReference{
+				Name:      name,
+				Namespace: targetNamespace,
+			},
+		},
+	}
+	if snapshotClass != "" {
+		newVSC.Spec.VolumeSnapshotClassName = &snapshotClass
+	}
+
+	if err := r.Create(ctx, newVSC); err != nil {
+		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			log.Info("snapshot.storage.k8s.io CRDs not installed, restoring pvc without a dataSource",
+				"persistentvolumeclaim", pvc.GetName(), "namespace", targetNamespace)
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to create volumesnapshotcontent %s: %w", name, err)
+	}
+
+	newVS := &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: targetNamespace,
+		},
+		Spec: snapshotv1.VolumeSnapshotSpec{
+			Source: snapshotv1.VolumeSnapshotSource{
+				VolumeSnapshotContentName: &name,
+			},
+		},
+	}
+	if snapshotClass != "" {
+		newVS.Spec.VolumeSnapshotClassName = &snapshotClass
+	}
+
+	if err := r.Create(ctx, newVS); err != nil {
+		return nil, nil, fmt.Errorf("failed to create volumesnapshot %s: %w", name, err)
+	}
+
+	if err := unstructured.SetNestedMap(pvc.Object, map[string]interface{}{
+		"apiGroup": volumeSnapshotAPIGroup,
+		"kind":     "VolumeSnapshot",
+		"name":     name,
+	}, "spec", "dataSource"); err != nil {
+		return nil, nil, fmt.Errorf("failed to set dataSource on pvc %s: %w", pvc.GetName(), err)
+	}
+
+	return &backupv1.RestoredResource{
+		APIVersion: volumeSnapshotAPIGroup + "/v1",
+		Kind:       "VolumeSnapshot",
+		Name:       name,
+		Namespace:  targetNamespace,
+		Action:     "snapshot-restored",
+	}, nil, nil
+}
+
+// locateVolumeSnapshotForPVC scans "<backupPath>/namespaces/<sourceNamespace>/volumesnapshots/"
+// for the VolumeSnapshot BackupReconciler.snapshotPersistentVolumeClaim uploaded for
+// pvcName, returning it and its bound VolumeSnapshotContent. Returns (nil, nil, nil) if
+// none is found rather than an error, since most PVCs in most backups have no snapshot.
+func locateVolumeSnapshotForPVC(ctx context.Context, provider storage.Provider, bucket, backupPath, sourceNamespace, pvcName string) (*snapshotv1.VolumeSnapshot, *snapshotv1.VolumeSnapshotContent, error) {
+	prefix := fmt.Sprintf("%s/namespaces/%s/volumesnapshots/", backupPath, sourceNamespace)
+	keys, err := provider.ListObjects(ctx, bucket, prefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list volumesnapshots under %s: %w", prefix, err)
+	}
+
+	for _, key := range keys {
+		if !strings.HasSuffix(key, ".json") || strings.HasSuffix(key, "-content.json") {
+			continue
+		}
+
+		var vs snapshotv1.VolumeSnapshot
+		if err := downloadAndUnmarshal(ctx, provider, bucket, key, &vs); err != nil {
+			return nil, nil, fmt.Errorf("failed to read volumesnapshot %s: %w", key, err)
+		}
+		if vs.Spec.Source.PersistentVolumeClaimName == nil || *vs.Spec.Source.PersistentVolumeClaimName != pvcName {
+			continue
+		}
+
+		contentKey := strings.TrimSuffix(key, ".json") + "-content.json"
+		var vsc snapshotv1.VolumeSnapshotContent
+		if err := downloadAndUnmarshal(ctx, provider, bucket, contentKey, &vsc); err != nil {
+			return nil, nil, fmt.Errorf("failed to read volumesnapshotcontent for %s: %w", key, err)
+		}
+		return &vs, &vsc, nil
+	}
+
+	return nil, nil, nil
+}
+
+// downloadAndUnmarshal downloads bucket/key from provider and JSON-decodes it into out.
+func downloadAndUnmarshal(ctx context.Context, provider storage.Provider, bucket, key string, out interface{}) error {
+	object, err := provider.GetObject(ctx, bucket, key)
+	if err != nil {
+		return fmt.Errorf("failed to download object: %w", err)
+	}
+	defer object.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := io.Copy(buf, object); err != nil {
+		return fmt.Errorf("failed to read object data: %w", err)
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), out); err != nil {
+		return fmt.Errorf("failed to parse object JSON: %w", err)
+	}
+	return nil
+}
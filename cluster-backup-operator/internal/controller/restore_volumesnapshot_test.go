@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"testing"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+// TestVolumeRestoreEnabled covers the unset, explicitly-false, and explicitly-true cases.
+func TestVolumeRestoreEnabled(t *testing.T) {
+	cases := []struct {
+		name    string
+		restore *backupv1.Restore
+		want    bool
+	}{
+		{"nil VolumeRestore", &backupv1.Restore{}, false},
+		{"RestorePVs unset", &backupv1.Restore{Spec: backupv1.RestoreSpec{VolumeRestore: &backupv1.VolumeRestoreOptions{}}}, false},
+		{"RestorePVs false", &backupv1.Restore{Spec: backupv1.RestoreSpec{VolumeRestore: &backupv1.VolumeRestoreOptions{RestorePVs: boolPtr(false)}}}, false},
+		{"RestorePVs true", &backupv1.Restore{Spec: backupv1.RestoreSpec{VolumeRestore: &backupv1.VolumeRestoreOptions{RestorePVs: boolPtr(true)}}}, true},
+	}
+
+	for _, c := range cases {
+		if got := volumeRestoreEnabled(c.restore); got != c.want {
+			t.Errorf("%s: volumeRestoreEnabled() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+	"github.com/rfashwall/cnd-workshop/pkg/volumesnapshotter"
+)
+
+// restoreVolumesEnabled reports whether restore opted into provisioning a fresh volume
+// for each backed-up PersistentVolume from RestoreSpec.RestoreVolumes: true always
+// provisions (validateRestoreConfig already rejected this combination if
+// r.VolumeSnapshotter is nil), nil provisions only when a snapshotter is configured
+// ("auto"), and false always skips.
+func (r *RestoreReconciler) restoreVolumesEnabled(restore *backupv1.Restore) bool {
+	if r.VolumeSnapshotter == nil {
+		return false
+	}
+	return restore.Spec.RestoreVolumes == nil || *restore.Spec.RestoreVolumes
+}
+
+// locateSnapshotRecordForPV downloads the SnapshotRecord backed up for pvName from
+// "<backupPath>/<volumesnapshotter.RecordsPrefix>/<pvName>.json", the layout
+// volumesnapshotter.StorageSnapshotter reads and writes. Returns (nil, nil) if none
+// exists, since most backed-up PersistentVolumes have no snapshot record.
+func locateSnapshotRecordForPV(ctx context.Context, provider storage.Provider, bucket, backupPath, pvName string) (*volumesnapshotter.SnapshotRecord, error) {
+	key := fmt.Sprintf("%s/%s/%s.json", backupPath, volumesnapshotter.RecordsPrefix, pvName)
+
+	if _, err := provider.StatObject(ctx, bucket, key); err != nil {
+		return nil, nil
+	}
+
+	var record volumesnapshotter.SnapshotRecord
+	if err := downloadAndUnmarshal(ctx, provider, bucket, key, &record); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot record for pv %s: %w", pvName, err)
+	}
+	return &record, nil
+}
+
+// restoreVolumeFromSnapshot looks up the SnapshotRecord backed up for pv's name,
+// provisions a replacement volume via r.VolumeSnapshotter, and rewrites pv's CSI volume
+// handle to point at it. pv is mutated in place; it is not yet created in the target
+// cluster when this runs. Returns (nil, nil) when no snapshot record exists for pv, so
+// it restores as a plain manifest same as before this feature existed.
+func (r *RestoreReconciler) restoreVolumeFromSnapshot(ctx context.Context, provider storage.Provider, bucket, backupPath string, pv *unstructured.Unstructured) (*backupv1.RestoredResource, error) {
+	record, err := locateSnapshotRecordForPV(ctx, provider, bucket, backupPath, pv.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, nil
+	}
+
+	volumeID, err := r.VolumeSnapshotter.CreateVolumeFromSnapshot(record.SnapshotID, record.VolumeType, record.AvailabilityZone, record.IOPS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to provision volume from snapshot %s for pv %s: %w", record.SnapshotID, pv.GetName(), err)
+	}
+
+	var typedPV corev1.PersistentVolume
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(pv.Object, &typedPV); err != nil {
+		return nil, fmt.Errorf("failed to convert pv %s for volume rewrite: %w", pv.GetName(), err)
+	}
+	if err := r.VolumeSnapshotter.SetVolumeID(&typedPV, volumeID); err != nil {
+		return nil, fmt.Errorf("failed to rewrite volume handle on pv %s: %w", pv.GetName(), err)
+	}
+	updated, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&typedPV)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert pv %s back after volume rewrite: %w", pv.GetName(), err)
+	}
+	pv.Object = updated
+
+	return &backupv1.RestoredResource{
+		APIVersion: "v1",
+		Kind:       "PersistentVolume",
+		Name:       pv.GetName(),
+		Action:     "volume-provisioned",
+	}, nil
+}
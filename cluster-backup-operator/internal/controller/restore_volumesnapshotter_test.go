@@ -0,0 +1,175 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/volumesnapshotter"
+)
+
+// fakeSnapshotter is a Snapshotter test double that hands out a fixed volume ID and
+// records its calls, so tests can assert restoreVolumeFromSnapshot drove it correctly
+// without depending on volumesnapshotter.StorageSnapshotter.
+type fakeSnapshotter struct {
+	volumeID        string
+	createCallCount int
+}
+
+func (f *fakeSnapshotter) CreateVolumeFromSnapshot(snapshotID, volumeType, az string, iops *int64) (string, error) {
+	f.createCallCount++
+	return f.volumeID, nil
+}
+
+func (f *fakeSnapshotter) SetVolumeID(pv *corev1.PersistentVolume, volumeID string) error {
+	if pv.Spec.CSI == nil {
+		return fmt.Errorf("persistentvolume %s has no CSI volume source", pv.Name)
+	}
+	pv.Spec.CSI.VolumeHandle = volumeID
+	return nil
+}
+
+func (f *fakeSnapshotter) GetVolumeID(pv *corev1.PersistentVolume) (string, error) {
+	if pv.Spec.CSI == nil {
+		return "", fmt.Errorf("persistentvolume %s has no CSI volume source", pv.Name)
+	}
+	return pv.Spec.CSI.VolumeHandle, nil
+}
+
+// TestRestoreVolumesEnabled covers the nil-snapshotter, unset/true/false RestoreVolumes
+// combinations, mirroring TestVolumeRestoreEnabled for the CSI VolumeSnapshot path.
+func TestRestoreVolumesEnabled(t *testing.T) {
+	cases := []struct {
+		name        string
+		snapshotter volumesnapshotter.Snapshotter
+		restore     *backupv1.Restore
+		want        bool
+	}{
+		{"no snapshotter configured, unset", nil, &backupv1.Restore{}, false},
+		{"no snapshotter configured, forced true", nil, &backupv1.Restore{Spec: backupv1.RestoreSpec{RestoreVolumes: boolPtr(true)}}, false},
+		{"snapshotter configured, unset (auto)", &fakeSnapshotter{}, &backupv1.Restore{}, true},
+		{"snapshotter configured, forced false", &fakeSnapshotter{}, &backupv1.Restore{Spec: backupv1.RestoreSpec{RestoreVolumes: boolPtr(false)}}, false},
+		{"snapshotter configured, forced true", &fakeSnapshotter{}, &backupv1.Restore{Spec: backupv1.RestoreSpec{RestoreVolumes: boolPtr(true)}}, true},
+	}
+
+	for _, c := range cases {
+		r := &RestoreReconciler{VolumeSnapshotter: c.snapshotter}
+		if got := r.restoreVolumesEnabled(c.restore); got != c.want {
+			t.Errorf("%s: restoreVolumesEnabled() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+// TestRestoreVolumeFromSnapshotRewritesVolumeHandle exercises the fake-snapshotter
+// rewrite path end to end: a SnapshotRecord backed up for a PV drives
+// CreateVolumeFromSnapshot, and the result is written back onto the PV's CSI volume
+// handle.
+func TestRestoreVolumeFromSnapshotRewritesVolumeHandle(t *testing.T) {
+	provider := newLocalProviderForTest(t)
+	ctx := context.Background()
+	bucket, backupPath := "test-bucket", "backups/2026-01-01"
+	if err := provider.EnsureBucket(ctx, bucket); err != nil {
+		t.Fatalf("failed to ensure bucket: %v", err)
+	}
+
+	record := volumesnapshotter.SnapshotRecord{PVName: "pv-data-0", SnapshotID: "snap-abc"}
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Fatalf("failed to marshal snapshot record: %v", err)
+	}
+	key := fmt.Sprintf("%s/%s/%s.json", backupPath, volumesnapshotter.RecordsPrefix, record.PVName)
+	if err := provider.PutObject(ctx, bucket, key, bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatalf("failed to write snapshot record: %v", err)
+	}
+
+	snapshotter := &fakeSnapshotter{volumeID: "vol-restored-abc"}
+	r := &RestoreReconciler{VolumeSnapshotter: snapshotter}
+
+	pv := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "PersistentVolume",
+			"metadata":   map[string]interface{}{"name": "pv-data-0"},
+			"spec": map[string]interface{}{
+				"csi": map[string]interface{}{
+					"driver":       "ebs.csi.aws.com",
+					"volumeHandle": "vol-old",
+				},
+			},
+		},
+	}
+
+	restored, err := r.restoreVolumeFromSnapshot(ctx, provider, bucket, backupPath, pv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if restored == nil {
+		t.Fatalf("expected a non-nil RestoredResource")
+	}
+	if snapshotter.createCallCount != 1 {
+		t.Errorf("expected CreateVolumeFromSnapshot to be called once, got %d", snapshotter.createCallCount)
+	}
+
+	handle, found, err := unstructured.NestedString(pv.Object, "spec", "csi", "volumeHandle")
+	if err != nil || !found {
+		t.Fatalf("failed to read rewritten volume handle: found=%v err=%v", found, err)
+	}
+	if handle != "vol-restored-abc" {
+		t.Errorf("spec.csi.volumeHandle = %q, want %q", handle, "vol-restored-abc")
+	}
+}
+
+// TestRestoreVolumeFromSnapshotNoRecordIsNoop verifies a PV with no backed-up
+// SnapshotRecord restores untouched rather than erroring.
+func TestRestoreVolumeFromSnapshotNoRecordIsNoop(t *testing.T) {
+	provider := newLocalProviderForTest(t)
+	ctx := context.Background()
+	bucket, backupPath := "test-bucket", "backups/2026-01-01"
+	if err := provider.EnsureBucket(ctx, bucket); err != nil {
+		t.Fatalf("failed to ensure bucket: %v", err)
+	}
+
+	snapshotter := &fakeSnapshotter{volumeID: "vol-restored-abc"}
+	r := &RestoreReconciler{VolumeSnapshotter: snapshotter}
+
+	pv := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "PersistentVolume",
+			"metadata":   map[string]interface{}{"name": "pv-no-record"},
+		},
+	}
+
+	restored, err := r.restoreVolumeFromSnapshot(ctx, provider, bucket, backupPath, pv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if restored != nil {
+		t.Errorf("expected a nil RestoredResource for a pv with no snapshot record, got %+v", restored)
+	}
+	if snapshotter.createCallCount != 0 {
+		t.Errorf("expected CreateVolumeFromSnapshot not to be called, got %d calls", snapshotter.createCallCount)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+)
+
+// defaultRotationInterval is used when StorageCredentialRotationSpec.RotationInterval
+// is unset.
+const defaultRotationInterval = 24 * time.Hour
+
+// conditionTypeRotationSucceeded is the Conditions[].Type RotateMinioCredentials'
+// outcome is recorded under.
+const conditionTypeRotationSucceeded = "RotationSucceeded"
+
+// StorageCredentialRotationReconciler reconciles a StorageCredentialRotation object
+type StorageCredentialRotationReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// AllowedCredentialsNamespaces restricts which namespaces SecretRef may point
+	// Secrets into, the same allow-list every other reconciler enforces for
+	// CredentialsRef. Empty means no restriction.
+	AllowedCredentialsNamespaces []string
+
+	// ProviderCache, when set, is purged after every successful (non-dry-run)
+	// rotation so cached Providers built against the old credential are never
+	// reused. Nil disables this; stale cached Providers still self-heal once their
+	// owning Backup/Restore's CacheKey picks up the credentials Secret's new
+	// ResourceVersion (see storage.CacheKey).
+	ProviderCache *storage.ProviderCache
+}
+
+// +kubebuilder:rbac:groups=backup.cnd.dk,resources=storagecredentialrotations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=backup.cnd.dk,resources=storagecredentialrotations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;update;patch
+
+// Reconcile rotates the MinIO credential referenced by a StorageCredentialRotation's
+// SecretRef once RotationInterval has elapsed since the last attempt, requeuing for
+// the next firing either way.
+func (r *StorageCredentialRotationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	rotation := &backupv1.StorageCredentialRotation{}
+	if err := r.Get(ctx, req.NamespacedName, rotation); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			log.Info("StorageCredentialRotation resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get StorageCredentialRotation")
+		return ctrl.Result{}, err
+	}
+
+	interval := rotation.Spec.RotationInterval.Duration
+	if interval <= 0 {
+		interval = defaultRotationInterval
+	}
+
+	if rotation.Status.NextRotation != nil && time.Now().Before(rotation.Status.NextRotation.Time) {
+		return ctrl.Result{RequeueAfter: time.Until(rotation.Status.NextRotation.Time)}, nil
+	}
+
+	result, rotateErr := storage.RotateMinioCredentials(ctx, r.Client, r.AllowedCredentialsNamespaces, rotation.Namespace, rotation.Spec.AdminEndpoint, rotation.Spec.Secure, rotation.Spec.SecretRef, rotation.Spec.Bucket, rotation.Spec.DryRun)
+
+	now := metav1.Now()
+	nextRotation := metav1.NewTime(now.Add(interval))
+	rotation.Status.NextRotation = &nextRotation
+
+	if rotateErr != nil {
+		log.Error(rotateErr, "Failed to rotate storage credential")
+		rotation.Status.Phase = backupv1.StorageCredentialRotationPhaseFailed
+		rotation.Status.Message = rotateErr.Error()
+		apimeta.SetStatusCondition(&rotation.Status.Conditions, metav1.Condition{
+			Type:    conditionTypeRotationSucceeded,
+			Status:  metav1.ConditionFalse,
+			Reason:  "RotationFailed",
+			Message: rotateErr.Error(),
+		})
+	} else {
+		rotation.Status.Phase = backupv1.StorageCredentialRotationPhaseCompleted
+		if result.DryRun {
+			rotation.Status.Message = "Dry run: generated a candidate credential without applying it"
+			apimeta.SetStatusCondition(&rotation.Status.Conditions, metav1.Condition{
+				Type:    conditionTypeRotationSucceeded,
+				Status:  metav1.ConditionTrue,
+				Reason:  "DryRun",
+				Message: rotation.Status.Message,
+			})
+		} else {
+			rotation.Status.LastRotated = &now
+			rotation.Status.Message = "Rotated storage credential"
+			apimeta.SetStatusCondition(&rotation.Status.Conditions, metav1.Condition{
+				Type:    conditionTypeRotationSucceeded,
+				Status:  metav1.ConditionTrue,
+				Reason:  "Rotated",
+				Message: rotation.Status.Message,
+			})
+			if r.ProviderCache != nil {
+				r.ProviderCache.Purge()
+			}
+		}
+	}
+
+	if err := r.Status().Update(ctx, rotation); err != nil {
+		log.Error(err, "Failed to update StorageCredentialRotation status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StorageCredentialRotationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&backupv1.StorageCredentialRotation{}).
+		Named("storagecredentialrotation").
+		Complete(r)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package filter compiles a BackupSource/RestoreTarget-style include/exclude policy
+// (namespaces, resource types, and label selectors) into a reusable Predicate, so
+// backup and restore can apply identical matching semantics without each controller
+// re-implementing glob matching and OR-vs-AND label selector precedence.
+package filter
+
+import (
+	"fmt"
+	"path"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Policy describes which namespaces, resource types, and labels a Predicate should
+// allow. Exclusion always wins over inclusion; an empty Included* list (or a literal
+// "*" entry) means "all".
+type Policy struct {
+	// IncludedNamespaces restricts matches to namespaces matching one of these
+	// patterns ("*" and simple glob patterns like "dev-*" are supported). Empty
+	// means all namespaces are candidates.
+	IncludedNamespaces []string
+
+	// ExcludedNamespaces removes namespaces matching one of these patterns
+	// regardless of IncludedNamespaces.
+	ExcludedNamespaces []string
+
+	// IncludedResources restricts matches to resource types matching one of these
+	// patterns. Empty means all resource types are candidates.
+	IncludedResources []string
+
+	// ExcludedResources removes individual resources regardless of
+	// IncludedResources. Each entry is either a bare resource type ("secrets",
+	// matching every secret) or "type/name" ("secrets/db-creds", matching a single
+	// named resource); both support glob patterns.
+	ExcludedResources []string
+
+	// LabelSelector must match an object's labels for it to pass, unless
+	// OrLabelSelectors is also set. Nil matches everything.
+	LabelSelector *metav1.LabelSelector
+
+	// OrLabelSelectors, if non-empty, takes precedence over LabelSelector: an
+	// object passes if it matches any one of these selectors.
+	OrLabelSelectors []metav1.LabelSelector
+}
+
+// Predicate is a Policy compiled into a form that can be evaluated per-object without
+// re-parsing label selectors on every call.
+type Predicate struct {
+	includedNamespaces []string
+	excludedNamespaces []string
+	includedResources  []string
+	excludedResources  []string
+
+	labelSelector    labels.Selector
+	orLabelSelectors []labels.Selector
+}
+
+// Compile validates policy's label selectors up front (so a typo fails immediately
+// instead of silently matching nothing) and rejects a policy where the same resource
+// type pattern appears in both IncludedResources and ExcludedResources, since that
+// combination can never match anything.
+func Compile(policy Policy) (*Predicate, error) {
+	for _, included := range policy.IncludedResources {
+		for _, excluded := range policy.ExcludedResources {
+			if included == excluded {
+				return nil, fmt.Errorf("resource %q is listed in both includedResources and excludedResources", included)
+			}
+		}
+	}
+
+	p := &Predicate{
+		includedNamespaces: policy.IncludedNamespaces,
+		excludedNamespaces: policy.ExcludedNamespaces,
+		includedResources:  policy.IncludedResources,
+		excludedResources:  policy.ExcludedResources,
+	}
+
+	if policy.LabelSelector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(policy.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid labelSelector: %w", err)
+		}
+		p.labelSelector = sel
+	}
+
+	for i := range policy.OrLabelSelectors {
+		sel, err := metav1.LabelSelectorAsSelector(&policy.OrLabelSelectors[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid orLabelSelectors[%d]: %w", i, err)
+		}
+		p.orLabelSelectors = append(p.orLabelSelectors, sel)
+	}
+
+	return p, nil
+}
+
+// AllowsNamespace reports whether namespace passes IncludedNamespaces/ExcludedNamespaces.
+func (p *Predicate) AllowsNamespace(namespace string) bool {
+	if matchesAny(p.excludedNamespaces, namespace) {
+		return false
+	}
+	return len(p.includedNamespaces) == 0 || matchesAny(p.includedNamespaces, namespace)
+}
+
+// AllowsResource reports whether resourceType (and, for ExcludedResources, the
+// "resourceType/name" form) passes IncludedResources/ExcludedResources.
+func (p *Predicate) AllowsResource(resourceType, name string) bool {
+	qualified := resourceType + "/" + name
+	for _, excluded := range p.excludedResources {
+		if matchesGlob(excluded, resourceType) || matchesGlob(excluded, qualified) {
+			return false
+		}
+	}
+	return len(p.includedResources) == 0 || matchesAny(p.includedResources, resourceType)
+}
+
+// AllowsLabels reports whether objLabels passes the configured selectors: if
+// OrLabelSelectors is set, any one of them matching is sufficient; otherwise
+// LabelSelector must match (or there is none, in which case everything passes).
+func (p *Predicate) AllowsLabels(objLabels map[string]string) bool {
+	set := labels.Set(objLabels)
+
+	if len(p.orLabelSelectors) > 0 {
+		for _, sel := range p.orLabelSelectors {
+			if sel.Matches(set) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if p.labelSelector != nil {
+		return p.labelSelector.Matches(set)
+	}
+
+	return true
+}
+
+// Allows reports whether obj, of resourceType, passes every configured dimension of
+// the policy: namespace, resource type/name, and labels.
+func (p *Predicate) Allows(obj *unstructured.Unstructured, resourceType string) bool {
+	if obj.GetNamespace() != "" && !p.AllowsNamespace(obj.GetNamespace()) {
+		return false
+	}
+	if !p.AllowsResource(resourceType, obj.GetName()) {
+		return false
+	}
+	return p.AllowsLabels(obj.GetLabels())
+}
+
+// matchesAny reports whether value matches any pattern in patterns. "*" matches
+// everything; any other pattern is a path.Match glob (e.g. "dev-*", "*.apps").
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matchesGlob(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesGlob reports whether value matches pattern, treating "*" as "match
+// everything" before falling back to path.Match so a bare "*" isn't limited by
+// path.Match's single-path-segment semantics.
+func matchesGlob(pattern, value string) bool {
+	if pattern == "*" {
+		return true
+	}
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
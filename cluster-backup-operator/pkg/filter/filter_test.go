@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCompileRejectsResourceListedInBothIncludedAndExcluded(t *testing.T) {
+	_, err := Compile(Policy{
+		IncludedResources: []string{"secrets"},
+		ExcludedResources: []string{"secrets"},
+	})
+	if err == nil {
+		t.Fatal("expected an error when a resource is listed in both IncludedResources and ExcludedResources")
+	}
+}
+
+func TestAllowsNamespaceGlob(t *testing.T) {
+	cases := []struct {
+		name      string
+		namespace string
+		want      bool
+	}{
+		{"excluded glob", "kube-system", false},
+		{"excluded glob other match", "kube-public", false},
+		{"not excluded", "dev-app", true},
+	}
+
+	p, err := Compile(Policy{ExcludedNamespaces: []string{"kube-*"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := p.AllowsNamespace(tc.namespace); got != tc.want {
+				t.Errorf("AllowsNamespace(%q) = %v, want %v", tc.namespace, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAllowsResourceExcludedByType(t *testing.T) {
+	p, err := Compile(Policy{ExcludedResources: []string{"secrets"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.AllowsResource("secrets", "db-creds") {
+		t.Error("expected every secret to be excluded")
+	}
+	if !p.AllowsResource("configmaps", "app-config") {
+		t.Error("expected configmaps to remain allowed")
+	}
+}
+
+func TestAllowsResourceExcludedByName(t *testing.T) {
+	p, err := Compile(Policy{ExcludedResources: []string{"secrets/db-creds"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.AllowsResource("secrets", "db-creds") {
+		t.Error("expected secrets/db-creds to be excluded")
+	}
+	if !p.AllowsResource("secrets", "other-secret") {
+		t.Error("expected secrets/other-secret to remain allowed")
+	}
+}
+
+func TestAllowsLabelsOrSelectorsTakePrecedence(t *testing.T) {
+	p, err := Compile(Policy{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "never-matches"}},
+		OrLabelSelectors: []metav1.LabelSelector{
+			{MatchLabels: map[string]string{"team": "a"}},
+			{MatchLabels: map[string]string{"team": "b"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !p.AllowsLabels(map[string]string{"team": "a"}) {
+		t.Error("expected team=a to match the first OrLabelSelectors entry")
+	}
+	if !p.AllowsLabels(map[string]string{"team": "b"}) {
+		t.Error("expected team=b to match the second OrLabelSelectors entry")
+	}
+	if p.AllowsLabels(map[string]string{"team": "c"}) {
+		t.Error("expected team=c to match neither OrLabelSelectors entry")
+	}
+}
+
+func TestAllowsLabelsFallsBackToLabelSelectorWhenNoOrSelectors(t *testing.T) {
+	p, err := Compile(Policy{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"backup": "enabled"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !p.AllowsLabels(map[string]string{"backup": "enabled"}) {
+		t.Error("expected a matching LabelSelector to allow the object")
+	}
+	if p.AllowsLabels(map[string]string{"backup": "disabled"}) {
+		t.Error("expected a non-matching LabelSelector to reject the object")
+	}
+}
+
+func TestAllowsLabelsWithNoSelectorsAllowsEverything(t *testing.T) {
+	p, err := Compile(Policy{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !p.AllowsLabels(map[string]string{"anything": "goes"}) {
+		t.Error("expected no selectors to allow every object")
+	}
+}
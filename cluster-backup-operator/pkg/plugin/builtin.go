@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import "encoding/json"
+
+// builtinItemActions are registered in every Registry without needing a
+// plugin manifest or a gRPC round trip.
+var builtinItemActions = map[string]ItemAction{
+	"strip-cluster-fields": stripClusterFieldsAction{},
+}
+
+// clusterManagedFields are metadata fields a cluster assigns at admission
+// time. RestoreReconciler.cleanResourceForRestore already strips these from
+// every resource it restores; stripClusterFieldsAction exists to demonstrate
+// the ItemAction extension point end-to-end, not to replace that step.
+var clusterManagedFields = []string{
+	"resourceVersion", "uid", "generation", "creationTimestamp", "selfLink", "managedFields",
+}
+
+// stripClusterFieldsAction is the built-in ItemAction shipped to demonstrate
+// the extension point without requiring an external plugin process.
+type stripClusterFieldsAction struct{}
+
+// Applies implements ItemAction; this action runs against every resource.
+func (stripClusterFieldsAction) Applies(group, kind, namespace string) bool {
+	return true
+}
+
+// Configure implements ItemAction. stripClusterFieldsAction takes no config.
+func (stripClusterFieldsAction) Configure(config map[string]string) error {
+	return nil
+}
+
+// Execute implements ItemAction.
+func (stripClusterFieldsAction) Execute(raw []byte) (ItemActionResult, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return ItemActionResult{}, err
+	}
+
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		for _, field := range clusterManagedFields {
+			delete(metadata, field)
+		}
+	}
+
+	transformed, err := json.Marshal(obj)
+	if err != nil {
+		return ItemActionResult{}, err
+	}
+	return ItemActionResult{Transformed: transformed}, nil
+}
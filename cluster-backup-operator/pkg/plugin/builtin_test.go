@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestStripClusterFieldsActionRemovesManagedFields covers the built-in
+// plugin's core behavior: cluster-assigned metadata is removed, user-authored
+// fields are left alone.
+func TestStripClusterFieldsActionRemovesManagedFields(t *testing.T) {
+	raw := []byte(`{"metadata":{"name":"demo","resourceVersion":"123","uid":"abc","labels":{"app":"demo"}}}`)
+
+	result, err := stripClusterFieldsAction{}.Execute(raw)
+	if err != nil {
+		t.Fatalf("Execute() returned error: %v", err)
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(result.Transformed, &obj); err != nil {
+		t.Fatalf("failed to parse transformed output: %v", err)
+	}
+	metadata := obj["metadata"].(map[string]interface{})
+
+	for _, field := range clusterManagedFields {
+		if _, ok := metadata[field]; ok {
+			t.Errorf("expected %q to be removed from metadata, still present", field)
+		}
+	}
+	if metadata["name"] != "demo" {
+		t.Errorf("expected metadata.name to be preserved, got %v", metadata["name"])
+	}
+	if labels, ok := metadata["labels"].(map[string]interface{}); !ok || labels["app"] != "demo" {
+		t.Errorf("expected metadata.labels to be preserved, got %v", metadata["labels"])
+	}
+}
+
+// TestNewRegistryIncludesBuiltins confirms NewRegistry seeds the built-in
+// actions without requiring LoadDir.
+func TestNewRegistryIncludesBuiltins(t *testing.T) {
+	registry := NewRegistry()
+	if _, ok := registry.Get("strip-cluster-fields"); !ok {
+		t.Error("expected strip-cluster-fields to be registered by default")
+	}
+	if _, ok := registry.Get("does-not-exist"); ok {
+		t.Error("expected unregistered plugin name to be absent")
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+)
+
+// The wire types below mirror plugin.proto's messages field-for-field, with
+// JSON tags instead of protobuf field numbers: grpcItemAction calls over the
+// jsonCodec registered in codec.go rather than requiring protoc-generated
+// Go types.
+
+type appliesRequest struct {
+	Group     string `json:"group"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+}
+
+type appliesResponse struct {
+	Applies bool `json:"applies"`
+}
+
+type configureRequest struct {
+	Config map[string]string `json:"config"`
+}
+
+type configureResponse struct{}
+
+type executeRequest struct {
+	Raw []byte `json:"raw"`
+}
+
+type resourceRefWire struct {
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+type executeResponse struct {
+	Transformed     []byte            `json:"transformed"`
+	AdditionalItems []resourceRefWire `json:"additionalItems"`
+	Skip            bool              `json:"skip"`
+}
+
+// grpcItemAction is an ItemAction backed by a plugin process reached over
+// gRPC, implementing the pluginproto.ItemAction service described in
+// plugin.proto.
+type grpcItemAction struct {
+	name string
+	conn *grpc.ClientConn
+}
+
+// Applies implements ItemAction. A dial/RPC failure is treated as "does not
+// apply" rather than surfaced as an error, since Applies has no error return
+// and a misbehaving plugin shouldn't block every other resource's restore.
+func (p *grpcItemAction) Applies(group, kind, namespace string) bool {
+	req := appliesRequest{Group: group, Kind: kind, Namespace: namespace}
+	var resp appliesResponse
+	if err := p.invoke(context.Background(), "Applies", &req, &resp); err != nil {
+		return false
+	}
+	return resp.Applies
+}
+
+// Configure implements ItemAction.
+func (p *grpcItemAction) Configure(config map[string]string) error {
+	req := configureRequest{Config: config}
+	var resp configureResponse
+	if err := p.invoke(context.Background(), "Configure", &req, &resp); err != nil {
+		return fmt.Errorf("plugin %s: Configure rpc failed: %w", p.name, err)
+	}
+	return nil
+}
+
+// Execute implements ItemAction.
+func (p *grpcItemAction) Execute(raw []byte) (ItemActionResult, error) {
+	req := executeRequest{Raw: raw}
+	var resp executeResponse
+	if err := p.invoke(context.Background(), "Execute", &req, &resp); err != nil {
+		return ItemActionResult{}, fmt.Errorf("plugin %s: Execute rpc failed: %w", p.name, err)
+	}
+
+	result := ItemActionResult{Transformed: resp.Transformed, Skip: resp.Skip}
+	for _, item := range resp.AdditionalItems {
+		result.AdditionalItems = append(result.AdditionalItems, ResourceRef{
+			Group:     item.Group,
+			Version:   item.Version,
+			Kind:      item.Kind,
+			Namespace: item.Namespace,
+			Name:      item.Name,
+		})
+	}
+	return result, nil
+}
+
+// invoke calls the pluginproto.ItemAction/<method> RPC over the jsonCodec.
+func (p *grpcItemAction) invoke(ctx context.Context, method string, req, resp interface{}) error {
+	return p.conn.Invoke(ctx, "/pluginproto.ItemAction/"+method, req, resp, grpc.CallContentSubtype(jsonCodecName))
+}
+
+// Close closes the underlying connection to the plugin process.
+func (p *grpcItemAction) Close() error {
+	return p.conn.Close()
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugin implements the ItemAction extension point: external
+// processes (or, for the "strip-cluster-fields" built-in, in-process code)
+// that mutate or filter a resource during backup or restore, reached over the
+// gRPC contract in plugin.proto. This mirrors Velero's
+// BackupItemAction/RestoreItemAction and the CNPG-I plugin pattern.
+package plugin
+
+// ResourceRef identifies one Kubernetes object an ItemAction's Execute wants
+// restored alongside the object it just transformed, e.g. a Secret a
+// plugin-managed field references.
+type ResourceRef struct {
+	Group     string
+	Version   string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// ItemActionResult is what Execute returns for one resource.
+type ItemActionResult struct {
+	// Transformed is the (possibly modified) resource, JSON-encoded the same
+	// way the input to Execute was. Left nil when the action made no change.
+	Transformed []byte
+
+	// AdditionalItems lists other objects the action wants backed up or restored
+	// alongside this one.
+	AdditionalItems []ResourceRef
+
+	// Skip, when true, tells the caller to drop this resource from the
+	// backup or restore entirely instead of applying Transformed.
+	Skip bool
+}
+
+// ItemAction mutates or filters one resource during backup or restore. Real
+// plugins run out-of-process and are reached over gRPC via Registry.LoadDir;
+// the built-in "strip-cluster-fields" action (see builtin.go) implements this
+// interface directly, in-process, to demonstrate the extension point without
+// requiring a sidecar process.
+type ItemAction interface {
+	// Applies reports whether this action should run against resources of the
+	// given group/kind in namespace (namespace is empty for cluster-scoped
+	// resources).
+	Applies(group, kind, namespace string) bool
+
+	// Configure passes the BackupSpec.ItemActions[].Config or
+	// RestoreSpec.ItemActions[].Config for this action to the plugin. It is
+	// called again before every Execute, not once per backup or restore.
+	Configure(config map[string]string) error
+
+	// Execute transforms raw, a single JSON-encoded Kubernetes resource.
+	Execute(raw []byte) (ItemActionResult, error)
+}
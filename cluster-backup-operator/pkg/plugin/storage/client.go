@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Client is a Provider backed by a plugin process reached over gRPC, implementing the
+// storageproto.StorageProvider service this package's wire.go describes.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// Dial connects to the plugin process listening on the given Unix domain socket path
+// (as started by Serve) and returns a Client satisfying Provider.
+func Dial(socketPath string) (*Client, error) {
+	conn, err := grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial storage plugin at %s: %w", socketPath, err)
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the underlying connection to the plugin process.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// PutObject implements Provider.
+func (c *Client) PutObject(ctx context.Context, bucket, key string, data []byte) error {
+	req := putObjectRequest{Bucket: bucket, Key: key, Data: data}
+	var resp putObjectResponse
+	if err := c.invoke(ctx, "PutObject", &req, &resp); err != nil {
+		return err
+	}
+	return asError(resp.Error)
+}
+
+// GetObject implements Provider.
+func (c *Client) GetObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	req := getObjectRequest{Bucket: bucket, Key: key}
+	var resp getObjectResponse
+	if err := c.invoke(ctx, "GetObject", &req, &resp); err != nil {
+		return nil, err
+	}
+	if err := asError(resp.Error); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// ListObjects implements Provider.
+func (c *Client) ListObjects(ctx context.Context, bucket, prefix string) ([]string, error) {
+	req := listObjectsRequest{Bucket: bucket, Prefix: prefix}
+	var resp listObjectsResponse
+	if err := c.invoke(ctx, "ListObjects", &req, &resp); err != nil {
+		return nil, err
+	}
+	if err := asError(resp.Error); err != nil {
+		return nil, err
+	}
+	return resp.Keys, nil
+}
+
+// DeleteObject implements Provider.
+func (c *Client) DeleteObject(ctx context.Context, bucket, key string) error {
+	req := deleteObjectRequest{Bucket: bucket, Key: key}
+	var resp deleteObjectResponse
+	if err := c.invoke(ctx, "DeleteObject", &req, &resp); err != nil {
+		return err
+	}
+	return asError(resp.Error)
+}
+
+// StatObject implements Provider.
+func (c *Client) StatObject(ctx context.Context, bucket, key string) (ObjectInfo, error) {
+	req := statObjectRequest{Bucket: bucket, Key: key}
+	var resp statObjectResponse
+	if err := c.invoke(ctx, "StatObject", &req, &resp); err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := asError(resp.Error); err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Size: resp.Size, ETag: resp.ETag}, nil
+}
+
+// HealthCheck implements Provider.
+func (c *Client) HealthCheck(ctx context.Context) error {
+	var resp healthCheckResponse
+	if err := c.invoke(ctx, "HealthCheck", &healthCheckRequest{}, &resp); err != nil {
+		return err
+	}
+	return asError(resp.Error)
+}
+
+// Capabilities implements Provider.
+func (c *Client) Capabilities(ctx context.Context) (Capabilities, error) {
+	var resp capabilitiesResponse
+	if err := c.invoke(ctx, "Capabilities", &capabilitiesRequest{}, &resp); err != nil {
+		return Capabilities{}, err
+	}
+	return Capabilities{Encryption: resp.Encryption, Compression: resp.Compression}, nil
+}
+
+// invoke calls the storageproto.StorageProvider/<method> RPC over the jsonCodec.
+func (c *Client) invoke(ctx context.Context, method string, req, resp interface{}) error {
+	return c.conn.Invoke(ctx, "/storageproto.StorageProvider/"+method, req, resp, grpc.CallContentSubtype(jsonCodecName))
+}
+
+// asError turns a wire response's Error string back into a Go error, or nil if empty.
+func asError(msg string) error {
+	if msg == "" {
+		return nil
+	}
+	return errors.New(msg)
+}
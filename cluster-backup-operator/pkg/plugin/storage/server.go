@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+)
+
+// storageServiceDesc wires Provider's methods up as a grpc.ServiceDesc by hand, since
+// there is no protoc toolchain in this tree to generate one from a storage.proto. Each
+// MethodDesc's Handler decodes a wire request via the jsonCodec, calls the
+// corresponding Provider method, and encodes a wire response - the same shape
+// protoc-gen-go-grpc would produce for a real storage.proto mirroring Provider.
+var storageServiceDesc = grpc.ServiceDesc{
+	ServiceName: "storageproto.StorageProvider",
+	HandlerType: (*Provider)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "PutObject", Handler: putObjectHandler},
+		{MethodName: "GetObject", Handler: getObjectHandler},
+		{MethodName: "ListObjects", Handler: listObjectsHandler},
+		{MethodName: "DeleteObject", Handler: deleteObjectHandler},
+		{MethodName: "StatObject", Handler: statObjectHandler},
+		{MethodName: "HealthCheck", Handler: healthCheckHandler},
+		{MethodName: "Capabilities", Handler: capabilitiesHandler},
+	},
+}
+
+func putObjectHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	var req putObjectRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	resp := putObjectResponse{}
+	if err := srv.(Provider).PutObject(ctx, req.Bucket, req.Key, req.Data); err != nil {
+		resp.Error = err.Error()
+	}
+	return &resp, nil
+}
+
+func getObjectHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	var req getObjectRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	resp := getObjectResponse{}
+	data, err := srv.(Provider).GetObject(ctx, req.Bucket, req.Key)
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Data = data
+	}
+	return &resp, nil
+}
+
+func listObjectsHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	var req listObjectsRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	resp := listObjectsResponse{}
+	keys, err := srv.(Provider).ListObjects(ctx, req.Bucket, req.Prefix)
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Keys = keys
+	}
+	return &resp, nil
+}
+
+func deleteObjectHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	var req deleteObjectRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	resp := deleteObjectResponse{}
+	if err := srv.(Provider).DeleteObject(ctx, req.Bucket, req.Key); err != nil {
+		resp.Error = err.Error()
+	}
+	return &resp, nil
+}
+
+func statObjectHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	var req statObjectRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	resp := statObjectResponse{}
+	info, err := srv.(Provider).StatObject(ctx, req.Bucket, req.Key)
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Size, resp.ETag = info.Size, info.ETag
+	}
+	return &resp, nil
+}
+
+func healthCheckHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	var req healthCheckRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	resp := healthCheckResponse{}
+	if err := srv.(Provider).HealthCheck(ctx); err != nil {
+		resp.Error = err.Error()
+	}
+	return &resp, nil
+}
+
+func capabilitiesHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	var req capabilitiesRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+	caps, err := srv.(Provider).Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &capabilitiesResponse{Encryption: caps.Encryption, Compression: caps.Compression}, nil
+}
+
+// Serve listens on socketPath and blocks, dispatching StorageProvider RPCs to impl,
+// until the listener errors or the process is killed. Plugin authors wire up a main
+// package with nothing more than:
+//
+//	func main() {
+//	    if err := storage.Serve(myProvider{}, "/var/run/plugin.sock"); err != nil {
+//	        log.Fatal(err)
+//	    }
+//	}
+func Serve(impl Provider, socketPath string) error {
+	if err := os.RemoveAll(socketPath); err != nil {
+		return fmt.Errorf("failed to remove stale socket %s: %w", socketPath, err)
+	}
+
+	lis, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", socketPath, err)
+	}
+
+	server := grpc.NewServer()
+	server.RegisterService(&storageServiceDesc, impl)
+
+	return server.Serve(lis)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storage is the SDK third parties use to ship an out-of-process storage
+// backend plugin (e.g. Ceph, Swift, a Restic repo) that pkg/storage.NewProvider can
+// dial when a StorageLocation names it as "plugin://<name>". A plugin author
+// implements Provider and calls Serve; the operator-side client in client.go dials the
+// resulting Unix socket and satisfies pkg/storage.Provider on the other end.
+//
+// There is no protoc toolchain in this tree, so the wire format is a hand-written
+// JSON codec (see codec.go) registered under grpc/encoding rather than protoc-generated
+// message types. A real deployment would swap this for generated stubs from a
+// storage.proto mirroring this interface without changing Provider or Serve.
+package storage
+
+import "context"
+
+// ObjectInfo is the metadata StatObject returns for a single object.
+type ObjectInfo struct {
+	Size int64  `json:"size"`
+	ETag string `json:"etag"`
+}
+
+// Capabilities is what a plugin advertises via its Capabilities RPC, so the operator
+// can refuse to schedule a backup whose StorageLocation requires something the plugin
+// does not support instead of failing partway through an upload.
+type Capabilities struct {
+	// Encryption reports whether the plugin honors StorageLocation.Encryption.
+	Encryption bool `json:"encryption"`
+
+	// Compression reports whether the plugin compresses objects before writing them
+	// to its backend.
+	Compression bool `json:"compression"`
+}
+
+// Provider is the interface a storage backend plugin implements. It mirrors
+// pkg/storage.Provider's operations, but over []byte instead of io.Reader/ReadCloser:
+// gRPC messages are serialized as a whole, so this SDK does not stream large objects
+// through the plugin boundary.
+type Provider interface {
+	// PutObject writes data to bucket/key.
+	PutObject(ctx context.Context, bucket, key string, data []byte) error
+
+	// GetObject returns the full content of bucket/key.
+	GetObject(ctx context.Context, bucket, key string) ([]byte, error)
+
+	// ListObjects returns the keys under prefix in bucket.
+	ListObjects(ctx context.Context, bucket, prefix string) ([]string, error)
+
+	// DeleteObject removes bucket/key.
+	DeleteObject(ctx context.Context, bucket, key string) error
+
+	// StatObject returns metadata for bucket/key without downloading its content.
+	StatObject(ctx context.Context, bucket, key string) (ObjectInfo, error)
+
+	// HealthCheck reports whether the plugin can currently reach its backend.
+	HealthCheck(ctx context.Context) error
+
+	// Capabilities reports what this plugin supports, for capability negotiation
+	// before the operator schedules a backup against it.
+	Capabilities(ctx context.Context) (Capabilities, error)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+// The request/response pairs below are the StorageProvider service's wire messages,
+// shared by the client (client.go) and server (server.go) halves of this package.
+// Each RPC's error, when non-empty, is surfaced by the client as a Go error instead of
+// a gRPC status, since the hand-rolled jsonCodec has no structured status support.
+
+type putObjectRequest struct {
+	Bucket string `json:"bucket"`
+	Key    string `json:"key"`
+	Data   []byte `json:"data"`
+}
+
+type putObjectResponse struct {
+	Error string `json:"error,omitempty"`
+}
+
+type getObjectRequest struct {
+	Bucket string `json:"bucket"`
+	Key    string `json:"key"`
+}
+
+type getObjectResponse struct {
+	Data  []byte `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type listObjectsRequest struct {
+	Bucket string `json:"bucket"`
+	Prefix string `json:"prefix"`
+}
+
+type listObjectsResponse struct {
+	Keys  []string `json:"keys,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+type deleteObjectRequest struct {
+	Bucket string `json:"bucket"`
+	Key    string `json:"key"`
+}
+
+type deleteObjectResponse struct {
+	Error string `json:"error,omitempty"`
+}
+
+type statObjectRequest struct {
+	Bucket string `json:"bucket"`
+	Key    string `json:"key"`
+}
+
+type statObjectResponse struct {
+	Size  int64  `json:"size"`
+	ETag  string `json:"etag"`
+	Error string `json:"error,omitempty"`
+}
+
+type healthCheckRequest struct{}
+
+type healthCheckResponse struct {
+	Error string `json:"error,omitempty"`
+}
+
+type capabilitiesRequest struct{}
+
+type capabilitiesResponse struct {
+	Encryption  bool `json:"encryption"`
+	Compression bool `json:"compression"`
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// AzureBlobProvider implements Provider on top of
+// github.com/Azure/azure-sdk-for-go/sdk/storage/azblob. The Endpoint on the
+// StorageLocation is expected to be the account URL
+// (https://<account>.blob.core.windows.net).
+type AzureBlobProvider struct {
+	client *azblob.Client
+}
+
+// NewAzureBlobProvider creates an AzureBlobProvider from a StorageLocation.
+func NewAzureBlobProvider(spec backupv1.StorageLocation) (*AzureBlobProvider, error) {
+	if spec.Endpoint == "" {
+		return nil, fmt.Errorf("storage endpoint (account URL) is required")
+	}
+
+	cred, err := azblob.NewSharedKeyCredential(spec.AccessKey, spec.SecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Azure shared key credential: %w", err)
+	}
+
+	client, err := azblob.NewClientWithSharedKeyCredential(spec.Endpoint, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure Blob client: %w", err)
+	}
+
+	return &AzureBlobProvider{client: client}, nil
+}
+
+// EnsureBucket implements Provider. In Azure Blob terms a "bucket" is a
+// container.
+func (p *AzureBlobProvider) EnsureBucket(ctx context.Context, bucket string) error {
+	_, err := p.client.CreateContainer(ctx, bucket, nil)
+	if err == nil {
+		return nil
+	}
+	if bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
+		return nil
+	}
+	return fmt.Errorf("failed to create Azure Blob container: %w", err)
+}
+
+// PutObject implements Provider.
+func (p *AzureBlobProvider) PutObject(ctx context.Context, bucket, key string, r io.Reader, size int64) error {
+	if _, err := p.client.UploadStream(ctx, bucket, key, r, nil); err != nil {
+		return fmt.Errorf("failed to upload blob: %w", err)
+	}
+	return nil
+}
+
+// GetObject implements Provider.
+func (p *AzureBlobProvider) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	resp, err := p.client.DownloadStream(ctx, bucket, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download blob: %w", err)
+	}
+	return resp.Body, nil
+}
+
+// ListObjects implements Provider.
+func (p *AzureBlobProvider) ListObjects(ctx context.Context, bucket, prefix string) ([]string, error) {
+	var keys []string
+	pager := p.client.NewListBlobsFlatPager(bucket, &azblob.ListBlobsFlatOptions{
+		Prefix: &prefix,
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blobs: %w", err)
+		}
+		for _, blob := range page.Segment.BlobItems {
+			keys = append(keys, *blob.Name)
+		}
+	}
+	return keys, nil
+}
+
+// DeleteObject implements Provider.
+func (p *AzureBlobProvider) DeleteObject(ctx context.Context, bucket, key string) error {
+	if _, err := p.client.DeleteBlob(ctx, bucket, key, nil); err != nil {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
+
+// StatObject implements Provider.
+func (p *AzureBlobProvider) StatObject(ctx context.Context, bucket, key string) (ObjectInfo, error) {
+	props, err := p.client.ServiceClient().NewContainerClient(bucket).NewBlobClient(key).GetProperties(ctx, nil)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to stat blob %s: %w", key, err)
+	}
+
+	var size int64
+	if props.ContentLength != nil {
+		size = *props.ContentLength
+	}
+	var etag string
+	if props.ETag != nil {
+		etag = string(*props.ETag)
+	}
+	return ObjectInfo{Size: size, ETag: etag}, nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultProviderCacheTTL is how long a ProviderCache entry may sit unused before the
+// janitor evicts it, used when NewProviderCache is given a zero TTL.
+const defaultProviderCacheTTL = 10 * time.Minute
+
+type cacheEntry struct {
+	provider Provider
+	lastUsed time.Time
+}
+
+// ProviderCache caches storage.Provider instances -- and the *http.Transport,
+// connection pool and DNS resolution each one carries -- keyed by a caller-supplied
+// cache key, so repeated reconciles of the same Backup/Restore do not pay to rebuild a
+// client every time. Safe for concurrent use; a background janitor goroutine closes
+// entries idle longer than ttl.
+type ProviderCache struct {
+	ttl time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]*cacheEntry
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewProviderCache starts a ProviderCache whose janitor evicts entries idle for longer
+// than ttl. A zero or negative ttl uses defaultProviderCacheTTL. Callers should Close
+// it when done (normally for the lifetime of the manager process) to stop the janitor
+// goroutine.
+func NewProviderCache(ttl time.Duration) *ProviderCache {
+	if ttl <= 0 {
+		ttl = defaultProviderCacheTTL
+	}
+	c := &ProviderCache{
+		ttl:     ttl,
+		entries: make(map[string]*cacheEntry),
+		stop:    make(chan struct{}),
+	}
+	go c.runJanitor()
+	return c
+}
+
+// Close stops the janitor goroutine. Safe to call more than once.
+func (c *ProviderCache) Close() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
+
+func (c *ProviderCache) runJanitor() {
+	ticker := time.NewTicker(c.ttl / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.evictIdle()
+		}
+	}
+}
+
+func (c *ProviderCache) evictIdle() {
+	cutoff := time.Now().Add(-c.ttl)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.entries {
+		if entry.lastUsed.Before(cutoff) {
+			closeProvider(entry.provider)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// closeProvider releases provider's held resources if it implements the optional
+// Closer capability (e.g. a plugin provider's gRPC connection), discarding any error:
+// eviction has nothing useful to do with a close failure beyond logging, and
+// ProviderCache has no logger of its own.
+func closeProvider(provider Provider) {
+	if closer, ok := provider.(Closer); ok {
+		_ = closer.Close()
+	}
+}
+
+// GetOrCreate returns the Provider cached under cacheKey, or builds one via
+// NewProvider and caches it if this is the first call (or the first call since
+// cacheKey last changed -- see CacheKey) for that key. Concurrent calls for the same
+// cold cacheKey may each build a Provider; only one wins the race to populate the
+// cache entry, and the others' freshly built Providers are simply discarded, which is
+// preferable to holding a lock across the network calls NewProvider can make.
+func (c *ProviderCache) GetOrCreate(ctx context.Context, k8sClient client.Client, allowedNamespaces []string, defaultNamespace string, cacheKey string, spec backupv1.StorageLocation) (Provider, error) {
+	if provider, ok := c.get(cacheKey); ok {
+		return provider, nil
+	}
+
+	provider, err := NewProvider(ctx, k8sClient, allowedNamespaces, defaultNamespace, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getOrStore(cacheKey, provider), nil
+}
+
+func (c *ProviderCache) get(cacheKey string) (Provider, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[cacheKey]
+	if !ok {
+		return nil, false
+	}
+	entry.lastUsed = time.Now()
+	return entry.provider, true
+}
+
+// getOrStore stores provider under cacheKey unless another goroutine already won the
+// race to populate it, in which case the existing cached Provider is returned instead
+// and provider is discarded.
+func (c *ProviderCache) getOrStore(cacheKey string, provider Provider) Provider {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[cacheKey]; ok {
+		entry.lastUsed = time.Now()
+		return entry.provider
+	}
+	c.entries[cacheKey] = &cacheEntry{provider: provider, lastUsed: time.Now()}
+	return provider
+}
+
+// Purge drops every cached entry, so the next GetOrCreate for any key rebuilds its
+// Provider from scratch. Used after a credential rotation (see RotateMinioCredentials):
+// ProviderCache has no index from a credentials Secret back to the cache keys of the
+// Backups/Restores that reference it, so a targeted per-key eviction isn't possible --
+// clearing everything is the honest, if coarser, alternative. A normal credentials
+// Secret update already invalidates affected entries on its own the moment the owning
+// Backup/Restore's CacheKey is recomputed with the Secret's new ResourceVersion; Purge
+// is only needed to force that out-of-band, the instant a rotation completes.
+func (c *ProviderCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, entry := range c.entries {
+		closeProvider(entry.provider)
+	}
+	c.entries = make(map[string]*cacheEntry)
+}
+
+// len reports the number of entries currently cached, for tests.
+func (c *ProviderCache) len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// CacheKey derives a ProviderCache key for a Backup/Restore named ownerName in
+// ownerNamespace at ownerResourceVersion, folding in credentialsResourceVersion (see
+// CredentialsRefResourceVersion) so a CredentialsRef Secret rotation invalidates the
+// cache even when the owner's own ResourceVersion is unchanged.
+func CacheKey(ownerNamespace, ownerName, ownerResourceVersion, credentialsResourceVersion string) string {
+	return ownerNamespace + "/" + ownerName + "@" + ownerResourceVersion + "#" + credentialsResourceVersion
+}
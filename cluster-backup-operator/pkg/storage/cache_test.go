@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+func newTestProviderCache(t *testing.T) *ProviderCache {
+	t.Helper()
+	c := NewProviderCache(time.Minute)
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestProviderCacheReturnsSameProviderForSameKey(t *testing.T) {
+	c := newTestProviderCache(t)
+	spec := backupv1.StorageLocation{Provider: "local", Bucket: "backups", Endpoint: t.TempDir()}
+
+	first, err := c.GetOrCreate(context.Background(), nil, nil, "", "owner@1#", spec)
+	require.NoError(t, err)
+
+	second, err := c.GetOrCreate(context.Background(), nil, nil, "", "owner@1#", spec)
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, c.len())
+}
+
+func TestProviderCacheMissesOnDifferentKey(t *testing.T) {
+	c := newTestProviderCache(t)
+	spec := backupv1.StorageLocation{Provider: "local", Bucket: "backups", Endpoint: t.TempDir()}
+
+	first, err := c.GetOrCreate(context.Background(), nil, nil, "", "owner@1#", spec)
+	require.NoError(t, err)
+
+	second, err := c.GetOrCreate(context.Background(), nil, nil, "", "owner@2#", spec)
+	require.NoError(t, err)
+
+	assert.NotSame(t, first, second)
+	assert.Equal(t, 2, c.len())
+}
+
+func TestCacheKeyChangesWithResourceVersionOrCredentials(t *testing.T) {
+	base := CacheKey("backups", "nightly", "1", "")
+	assert.NotEqual(t, base, CacheKey("backups", "nightly", "2", ""))
+	assert.NotEqual(t, base, CacheKey("backups", "nightly", "1", "5"))
+	assert.Equal(t, base, CacheKey("backups", "nightly", "1", ""))
+}
+
+// TestProviderCacheConcurrentGetOrCreate hammers GetOrCreate for the same key from
+// many goroutines, verifying (under -race) there is no data race on entry
+// construction/eviction and that every caller ends up with a single, consistent
+// Provider for that key once the dust settles.
+func TestProviderCacheConcurrentGetOrCreate(t *testing.T) {
+	c := newTestProviderCache(t)
+	spec := backupv1.StorageLocation{Provider: "local", Bucket: "backups", Endpoint: t.TempDir()}
+
+	const goroutines = 50
+	results := make([]Provider, goroutines)
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func(i int) {
+			defer wg.Done()
+			provider, err := c.GetOrCreate(context.Background(), nil, nil, "", "owner@1#", spec)
+			assert.NoError(t, err)
+			results[i] = provider
+		}(i)
+	}
+	wg.Wait()
+
+	for _, provider := range results {
+		assert.Same(t, results[0], provider)
+	}
+	assert.Equal(t, 1, c.len())
+}
+
+func TestProviderCacheEvictsIdleEntries(t *testing.T) {
+	c := NewProviderCache(20 * time.Millisecond)
+	defer c.Close()
+	spec := backupv1.StorageLocation{Provider: "local", Bucket: "backups", Endpoint: t.TempDir()}
+
+	_, err := c.GetOrCreate(context.Background(), nil, nil, "", "owner@1#", spec)
+	require.NoError(t, err)
+	require.Equal(t, 1, c.len())
+
+	require.Eventually(t, func() bool {
+		return c.len() == 0
+	}, 2*time.Second, 10*time.Millisecond)
+}
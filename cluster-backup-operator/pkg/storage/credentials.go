@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultAccessKeyField = "accessKey"
+	defaultSecretKeyField = "secretKey"
+)
+
+// Credentials holds the storage credentials resolved for a StorageLocation, whether
+// they came from a CredentialsRef Secret or (for backward compatibility) the spec's
+// deprecated inline AccessKey/SecretKey fields.
+type Credentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+
+	// CABundle is PEM-encoded CA certificate data for providers that need to trust a
+	// self-signed storage endpoint.
+	CABundle []byte
+}
+
+// ResolveCredentials resolves the credentials for spec. If spec.CredentialsRef is set,
+// it is read from the referenced Secret (after checking namespace against
+// allowedNamespaces); ref.Namespace defaults to defaultNamespace (normally the
+// namespace of the Backup/Restore object) when unset. Otherwise it falls back to the
+// spec's deprecated inline AccessKey/SecretKey fields.
+func ResolveCredentials(ctx context.Context, k8sClient client.Client, allowedNamespaces []string, defaultNamespace string, spec backupv1.StorageLocation) (Credentials, error) {
+	if spec.CredentialsRef == nil {
+		return Credentials{AccessKey: spec.AccessKey, SecretKey: spec.SecretKey}, nil
+	}
+
+	ref := spec.CredentialsRef
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if namespace == "" {
+		return Credentials{}, fmt.Errorf("credentialsRef %q has no namespace and none could be defaulted", ref.Name)
+	}
+	if !namespaceAllowed(namespace, allowedNamespaces) {
+		return Credentials{}, fmt.Errorf("credentialsRef namespace %q is not in the operator's allowed namespace list", namespace)
+	}
+
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+		return Credentials{}, fmt.Errorf("failed to get credentials secret %s/%s: %w", namespace, ref.Name, err)
+	}
+
+	accessKeyField := ref.AccessKeyField
+	if accessKeyField == "" {
+		accessKeyField = defaultAccessKeyField
+	}
+	secretKeyField := ref.SecretKeyField
+	if secretKeyField == "" {
+		secretKeyField = defaultSecretKeyField
+	}
+
+	accessKey, ok := secret.Data[accessKeyField]
+	if !ok {
+		return Credentials{}, fmt.Errorf("secret %s/%s has no data key %q", namespace, ref.Name, accessKeyField)
+	}
+	secretKey, ok := secret.Data[secretKeyField]
+	if !ok {
+		return Credentials{}, fmt.Errorf("secret %s/%s has no data key %q", namespace, ref.Name, secretKeyField)
+	}
+
+	creds := Credentials{AccessKey: string(accessKey), SecretKey: string(secretKey)}
+
+	if ref.SessionTokenField != "" {
+		if token, ok := secret.Data[ref.SessionTokenField]; ok {
+			creds.SessionToken = string(token)
+		}
+	}
+	if ref.CABundleField != "" {
+		ca, ok := secret.Data[ref.CABundleField]
+		if !ok {
+			return Credentials{}, fmt.Errorf("secret %s/%s has no data key %q for caBundleField", namespace, ref.Name, ref.CABundleField)
+		}
+		creds.CABundle = ca
+	}
+
+	return creds, nil
+}
+
+// CredentialsRefResourceVersion returns the ResourceVersion of the Secret
+// spec.CredentialsRef points at, or "" if CredentialsRef is unset. ProviderCache folds
+// this into its cache key so rotating the referenced Secret invalidates a cached
+// Provider even though the owning Backup/Restore's own ResourceVersion did not change.
+func CredentialsRefResourceVersion(ctx context.Context, k8sClient client.Client, allowedNamespaces []string, defaultNamespace string, spec backupv1.StorageLocation) (string, error) {
+	if spec.CredentialsRef == nil {
+		return "", nil
+	}
+
+	ref := spec.CredentialsRef
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if namespace == "" {
+		return "", fmt.Errorf("credentialsRef %q has no namespace and none could be defaulted", ref.Name)
+	}
+	if !namespaceAllowed(namespace, allowedNamespaces) {
+		return "", fmt.Errorf("credentialsRef namespace %q is not in the operator's allowed namespace list", namespace)
+	}
+
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+		return "", fmt.Errorf("failed to get credentials secret %s/%s: %w", namespace, ref.Name, err)
+	}
+	return secret.ResourceVersion, nil
+}
+
+func namespaceAllowed(namespace string, allowedNamespaces []string) bool {
+	if len(allowedNamespaces) == 0 {
+		return true
+	}
+	for _, ns := range allowedNamespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
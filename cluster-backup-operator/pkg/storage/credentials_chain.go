@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// namedCredentialsProvider pairs a credentials.Provider with the human-readable name
+// resolveChainedCredentials reports back (in logs and the CredentialsResolved
+// condition) when that provider is the one that satisfies the chain.
+type namedCredentialsProvider struct {
+	name     string
+	provider credentials.Provider
+}
+
+// buildCredentialsProviders returns, in declaration order, every credentials source
+// spec.CredentialsChain and staticCreds together configure: the resolved static
+// credentials first (so existing CredentialsRef/AccessKey+SecretKey YAML keeps
+// authenticating exactly as it does today), then each configured chain link.
+func buildCredentialsProviders(staticCreds Credentials, chain *backupv1.CredentialsChainConfig) []namedCredentialsProvider {
+	var named []namedCredentialsProvider
+
+	if staticCreds.AccessKey != "" || staticCreds.SecretKey != "" {
+		named = append(named, namedCredentialsProvider{
+			name: "static",
+			provider: &credentials.Static{Value: credentials.Value{
+				AccessKeyID:     staticCreds.AccessKey,
+				SecretAccessKey: staticCreds.SecretKey,
+				SessionToken:    staticCreds.SessionToken,
+				SignerType:      credentials.SignatureV4,
+			}},
+		})
+	}
+
+	if chain == nil {
+		return named
+	}
+
+	if chain.EnvAWS {
+		named = append(named, namedCredentialsProvider{name: "env-aws", provider: &credentials.EnvAWS{}})
+	}
+	if chain.EnvMinio {
+		named = append(named, namedCredentialsProvider{name: "env-minio", provider: &credentials.EnvMinio{}})
+	}
+	if chain.IAM != nil {
+		named = append(named, namedCredentialsProvider{
+			name:     "iam",
+			provider: &credentials.IAM{Endpoint: chain.IAM.Endpoint},
+		})
+	}
+	if wi := chain.WebIdentity; wi != nil {
+		tokenFile := wi.TokenFile
+		if tokenFile == "" {
+			tokenFile = os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+		}
+		named = append(named, namedCredentialsProvider{
+			name: "web-identity",
+			provider: &credentials.STSWebIdentity{
+				STSEndpoint: wi.STSEndpoint,
+				GetWebIDTokenExpiry: func() (*credentials.WebIdentityToken, error) {
+					token, err := os.ReadFile(tokenFile)
+					if err != nil {
+						return nil, fmt.Errorf("failed to read web identity token file %s: %w", tokenFile, err)
+					}
+					return &credentials.WebIdentityToken{Token: string(token)}, nil
+				},
+				RoleARN: wi.RoleARN,
+			},
+		})
+	}
+	if ar := chain.AssumeRole; ar != nil {
+		named = append(named, namedCredentialsProvider{
+			name: "assume-role",
+			provider: &credentials.STSAssumeRole{
+				STSEndpoint: ar.STSEndpoint,
+				Options: credentials.STSAssumeRoleOptions{
+					RoleARN:         ar.RoleARN,
+					RoleSessionName: ar.RoleSessionName,
+					ExternalID:      ar.ExternalID,
+				},
+			},
+		})
+	}
+
+	return named
+}
+
+// resolveChainedCredentials picks the first provider (in declaration order: the
+// resolved static credentials, then every configured CredentialsChain link) whose
+// Retrieve() succeeds, and returns a *credentials.Credentials built from the full
+// chain via credentials.NewChainCredentials for the minio client to actually use, so
+// it keeps the chain's own lazy refresh/expiry behavior rather than the one-shot
+// Value read here. The two passes over the chain (one to name the satisfying link for
+// observability, one inside NewChainCredentials itself when the client first calls
+// Get()) are an accepted cost: this only runs once per reconcile, at client
+// construction.
+func resolveChainedCredentials(staticCreds Credentials, chain *backupv1.CredentialsChainConfig) (*credentials.Credentials, string, error) {
+	named := buildCredentialsProviders(staticCreds, chain)
+	if len(named) == 0 {
+		return nil, "", fmt.Errorf("no credentials source configured: set credentialsRef, accessKey/secretKey, or a credentialsChain link")
+	}
+
+	var satisfiedBy string
+	var lastErr error
+	for _, np := range named {
+		value, err := np.provider.Retrieve()
+		if err != nil {
+			lastErr = fmt.Errorf("%s: %w", np.name, err)
+			continue
+		}
+		if value.AccessKeyID == "" {
+			// EnvAWS/EnvMinio in particular never return an error for unset env
+			// vars -- Retrieve() just returns an empty Value -- so a non-nil error
+			// is not sufficient to tell "this link is unconfigured" from "this link
+			// succeeded with no credentials", and the chain must fall through to
+			// the next configured link either way.
+			lastErr = fmt.Errorf("%s: no credentials available", np.name)
+			continue
+		}
+		satisfiedBy = np.name
+		break
+	}
+	if satisfiedBy == "" {
+		return nil, "", fmt.Errorf("no credentials chain link succeeded: %w", lastErr)
+	}
+
+	providers := make([]credentials.Provider, len(named))
+	for i, np := range named {
+		providers[i] = np.provider
+	}
+	return credentials.NewChainCredentials(providers), satisfiedBy, nil
+}
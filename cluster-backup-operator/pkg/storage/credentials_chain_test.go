@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+func TestBuildCredentialsProvidersOrdersStaticFirstThenChainLinks(t *testing.T) {
+	named := buildCredentialsProviders(
+		Credentials{AccessKey: "key", SecretKey: "secret"},
+		&backupv1.CredentialsChainConfig{EnvAWS: true, EnvMinio: true},
+	)
+
+	require.Len(t, named, 3)
+	assert.Equal(t, "static", named[0].name)
+	assert.Equal(t, "env-aws", named[1].name)
+	assert.Equal(t, "env-minio", named[2].name)
+}
+
+func TestResolveChainedCredentialsPrefersStaticOverChain(t *testing.T) {
+	creds, source, err := resolveChainedCredentials(
+		Credentials{AccessKey: "key", SecretKey: "secret"},
+		&backupv1.CredentialsChainConfig{EnvAWS: true},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, creds)
+	assert.Equal(t, "static", source)
+
+	value, err := creds.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "key", value.AccessKeyID)
+	assert.Equal(t, "secret", value.SecretAccessKey)
+}
+
+func TestResolveChainedCredentialsFallsThroughToNextLink(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("MINIO_ROOT_USER", "minio-user")
+	t.Setenv("MINIO_ROOT_PASSWORD", "minio-pass")
+
+	creds, source, err := resolveChainedCredentials(
+		Credentials{},
+		&backupv1.CredentialsChainConfig{EnvAWS: true, EnvMinio: true},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, creds)
+	assert.Equal(t, "env-minio", source)
+}
+
+func TestResolveChainedCredentialsErrorsWithNoSourceConfigured(t *testing.T) {
+	_, _, err := resolveChainedCredentials(Credentials{}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestResolveCredentialsFromSecretRef(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-creds", Namespace: "backups"},
+		Data: map[string][]byte{
+			"accessKey": []byte("AKIA..."),
+			"secretKey": []byte("shh"),
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	spec := backupv1.StorageLocation{
+		CredentialsRef: &backupv1.CredentialsReference{Name: "s3-creds", Namespace: "backups"},
+	}
+
+	creds, err := ResolveCredentials(context.Background(), fakeClient, []string{"backups"}, "", spec)
+	assert.NoError(t, err)
+	assert.Equal(t, "AKIA...", creds.AccessKey)
+	assert.Equal(t, "shh", creds.SecretKey)
+}
+
+func TestResolveCredentialsRejectsDisallowedNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	spec := backupv1.StorageLocation{
+		CredentialsRef: &backupv1.CredentialsReference{Name: "s3-creds", Namespace: "other-team"},
+	}
+
+	_, err := ResolveCredentials(context.Background(), fakeClient, []string{"backups"}, "", spec)
+	assert.Error(t, err)
+}
+
+func TestResolveCredentialsFallsBackToInlineFields(t *testing.T) {
+	spec := backupv1.StorageLocation{AccessKey: "inline-key", SecretKey: "inline-secret"}
+
+	creds, err := ResolveCredentials(context.Background(), nil, nil, "", spec)
+	assert.NoError(t, err)
+	assert.Equal(t, "inline-key", creds.AccessKey)
+	assert.Equal(t, "inline-secret", creds.SecretKey)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultEncryptionKeyField = "key"
+
+// resolveEncryption builds the encrypt.ServerSide to use for every PutObject/GetObject
+// call against a StorageLocation with Encryption configured. It returns (nil, nil) when
+// cfg is nil or its Mode is EncryptionModeNone.
+func resolveEncryption(ctx context.Context, k8sClient client.Client, allowedNamespaces []string, defaultNamespace string, cfg *backupv1.EncryptionConfig) (encrypt.ServerSide, error) {
+	if cfg == nil || cfg.Mode == "" || cfg.Mode == backupv1.EncryptionModeNone {
+		return nil, nil
+	}
+
+	switch cfg.Mode {
+	case backupv1.EncryptionModeSSEKMS:
+		if cfg.KMSKeyID == "" {
+			return nil, fmt.Errorf("encryption mode SSE-KMS requires kmsKeyID")
+		}
+		return encrypt.NewSSEKMS(cfg.KMSKeyID, nil)
+
+	case backupv1.EncryptionModeSSEC:
+		if cfg.KeyRef == nil {
+			return nil, fmt.Errorf("encryption mode SSE-C requires keyRef")
+		}
+
+		namespace := cfg.KeyRef.Namespace
+		if namespace == "" {
+			namespace = defaultNamespace
+		}
+		if namespace == "" {
+			return nil, fmt.Errorf("keyRef %q has no namespace and none could be defaulted", cfg.KeyRef.Name)
+		}
+		if !namespaceAllowed(namespace, allowedNamespaces) {
+			return nil, fmt.Errorf("keyRef namespace %q is not in the operator's allowed namespace list", namespace)
+		}
+
+		secret := &corev1.Secret{}
+		if err := k8sClient.Get(ctx, client.ObjectKey{Name: cfg.KeyRef.Name, Namespace: namespace}, secret); err != nil {
+			return nil, fmt.Errorf("failed to get encryption key secret %s/%s: %w", namespace, cfg.KeyRef.Name, err)
+		}
+
+		keyField := cfg.KeyRef.KeyField
+		if keyField == "" {
+			keyField = defaultEncryptionKeyField
+		}
+		key, ok := secret.Data[keyField]
+		if !ok {
+			return nil, fmt.Errorf("secret %s/%s has no data key %q", namespace, cfg.KeyRef.Name, keyField)
+		}
+
+		return encrypt.NewSSEC(key)
+
+	default:
+		return nil, fmt.Errorf("unsupported encryption mode %q", cfg.Mode)
+	}
+}
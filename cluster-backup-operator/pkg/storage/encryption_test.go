@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestResolveEncryptionNilWhenUnset(t *testing.T) {
+	sse, err := resolveEncryption(context.Background(), nil, nil, "", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, sse)
+
+	sse, err = resolveEncryption(context.Background(), nil, nil, "", &backupv1.EncryptionConfig{Mode: backupv1.EncryptionModeNone})
+	assert.NoError(t, err)
+	assert.Nil(t, sse)
+}
+
+func TestResolveEncryptionSSECFromSecretRef(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sse-c-key", Namespace: "backups"},
+		Data: map[string][]byte{
+			"key": []byte("01234567890123456789012345678901"),
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	cfg := &backupv1.EncryptionConfig{
+		Mode:   backupv1.EncryptionModeSSEC,
+		KeyRef: &backupv1.EncryptionKeyReference{Name: "sse-c-key", Namespace: "backups"},
+	}
+
+	sse, err := resolveEncryption(context.Background(), fakeClient, []string{"backups"}, "", cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, sse)
+}
+
+func TestResolveEncryptionSSECRejectsWrongKeyLength(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sse-c-key", Namespace: "backups"},
+		Data:       map[string][]byte{"key": []byte("too-short")},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	cfg := &backupv1.EncryptionConfig{
+		Mode:   backupv1.EncryptionModeSSEC,
+		KeyRef: &backupv1.EncryptionKeyReference{Name: "sse-c-key", Namespace: "backups"},
+	}
+
+	_, err := resolveEncryption(context.Background(), fakeClient, []string{"backups"}, "", cfg)
+	assert.Error(t, err)
+}
+
+func TestResolveEncryptionSSEKMSRequiresKeyID(t *testing.T) {
+	cfg := &backupv1.EncryptionConfig{Mode: backupv1.EncryptionModeSSEKMS}
+
+	_, err := resolveEncryption(context.Background(), nil, nil, "", cfg)
+	assert.Error(t, err)
+
+	cfg.KMSKeyID = "alias/backups"
+	sse, err := resolveEncryption(context.Background(), nil, nil, "", cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, sse)
+}
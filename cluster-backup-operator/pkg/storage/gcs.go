@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// GCSProvider implements Provider on top of cloud.google.com/go/storage.
+type GCSProvider struct {
+	client *storage.Client
+}
+
+// NewGCSProvider creates a GCSProvider from a StorageLocation. Credentials
+// are resolved by the underlying SDK's application-default-credentials
+// chain (workload identity, GOOGLE_APPLICATION_CREDENTIALS, etc).
+func NewGCSProvider(spec backupv1.StorageLocation) (*GCSProvider, error) {
+	client, err := storage.NewClient(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client: %w", err)
+	}
+	return &GCSProvider{client: client}, nil
+}
+
+// EnsureBucket implements Provider.
+func (p *GCSProvider) EnsureBucket(ctx context.Context, bucket string) error {
+	_, err := p.client.Bucket(bucket).Attrs(ctx)
+	if err == nil {
+		return nil
+	}
+	if err != storage.ErrBucketNotExist {
+		return fmt.Errorf("failed to check GCS bucket existence: %w", err)
+	}
+	if err := p.client.Bucket(bucket).Create(ctx, "", nil); err != nil {
+		return fmt.Errorf("failed to create GCS bucket: %w", err)
+	}
+	return nil
+}
+
+// PutObject implements Provider.
+func (p *GCSProvider) PutObject(ctx context.Context, bucket, key string, r io.Reader, size int64) error {
+	w := p.client.Bucket(bucket).Object(key).NewWriter(ctx)
+	if _, err := io.Copy(w, r); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("failed to upload object to GCS: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to finalize GCS upload: %w", err)
+	}
+	return nil
+}
+
+// GetObject implements Provider.
+func (p *GCSProvider) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	r, err := p.client.Bucket(bucket).Object(key).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object from GCS: %w", err)
+	}
+	return r, nil
+}
+
+// ListObjects implements Provider.
+func (p *GCSProvider) ListObjects(ctx context.Context, bucket, prefix string) ([]string, error) {
+	var keys []string
+	it := p.client.Bucket(bucket).Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list GCS objects: %w", err)
+		}
+		keys = append(keys, attrs.Name)
+	}
+	return keys, nil
+}
+
+// DeleteObject implements Provider.
+func (p *GCSProvider) DeleteObject(ctx context.Context, bucket, key string) error {
+	if err := p.client.Bucket(bucket).Object(key).Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete object from GCS: %w", err)
+	}
+	return nil
+}
+
+// StatObject implements Provider.
+func (p *GCSProvider) StatObject(ctx context.Context, bucket, key string) (ObjectInfo, error) {
+	attrs, err := p.client.Bucket(bucket).Object(key).Attrs(ctx)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to stat object %s in GCS: %w", key, err)
+	}
+	return ObjectInfo{Size: attrs.Size, ETag: attrs.Etag}, nil
+}
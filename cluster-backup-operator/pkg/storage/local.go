@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// LocalProvider implements Provider on top of the local filesystem. It is
+// primarily useful for tests and for air-gapped clusters backing onto a
+// mounted PVC instead of an object store.
+type LocalProvider struct {
+	// root is the directory all buckets are created under. It defaults to
+	// the StorageLocation's Endpoint when set, otherwise the OS temp dir.
+	root string
+}
+
+// NewLocalProvider creates a LocalProvider rooted at spec.Endpoint (or the
+// OS temp directory if unset).
+func NewLocalProvider(spec backupv1.StorageLocation) (*LocalProvider, error) {
+	root := spec.Endpoint
+	if root == "" {
+		root = os.TempDir()
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local storage root %s: %w", root, err)
+	}
+	return &LocalProvider{root: root}, nil
+}
+
+func (p *LocalProvider) bucketDir(bucket string) string {
+	return filepath.Join(p.root, bucket)
+}
+
+func (p *LocalProvider) objectPath(bucket, key string) string {
+	return filepath.Join(p.bucketDir(bucket), filepath.FromSlash(key))
+}
+
+// EnsureBucket implements Provider.
+func (p *LocalProvider) EnsureBucket(ctx context.Context, bucket string) error {
+	if err := os.MkdirAll(p.bucketDir(bucket), 0o755); err != nil {
+		return fmt.Errorf("failed to create local bucket directory: %w", err)
+	}
+	return nil
+}
+
+// PutObject implements Provider.
+func (p *LocalProvider) PutObject(ctx context.Context, bucket, key string, r io.Reader, size int64) error {
+	path := p.objectPath(bucket, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create parent directory for %s: %w", key, err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create local object %s: %w", key, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return fmt.Errorf("failed to write local object %s: %w", key, err)
+	}
+	return nil
+}
+
+// GetObject implements Provider.
+func (p *LocalProvider) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	f, err := os.Open(p.objectPath(bucket, key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open local object %s: %w", key, err)
+	}
+	return f, nil
+}
+
+// ListObjects implements Provider.
+func (p *LocalProvider) ListObjects(ctx context.Context, bucket, prefix string) ([]string, error) {
+	root := p.bucketDir(bucket)
+	var keys []string
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list local objects: %w", err)
+	}
+
+	return keys, nil
+}
+
+// DeleteObject implements Provider.
+func (p *LocalProvider) DeleteObject(ctx context.Context, bucket, key string) error {
+	if err := os.Remove(p.objectPath(bucket, key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete local object %s: %w", key, err)
+	}
+	return nil
+}
+
+// StatObject implements Provider. ETag is always empty: the local filesystem has no
+// equivalent content identifier cheaper than hashing the whole file.
+func (p *LocalProvider) StatObject(ctx context.Context, bucket, key string) (ObjectInfo, error) {
+	info, err := os.Stat(p.objectPath(bucket, key))
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to stat local object %s: %w", key, err)
+	}
+	return ObjectInfo{Size: info.Size()}, nil
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+func TestLocalProviderRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	provider, err := NewLocalProvider(backupv1.StorageLocation{Endpoint: t.TempDir()})
+	assert.NoError(t, err)
+
+	bucket := "test-bucket"
+	assert.NoError(t, provider.EnsureBucket(ctx, bucket))
+
+	data := []byte(`{"hello":"world"}`)
+	assert.NoError(t, provider.PutObject(ctx, bucket, "namespaces/default/configmaps/foo.json", bytes.NewReader(data), int64(len(data))))
+
+	reader, err := provider.GetObject(ctx, bucket, "namespaces/default/configmaps/foo.json")
+	assert.NoError(t, err)
+	defer reader.Close()
+
+	got, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	keys, err := provider.ListObjects(ctx, bucket, "namespaces/default")
+	assert.NoError(t, err)
+	assert.Contains(t, keys, "namespaces/default/configmaps/foo.json")
+
+	assert.NoError(t, provider.DeleteObject(ctx, bucket, "namespaces/default/configmaps/foo.json"))
+	keys, err = provider.ListObjects(ctx, bucket, "namespaces/default")
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestNewProviderUnsupported(t *testing.T) {
+	_, err := NewProvider(context.Background(), nil, nil, "", backupv1.StorageLocation{Provider: "bogus"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MinioProvider implements Provider on top of github.com/minio/minio-go/v7.
+// It backs the "minio" StorageLocation.Provider value (and is the default
+// when Provider is empty). Real AWS S3 buckets use S3Provider instead, for
+// IAM-role-first credential resolution; minio-go's S3-API compatibility made
+// MinioProvider double as the "s3" backend historically, but that value now
+// routes to S3Provider.
+type MinioProvider struct {
+	client *minio.Client
+
+	// sse is non-nil when StorageLocation.Encryption requested SSE-C or SSE-KMS; it is
+	// applied to every PutObject and (for SSE-C only) GetObject call.
+	sse encrypt.ServerSide
+
+	// objectLock is non-nil when StorageLocation.ObjectLock requested per-object WORM
+	// retention; it is applied to every PutObject call.
+	objectLock *backupv1.ObjectLockConfig
+
+	// credentialsSource names the CredentialsChainConfig link (or "static") that
+	// satisfied ResolveCredentials/resolveChainedCredentials, for CredentialsSource.
+	credentialsSource string
+}
+
+// CredentialsSource implements CredentialsReporter.
+func (p *MinioProvider) CredentialsSource() string {
+	return p.credentialsSource
+}
+
+// NewMinioProvider creates a MinioProvider from a StorageLocation, resolving its
+// credentials via ResolveCredentials (a CredentialsRef Secret, or the deprecated
+// inline AccessKey/SecretKey fields), then spec.CredentialsChain's additional fallback
+// links if any are configured.
+func NewMinioProvider(ctx context.Context, k8sClient client.Client, allowedNamespaces []string, defaultNamespace string, spec backupv1.StorageLocation) (*MinioProvider, error) {
+	endpoint := spec.Endpoint
+	if endpoint == "" {
+		return nil, fmt.Errorf("storage endpoint is required")
+	}
+
+	creds, err := ResolveCredentials(ctx, k8sClient, allowedNamespaces, defaultNamespace, spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve storage credentials: %w", err)
+	}
+
+	secure := tlsSecure(spec, spec.Secure || strings.HasPrefix(endpoint, "https://"))
+	endpoint = strings.TrimPrefix(endpoint, "http://")
+	endpoint = strings.TrimPrefix(endpoint, "https://")
+
+	// Neither CredentialsRef/AccessKey/SecretKey nor CredentialsChain configured:
+	// preserve the original workshop-default local Minio credentials rather than
+	// treating a fully empty StorageLocation as a resolution failure.
+	chainCreds, credentialsSource, err := resolveChainedCredentials(creds, spec.CredentialsChain)
+	if err != nil {
+		if spec.CredentialsChain != nil {
+			return nil, fmt.Errorf("failed to resolve storage credentials chain: %w", err)
+		}
+		chainCreds = credentials.NewStaticV4("minioadmin", "minioadmin123", "")
+		credentialsSource = "static-default"
+	}
+
+	options := &minio.Options{
+		Creds:  chainCreds,
+		Secure: secure,
+	}
+
+	tlsConfig, err := resolveTLSConfig(ctx, k8sClient, allowedNamespaces, defaultNamespace, spec, creds.CABundle)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		options.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+
+	minioClient, err := minio.New(endpoint, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Minio client: %w", err)
+	}
+
+	sse, err := resolveEncryption(ctx, k8sClient, allowedNamespaces, defaultNamespace, spec.Encryption)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve storage encryption: %w", err)
+	}
+
+	return &MinioProvider{client: minioClient, sse: sse, objectLock: spec.ObjectLock, credentialsSource: credentialsSource}, nil
+}
+
+// EnsureBucket implements Provider.
+func (p *MinioProvider) EnsureBucket(ctx context.Context, bucket string) error {
+	exists, err := p.client.BucketExists(ctx, bucket)
+	if err != nil {
+		return fmt.Errorf("failed to check bucket existence: %w", err)
+	}
+	if exists {
+		return nil
+	}
+	return p.client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{})
+}
+
+// PutObject implements Provider. When StorageLocation.Encryption was configured, the
+// object is written with the corresponding SSE-C or SSE-KMS server-side encryption.
+// When StorageLocation.ObjectLock was configured, it is also written with a retention
+// mode and until-date so it cannot be deleted or overwritten before then.
+func (p *MinioProvider) PutObject(ctx context.Context, bucket, key string, r io.Reader, size int64) error {
+	opts := minio.PutObjectOptions{
+		ContentType:          "application/json",
+		ServerSideEncryption: p.sse,
+	}
+	if p.objectLock != nil {
+		mode := minio.Governance
+		if p.objectLock.Mode == backupv1.ObjectLockModeCompliance {
+			mode = minio.Compliance
+		}
+		opts.Mode = mode
+		opts.RetainUntilDate = time.Now().AddDate(0, 0, int(p.objectLock.RetainDays))
+	}
+
+	_, err := p.client.PutObject(ctx, bucket, key, r, size, opts)
+	if err != nil {
+		return fmt.Errorf("failed to upload object to Minio: %w", err)
+	}
+	return nil
+}
+
+// GetObject implements Provider. SSE-C objects require the same customer key used to
+// encrypt them to be presented again here; SSE-KMS objects need no extra request
+// options since the server resolves the KMS key itself.
+func (p *MinioProvider) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	opts := minio.GetObjectOptions{}
+	if p.sse != nil && p.sse.Type() == encrypt.SSEC {
+		opts.ServerSideEncryption = p.sse
+	}
+
+	obj, err := p.client.GetObject(ctx, bucket, key, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object from Minio: %w", err)
+	}
+	return obj, nil
+}
+
+// ListObjects implements Provider.
+func (p *MinioProvider) ListObjects(ctx context.Context, bucket, prefix string) ([]string, error) {
+	var keys []string
+	for object := range p.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", object.Err)
+		}
+		keys = append(keys, object.Key)
+	}
+	return keys, nil
+}
+
+// DeleteObject implements Provider.
+func (p *MinioProvider) DeleteObject(ctx context.Context, bucket, key string) error {
+	if err := p.client.RemoveObject(ctx, bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete object from Minio: %w", err)
+	}
+	return nil
+}
+
+// StatObject implements Provider.
+func (p *MinioProvider) StatObject(ctx context.Context, bucket, key string) (ObjectInfo, error) {
+	info, err := p.client.StatObject(ctx, bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to stat object %s in Minio: %w", key, err)
+	}
+	return ObjectInfo{Size: info.Size, ETag: info.ETag}, nil
+}
+
+// lifecycleRuleID tags the lifecycle rule ConfigureLifecycle manages, so repeated
+// syncs replace it in place instead of accumulating duplicate rules.
+const lifecycleRuleID = "cluster-backup-retention"
+
+// ConfigureLifecycle implements storage.LifecycleConfigurer. Minio/S3 lifecycle rules
+// only expire objects by a single day count, so this is a coarse backstop sized to the
+// longest horizon policy configures (KeepDaily/KeepWeekly/KeepMonthly converted to
+// days); it is not an equivalent of the GFS pruning enforceRetention performs
+// object-by-object, only a guarantee that backups eventually expire even if the
+// operator never runs again. A policy with no day-based field configured removes the
+// managed rule instead of setting one.
+func (p *MinioProvider) ConfigureLifecycle(ctx context.Context, bucket string, policy backupv1.RetentionPolicy) error {
+	days := policy.KeepDaily
+	if weekly := policy.KeepWeekly * 7; weekly > days {
+		days = weekly
+	}
+	if monthly := policy.KeepMonthly * 30; monthly > days {
+		days = monthly
+	}
+
+	cfg := lifecycle.NewConfiguration()
+	if days > 0 {
+		cfg.Rules = []lifecycle.Rule{
+			{
+				ID:     lifecycleRuleID,
+				Status: "Enabled",
+				Expiration: lifecycle.Expiration{
+					Days: lifecycle.ExpirationDays(days),
+				},
+			},
+		}
+	}
+
+	if err := p.client.SetBucketLifecycle(ctx, bucket, cfg); err != nil {
+		return fmt.Errorf("failed to sync bucket lifecycle on Minio: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	pluginstorage "github.com/rfashwall/cnd-workshop/pkg/plugin/storage"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newPluginProvider resolves the BackupStorageProvider named name in defaultNamespace,
+// dials the plugin process listening on its SocketPath, negotiates capabilities
+// against what spec requires, and wraps the connection in a pluginProvider
+// implementing Provider.
+func newPluginProvider(ctx context.Context, k8sClient client.Client, defaultNamespace, name string, spec backupv1.StorageLocation) (Provider, error) {
+	bsp := &backupv1.BackupStorageProvider{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: defaultNamespace, Name: name}, bsp); err != nil {
+		return nil, fmt.Errorf("failed to look up BackupStorageProvider %s/%s: %w", defaultNamespace, name, err)
+	}
+	if bsp.Spec.SocketPath == "" {
+		return nil, fmt.Errorf("BackupStorageProvider %s/%s has no socketPath configured", defaultNamespace, name)
+	}
+
+	pluginClient, err := pluginstorage.Dial(bsp.Spec.SocketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial storage plugin %s: %w", name, err)
+	}
+
+	if err := pluginClient.HealthCheck(ctx); err != nil {
+		_ = pluginClient.Close()
+		return nil, fmt.Errorf("storage plugin %s failed health check: %w", name, err)
+	}
+
+	caps, err := pluginClient.Capabilities(ctx)
+	if err != nil {
+		_ = pluginClient.Close()
+		return nil, fmt.Errorf("failed to negotiate capabilities with storage plugin %s: %w", name, err)
+	}
+
+	if spec.Encryption != nil && spec.Encryption.Mode != backupv1.EncryptionModeNone && !caps.Encryption {
+		_ = pluginClient.Close()
+		return nil, fmt.Errorf("storage plugin %s does not advertise encryption support, but StorageLocation requires it", name)
+	}
+
+	return &pluginProvider{client: pluginClient}, nil
+}
+
+// pluginProvider adapts pkg/plugin/storage.Provider (whose methods exchange whole
+// []byte payloads over gRPC) to the Provider interface the controllers expect (whose
+// methods stream via io.Reader/io.ReadCloser). Objects are buffered in memory crossing
+// this boundary; a plugin backing very large objects should favor a backend that
+// supports presigned URLs instead, which this SDK does not model.
+type pluginProvider struct {
+	client *pluginstorage.Client
+}
+
+// EnsureBucket implements Provider. Plugin backends are expected to create
+// buckets/containers lazily on first write, so this is a no-op.
+func (p *pluginProvider) EnsureBucket(ctx context.Context, bucket string) error {
+	return nil
+}
+
+// PutObject implements Provider.
+func (p *pluginProvider) PutObject(ctx context.Context, bucket, key string, r io.Reader, size int64) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to buffer object %s for plugin upload: %w", key, err)
+	}
+	if err := p.client.PutObject(ctx, bucket, key, data); err != nil {
+		return fmt.Errorf("plugin PutObject %s failed: %w", key, err)
+	}
+	return nil
+}
+
+// GetObject implements Provider.
+func (p *pluginProvider) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	data, err := p.client.GetObject(ctx, bucket, key)
+	if err != nil {
+		return nil, fmt.Errorf("plugin GetObject %s failed: %w", key, err)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// ListObjects implements Provider.
+func (p *pluginProvider) ListObjects(ctx context.Context, bucket, prefix string) ([]string, error) {
+	keys, err := p.client.ListObjects(ctx, bucket, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("plugin ListObjects failed: %w", err)
+	}
+	return keys, nil
+}
+
+// DeleteObject implements Provider.
+func (p *pluginProvider) DeleteObject(ctx context.Context, bucket, key string) error {
+	if err := p.client.DeleteObject(ctx, bucket, key); err != nil {
+		return fmt.Errorf("plugin DeleteObject %s failed: %w", key, err)
+	}
+	return nil
+}
+
+// StatObject implements Provider.
+func (p *pluginProvider) StatObject(ctx context.Context, bucket, key string) (ObjectInfo, error) {
+	info, err := p.client.StatObject(ctx, bucket, key)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("plugin StatObject %s failed: %w", key, err)
+	}
+	return ObjectInfo{Size: info.Size, ETag: info.ETag}, nil
+}
+
+// Close implements Closer, releasing the gRPC connection dialed in newPluginProvider.
+func (p *pluginProvider) Close() error {
+	return p.client.Close()
+}
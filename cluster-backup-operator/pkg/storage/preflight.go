@@ -0,0 +1,172 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	madmin "github.com/minio/madmin-go/v3"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PreflightResult reports the cluster health RunPreflight observed. Source is
+// "admin" when the MinIO admin API answered, or "fallback" when it could not be
+// reached (or the provider is not minio/empty) and only bucket reachability was
+// checked -- in that case FreeBytes/OnlineDisks/OfflineDisks/Quorum are left zero and
+// should not be treated as meaningful.
+type PreflightResult struct {
+	Source string
+
+	FreeBytes    uint64
+	OnlineDisks  int
+	OfflineDisks int
+
+	// Quorum approximates the minimum online disks MinIO's erasure coding needs to
+	// stay writable, derived from StorageInfo.Backend.StandardSCParity. It is not an
+	// exact reproduction of MinIO's per-erasure-set quorum accounting, only a coarse
+	// preflight signal.
+	Quorum int
+}
+
+// RunPreflight checks that the storage backend named by spec can accept
+// requiredBytes more data, and (via the MinIO admin API, when reachable) that enough
+// disks are online to satisfy quorum, before a Restore starts writing to it.
+//
+// For the minio provider (or an empty spec.Provider) it tries the admin API first,
+// at spec.AdminEndpoint (defaulting to spec.Endpoint) using spec.AdminCredentialsRef
+// (defaulting to spec.CredentialsRef). If the admin API is unreachable, or the
+// provider is something other than minio, it falls back to confirming the bucket is
+// reachable through the regular Provider, which cannot report free space or disk
+// counts -- callers should not fail a restore solely because a fallback result has
+// FreeBytes/OnlineDisks/OfflineDisks/Quorum all zero.
+//
+// An error return means the check itself could not be completed to a verdict (e.g.
+// neither the admin API nor the bucket was reachable), or reports a positively
+// unhealthy verdict (not enough free space, or not enough online disks for quorum)
+// once the admin API did answer.
+func RunPreflight(ctx context.Context, k8sClient client.Client, allowedNamespaces []string, defaultNamespace string, spec backupv1.StorageLocation, requiredBytes int64) (PreflightResult, error) {
+	if spec.Provider == "minio" || spec.Provider == "" {
+		result, err := runAdminPreflight(ctx, k8sClient, allowedNamespaces, defaultNamespace, spec, requiredBytes)
+		if err == nil {
+			return result, nil
+		}
+		if result.Source == "admin" {
+			// The admin API answered but reported an unhealthy cluster; that is a
+			// real preflight failure, not a reason to fall back.
+			return result, err
+		}
+	}
+
+	return runFallbackPreflight(ctx, k8sClient, allowedNamespaces, defaultNamespace, spec)
+}
+
+// runAdminPreflight calls the MinIO admin API's StorageInfo and evaluates it against
+// requiredBytes. Its returned PreflightResult.Source is only ever "admin"; RunPreflight
+// uses that (rather than a separate bool) to tell "admin API reachable but unhealthy"
+// (propagate the error) apart from "admin API itself could not be reached" (fall
+// back), since both return a non-nil error from this function.
+func runAdminPreflight(ctx context.Context, k8sClient client.Client, allowedNamespaces []string, defaultNamespace string, spec backupv1.StorageLocation, requiredBytes int64) (PreflightResult, error) {
+	adminEndpoint := spec.AdminEndpoint
+	if adminEndpoint == "" {
+		adminEndpoint = spec.Endpoint
+	}
+	if adminEndpoint == "" {
+		return PreflightResult{}, fmt.Errorf("no admin or storage endpoint configured")
+	}
+
+	adminSpec := spec
+	if spec.AdminCredentialsRef != nil {
+		adminSpec.CredentialsRef = spec.AdminCredentialsRef
+	}
+	creds, err := ResolveCredentials(ctx, k8sClient, allowedNamespaces, defaultNamespace, adminSpec)
+	if err != nil {
+		return PreflightResult{}, fmt.Errorf("failed to resolve admin credentials: %w", err)
+	}
+
+	secure := tlsSecure(spec, spec.Secure)
+	adminClient, err := madmin.New(adminEndpoint, creds.AccessKey, creds.SecretKey, secure)
+	if err != nil {
+		return PreflightResult{}, fmt.Errorf("failed to create MinIO admin client: %w", err)
+	}
+
+	info, err := adminClient.StorageInfo(ctx)
+	if err != nil {
+		return PreflightResult{}, fmt.Errorf("failed to get storage info from MinIO admin API: %w", err)
+	}
+
+	result := summarizeStorageInfo(info)
+
+	if requiredBytes > 0 && result.FreeBytes < uint64(requiredBytes) {
+		return result, fmt.Errorf("insufficient free space: need %d bytes, have %d free across %d online disks", requiredBytes, result.FreeBytes, result.OnlineDisks)
+	}
+	if result.OnlineDisks < result.Quorum {
+		return result, fmt.Errorf("insufficient online disks for quorum: have %d online, need %d", result.OnlineDisks, result.Quorum)
+	}
+
+	return result, nil
+}
+
+// summarizeStorageInfo reduces a MinIO admin StorageInfo response to the fields
+// RunPreflight cares about: total free bytes across online disks, online/offline disk
+// counts, and an approximate quorum (online disks minus the backend's standard
+// storage-class parity).
+func summarizeStorageInfo(info madmin.StorageInfo) PreflightResult {
+	var freeBytes uint64
+	online, offline := 0, 0
+	for _, disk := range info.Disks {
+		if disk.State != madmin.DriveStateOk {
+			offline++
+			continue
+		}
+		online++
+		if disk.TotalSpace >= disk.UsedSpace {
+			freeBytes += disk.TotalSpace - disk.UsedSpace
+		}
+	}
+
+	quorum := online - info.Backend.StandardSCParity
+	if quorum < 0 {
+		quorum = 0
+	}
+
+	return PreflightResult{
+		Source:       "admin",
+		FreeBytes:    freeBytes,
+		OnlineDisks:  online,
+		OfflineDisks: offline,
+		Quorum:       quorum,
+	}
+}
+
+// runFallbackPreflight confirms spec.Bucket is reachable via the regular Provider, the
+// only check available when the admin API is unreachable or the provider is not
+// minio. It deliberately does not call EnsureBucket, which would create a missing
+// bucket as a side effect of what is meant to be a read-only health check; ListObjects
+// with an empty prefix is the least destructive call every Provider already supports.
+func runFallbackPreflight(ctx context.Context, k8sClient client.Client, allowedNamespaces []string, defaultNamespace string, spec backupv1.StorageLocation) (PreflightResult, error) {
+	provider, err := NewProvider(ctx, k8sClient, allowedNamespaces, defaultNamespace, spec)
+	if err != nil {
+		return PreflightResult{}, fmt.Errorf("failed to initialize storage provider for preflight: %w", err)
+	}
+	if _, err := provider.ListObjects(ctx, spec.Bucket, ""); err != nil {
+		return PreflightResult{}, fmt.Errorf("bucket %q is not reachable: %w", spec.Bucket, err)
+	}
+	return PreflightResult{Source: "fallback"}, nil
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	madmin "github.com/minio/madmin-go/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// TestRunPreflightFallsBackForNonMinioProvider verifies a "local" StorageLocation
+// never attempts the MinIO admin API and is satisfied by the bucket-reachability
+// fallback alone.
+func TestRunPreflightFallsBackForNonMinioProvider(t *testing.T) {
+	spec := backupv1.StorageLocation{
+		Provider: "local",
+		Bucket:   "backups",
+		Endpoint: t.TempDir(),
+	}
+
+	result, err := RunPreflight(context.Background(), nil, nil, "", spec, 1024)
+	require.NoError(t, err)
+	assert.Equal(t, "fallback", result.Source)
+	assert.Zero(t, result.OnlineDisks)
+}
+
+func TestSummarizeStorageInfoComputesFreeBytesAndQuorum(t *testing.T) {
+	info := madmin.StorageInfo{
+		Disks: []madmin.Disk{
+			{State: madmin.DriveStateOk, TotalSpace: 1000, UsedSpace: 400},
+			{State: madmin.DriveStateOk, TotalSpace: 1000, UsedSpace: 600},
+			{State: madmin.DriveStateOffline},
+		},
+	}
+	info.Backend.StandardSCParity = 1
+
+	result := summarizeStorageInfo(info)
+	assert.Equal(t, uint64(1000), result.FreeBytes)
+	assert.Equal(t, 2, result.OnlineDisks)
+	assert.Equal(t, 1, result.OfflineDisks)
+	assert.Equal(t, 1, result.Quorum)
+}
+
+func TestSummarizeStorageInfoClampsQuorumToZero(t *testing.T) {
+	info := madmin.StorageInfo{
+		Disks: []madmin.Disk{
+			{State: madmin.DriveStateOffline},
+			{State: madmin.DriveStateOffline},
+		},
+	}
+	info.Backend.StandardSCParity = 2
+
+	result := summarizeStorageInfo(info)
+	assert.Equal(t, 0, result.OnlineDisks)
+	assert.Equal(t, 0, result.Quorum)
+}
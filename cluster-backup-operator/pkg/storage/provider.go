@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storage abstracts the object-storage backend used to hold backup
+// artifacts so the controllers do not depend directly on any single SDK.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// pluginProviderPrefix marks a StorageLocation.Provider as naming a BackupStorageProvider
+// plugin rather than one of the in-tree backends, e.g. "plugin://ceph-rgw".
+const pluginProviderPrefix = "plugin://"
+
+// Provider is the interface every storage backend must implement so the
+// backup and restore controllers can move objects without knowing whether
+// they are talking to Minio/S3, GCS, Azure Blob, or the local filesystem.
+type Provider interface {
+	// EnsureBucket creates the bucket/container if it does not already exist.
+	EnsureBucket(ctx context.Context, bucket string) error
+
+	// PutObject uploads size bytes read from r to bucket/key.
+	PutObject(ctx context.Context, bucket, key string, r io.Reader, size int64) error
+
+	// GetObject returns a reader for bucket/key. Callers must close it.
+	GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error)
+
+	// ListObjects returns the keys under prefix in bucket.
+	ListObjects(ctx context.Context, bucket, prefix string) ([]string, error)
+
+	// DeleteObject removes bucket/key.
+	DeleteObject(ctx context.Context, bucket, key string) error
+
+	// StatObject returns metadata for bucket/key without downloading its content.
+	StatObject(ctx context.Context, bucket, key string) (ObjectInfo, error)
+}
+
+// ObjectInfo is the metadata StatObject returns for a single object.
+type ObjectInfo struct {
+	// Size is the object's content length in bytes.
+	Size int64
+
+	// ETag is the backend's content identifier (e.g. an S3 ETag or GCS generation),
+	// opaque outside the provider that produced it.
+	ETag string
+}
+
+// LifecycleConfigurer is an optional capability a Provider may implement to sync a
+// RetentionPolicy into a backend-managed lifecycle rule, so retention still holds if
+// the operator is offline. Callers should type-assert for it rather than requiring it
+// on every Provider, since most backends (and most StorageLocations, which simply
+// leave BackupSpec.Retention unset) have no such rule to sync.
+type LifecycleConfigurer interface {
+	// ConfigureLifecycle syncs bucket's lifecycle rules to match policy.
+	ConfigureLifecycle(ctx context.Context, bucket string, policy backupv1.RetentionPolicy) error
+}
+
+// CredentialsReporter is an optional capability a Provider may implement to name
+// which credentials source actually satisfied NewProvider, e.g. for a
+// CredentialsResolved status condition. Callers should type-assert for it rather than
+// requiring it on every Provider, since only MinioProvider's CredentialsChainConfig
+// supports more than one source to choose between.
+type CredentialsReporter interface {
+	// CredentialsSource names the chain link that produced the credentials in use,
+	// e.g. "static", "env-aws", "iam".
+	CredentialsSource() string
+}
+
+// Closer is an optional capability a Provider may implement to release resources
+// (e.g. a plugin provider's underlying gRPC connection) it holds beyond what garbage
+// collection alone would reclaim. Callers should type-assert for it rather than
+// requiring it on every Provider, since most providers (every HTTP-based one) have
+// nothing extra to release.
+type Closer interface {
+	Close() error
+}
+
+// NewProvider instantiates the concrete Provider selected by spec.Provider. It is the
+// single place that knows how to turn a StorageLocation into a working backend.
+//
+// k8sClient and allowedNamespaces are used to resolve spec.CredentialsRef when set;
+// allowedNamespaces is the operator's configured allow-list of namespaces Secrets may
+// be read from, and defaultNamespace is used when CredentialsRef.Namespace is empty
+// (normally the namespace of the Backup/Restore object driving this call).
+func NewProvider(ctx context.Context, k8sClient client.Client, allowedNamespaces []string, defaultNamespace string, spec backupv1.StorageLocation) (Provider, error) {
+	if strings.HasPrefix(spec.Provider, pluginProviderPrefix) {
+		name := strings.TrimPrefix(spec.Provider, pluginProviderPrefix)
+		return newPluginProvider(ctx, k8sClient, defaultNamespace, name, spec)
+	}
+
+	switch spec.Provider {
+	case "minio", "":
+		return NewMinioProvider(ctx, k8sClient, allowedNamespaces, defaultNamespace, spec)
+	case "s3":
+		return NewS3Provider(ctx, k8sClient, allowedNamespaces, defaultNamespace, spec)
+	case "gcs":
+		return NewGCSProvider(spec)
+	case "azblob", "azure":
+		return NewAzureBlobProvider(spec)
+	case "local", "file":
+		return NewLocalProvider(spec)
+	default:
+		return nil, fmt.Errorf("unsupported storage provider %q", spec.Provider)
+	}
+}
+
+// Copy streams every object under srcPrefix from src to the same key under
+// dstPrefix in dst, chunk by chunk, so a restore controller (or any future
+// migration tool) can move a backup between providers without buffering the
+// whole object in memory.
+func Copy(ctx context.Context, src Provider, srcBucket, srcPrefix string, dst Provider, dstBucket, dstPrefix string) error {
+	keys, err := src.ListObjects(ctx, srcBucket, srcPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to list source objects under %s: %w", srcPrefix, err)
+	}
+
+	if err := dst.EnsureBucket(ctx, dstBucket); err != nil {
+		return fmt.Errorf("failed to ensure destination bucket %s: %w", dstBucket, err)
+	}
+
+	for _, key := range keys {
+		if err := copyOne(ctx, src, srcBucket, key, dst, dstBucket, dstPrefix+key[len(srcPrefix):]); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// copyOne streams a single object through an io.Pipe so the destination
+// PutObject never needs the whole object materialized in memory.
+func copyOne(ctx context.Context, src Provider, srcBucket, srcKey string, dst Provider, dstBucket, dstKey string) error {
+	reader, err := src.GetObject(ctx, srcBucket, srcKey)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	pr, pw := io.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		n, copyErr := io.Copy(pw, reader)
+		_ = n
+		pw.CloseWithError(copyErr)
+		errCh <- copyErr
+	}()
+
+	// PutObject needs a known size; spill to a counting buffer is not
+	// possible while streaming, so providers that require a size read the
+	// pipe fully via io.Copy semantics by passing -1 and letting the SDK
+	// chunk it (handled per-provider).
+	if err := dst.PutObject(ctx, dstBucket, dstKey, pr, -1); err != nil {
+		return err
+	}
+
+	return <-errCh
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// ReplicaWriteStats tracks how many objects a ReplicatingProvider wrote to a single
+// replica target successfully versus how many it failed to write, plus the most
+// recent failure, over the lifetime of the ReplicatingProvider. The Backup controller
+// reads this out once a run completes to populate BackupStatus.Replicas.
+type ReplicaWriteStats struct {
+	// Name matches the ReplicaTarget.Name this status is for.
+	Name string
+
+	// Succeeded is how many objects this run reached this replica.
+	Succeeded int
+
+	// Failed is how many objects this run failed to reach this replica.
+	Failed int
+
+	// LastError is the most recent replication failure for this target, if any.
+	LastError error
+}
+
+// namedProvider pairs a replica Provider with the ReplicaTarget.Name it was built
+// from, so failures can be attributed back to the right BackupStatus.Replicas entry.
+type namedProvider struct {
+	name     string
+	provider Provider
+}
+
+// ReplicatingProvider wraps a primary Provider and fans out every object the primary
+// accepts to one or more replica Providers, modeled on Velero's backup-location
+// replication: the primary write is authoritative (its error fails the backup run),
+// while a replica write failure only degrades that one replica's status and never
+// fails the run.
+type ReplicatingProvider struct {
+	Provider
+
+	mu       sync.Mutex
+	replicas []namedProvider
+	stats    map[string]*ReplicaWriteStats
+}
+
+// NewReplicatingProvider wraps primary so every successful PutObject is also written
+// to each of replicas. Callers with no configured ReplicaTargets should use primary
+// directly instead of wrapping it in a ReplicatingProvider with an empty replica set.
+func NewReplicatingProvider(primary Provider, replicas map[string]Provider) *ReplicatingProvider {
+	rp := &ReplicatingProvider{Provider: primary, stats: make(map[string]*ReplicaWriteStats, len(replicas))}
+	for name, provider := range replicas {
+		rp.replicas = append(rp.replicas, namedProvider{name: name, provider: provider})
+		rp.stats[name] = &ReplicaWriteStats{Name: name}
+	}
+	return rp
+}
+
+// PutObject writes to the primary provider first; a primary failure is returned as-is
+// without attempting any replica. On primary success, the same bytes are written to
+// every replica target in turn and each outcome is recorded in Stats, but a replica
+// failure is never returned to the caller since it must not fail the backup run.
+func (rp *ReplicatingProvider) PutObject(ctx context.Context, bucket, key string, r io.Reader, size int64) error {
+	if len(rp.replicas) == 0 {
+		return rp.Provider.PutObject(ctx, bucket, key, r, size)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to buffer object %s for replication: %w", key, err)
+	}
+
+	if err := rp.Provider.PutObject(ctx, bucket, key, bytes.NewReader(data), size); err != nil {
+		return err
+	}
+
+	for _, replica := range rp.replicas {
+		rp.recordResult(replica.name, replica.provider.PutObject(ctx, bucket, key, bytes.NewReader(data), size))
+	}
+
+	return nil
+}
+
+func (rp *ReplicatingProvider) recordResult(name string, err error) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	stats := rp.stats[name]
+	if err != nil {
+		stats.Failed++
+		stats.LastError = err
+		return
+	}
+	stats.Succeeded++
+}
+
+// Stats returns a snapshot of every replica's write outcomes accumulated so far.
+func (rp *ReplicatingProvider) Stats() []ReplicaWriteStats {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	out := make([]ReplicaWriteStats, 0, len(rp.replicas))
+	for _, replica := range rp.replicas {
+		out = append(out, *rp.stats[replica.name])
+	}
+	return out
+}
+
+// ConfigureLifecycle implements LifecycleConfigurer by delegating to the primary
+// provider if it supports one, so wrapping a StorageLocation with ReplicaTargets does
+// not silently disable retention-policy lifecycle sync on the primary bucket.
+func (rp *ReplicatingProvider) ConfigureLifecycle(ctx context.Context, bucket string, policy backupv1.RetentionPolicy) error {
+	lc, ok := rp.Provider.(LifecycleConfigurer)
+	if !ok {
+		return nil
+	}
+	return lc.ConfigureLifecycle(ctx, bucket, policy)
+}
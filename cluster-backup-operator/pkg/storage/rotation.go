@@ -0,0 +1,198 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	madmin "github.com/minio/madmin-go/v3"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// pendingAccessKeyField and pendingSecretKeyField hold a newly generated credential
+// in SecretRef while RotateMinioCredentials is probing it, so a crash between writing
+// the pending key and promoting it leaves the Secret's primary accessKey/secretKey
+// fields (and whatever is currently authenticating with them) untouched.
+const (
+	pendingAccessKeyField = "pendingAccessKey"
+	pendingSecretKeyField = "pendingSecretKey"
+)
+
+// RotationResult reports the outcome of a RotateMinioCredentials call.
+type RotationResult struct {
+	// RotatedAccessKey is the access key that is now (or, in DryRun, would become)
+	// SecretRef's primary credential. Empty if DryRun is false and rotation failed.
+	RotatedAccessKey string
+
+	// DryRun echoes the request; when true nothing in MinIO or SecretRef was
+	// actually changed.
+	DryRun bool
+}
+
+// RotateMinioCredentials generates a new MinIO access/secret key pair, registers it
+// with the admin API at adminEndpoint (authenticating with SecretRef's current
+// credential), writes it into secretRef as a pending key, and probes it with a
+// BucketExists call against bucket before promoting it to secretRef's primary
+// accessKey/secretKey fields. If the probe fails, the new credential is removed from
+// MinIO again and secretRef is left holding its original, still-working credential.
+//
+// dryRun generates a candidate credential pair and returns it without calling the
+// admin API, probing anything, or writing secretRef.
+func RotateMinioCredentials(ctx context.Context, k8sClient client.Client, allowedNamespaces []string, defaultNamespace string, adminEndpoint string, secure bool, secretRef backupv1.CredentialsReference, bucket string, dryRun bool) (RotationResult, error) {
+	newAccessKey, newSecretKey, err := generateCredentialPair()
+	if err != nil {
+		return RotationResult{}, fmt.Errorf("failed to generate new credential pair: %w", err)
+	}
+
+	if dryRun {
+		return RotationResult{RotatedAccessKey: newAccessKey, DryRun: true}, nil
+	}
+
+	namespace := secretRef.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if namespace == "" {
+		return RotationResult{}, fmt.Errorf("secretRef %q has no namespace and none could be defaulted", secretRef.Name)
+	}
+	if !namespaceAllowed(namespace, allowedNamespaces) {
+		return RotationResult{}, fmt.Errorf("secretRef namespace %q is not in the operator's allowed namespace list", namespace)
+	}
+
+	accessKeyField := secretRef.AccessKeyField
+	if accessKeyField == "" {
+		accessKeyField = defaultAccessKeyField
+	}
+	secretKeyField := secretRef.SecretKeyField
+	if secretKeyField == "" {
+		secretKeyField = defaultSecretKeyField
+	}
+
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: secretRef.Name, Namespace: namespace}, secret); err != nil {
+		return RotationResult{}, fmt.Errorf("failed to get credentials secret %s/%s: %w", namespace, secretRef.Name, err)
+	}
+	currentAccessKey := string(secret.Data[accessKeyField])
+	currentSecretKey := string(secret.Data[secretKeyField])
+
+	adminClient, err := madmin.New(adminEndpoint, currentAccessKey, currentSecretKey, secure)
+	if err != nil {
+		return RotationResult{}, fmt.Errorf("failed to create MinIO admin client: %w", err)
+	}
+
+	if err := writeSecretField(ctx, k8sClient, secret, pendingAccessKeyField, newAccessKey); err != nil {
+		return RotationResult{}, fmt.Errorf("failed to write pending credential to secret %s/%s: %w", namespace, secretRef.Name, err)
+	}
+	if err := writeSecretField(ctx, k8sClient, secret, pendingSecretKeyField, newSecretKey); err != nil {
+		return RotationResult{}, fmt.Errorf("failed to write pending credential to secret %s/%s: %w", namespace, secretRef.Name, err)
+	}
+
+	if err := adminClient.AddUser(ctx, newAccessKey, newSecretKey); err != nil {
+		clearPendingFields(ctx, k8sClient, secret)
+		return RotationResult{}, fmt.Errorf("failed to register new credential with MinIO admin API: %w", err)
+	}
+
+	if err := probeCredential(ctx, adminEndpoint, secure, newAccessKey, newSecretKey, bucket); err != nil {
+		// The probe failed: remove the credential MinIO never got a chance to prove
+		// itself with, and leave secretRef's primary key (still currentAccessKey/
+		// currentSecretKey) exactly as it was.
+		_ = adminClient.RemoveUser(ctx, newAccessKey)
+		clearPendingFields(ctx, k8sClient, secret)
+		return RotationResult{}, fmt.Errorf("new credential failed probe against bucket %q, rolled back: %w", bucket, err)
+	}
+
+	if err := writeSecretField(ctx, k8sClient, secret, accessKeyField, newAccessKey); err != nil {
+		return RotationResult{}, fmt.Errorf("failed to promote new credential in secret %s/%s: %w", namespace, secretRef.Name, err)
+	}
+	if err := writeSecretField(ctx, k8sClient, secret, secretKeyField, newSecretKey); err != nil {
+		return RotationResult{}, fmt.Errorf("failed to promote new credential in secret %s/%s: %w", namespace, secretRef.Name, err)
+	}
+	clearPendingFields(ctx, k8sClient, secret)
+
+	// The old credential is no longer referenced by secretRef; remove it from MinIO
+	// so a leaked/rolled-back Secret state cannot be used to authenticate with it.
+	if currentAccessKey != "" && currentAccessKey != newAccessKey {
+		_ = adminClient.RemoveUser(ctx, currentAccessKey)
+	}
+
+	return RotationResult{RotatedAccessKey: newAccessKey}, nil
+}
+
+// probeCredential confirms accessKey/secretKey can authenticate against bucket before
+// RotateMinioCredentials trusts them enough to become the Secret's primary credential.
+func probeCredential(ctx context.Context, endpoint string, secure bool, accessKey, secretKey, bucket string) error {
+	probeClient, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: secure,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create probe client: %w", err)
+	}
+	exists, err := probeClient.BucketExists(ctx, bucket)
+	if err != nil {
+		return fmt.Errorf("BucketExists probe failed: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("bucket %q does not exist", bucket)
+	}
+	return nil
+}
+
+// writeSecretField patches a single field of secret's data and keeps the in-memory
+// copy in sync so later calls in the same rotation see the update.
+func writeSecretField(ctx context.Context, k8sClient client.Client, secret *corev1.Secret, field, value string) error {
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[field] = []byte(value)
+	return k8sClient.Update(ctx, secret)
+}
+
+// clearPendingFields removes the pending credential fields written by a rotation
+// attempt that did not complete, best-effort -- a failure here just leaves stale
+// (and inert, since they were never promoted or otherwise trusted) pending fields
+// behind for the next attempt to overwrite.
+func clearPendingFields(ctx context.Context, k8sClient client.Client, secret *corev1.Secret) {
+	if secret.Data == nil {
+		return
+	}
+	delete(secret.Data, pendingAccessKeyField)
+	delete(secret.Data, pendingSecretKeyField)
+	_ = k8sClient.Update(ctx, secret)
+}
+
+// generateCredentialPair returns a random MinIO-compatible access/secret key pair.
+func generateCredentialPair() (accessKey, secretKey string, err error) {
+	accessKeyBytes := make([]byte, 15)
+	if _, err := rand.Read(accessKeyBytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate access key: %w", err)
+	}
+	secretKeyBytes := make([]byte, 30)
+	if _, err := rand.Read(secretKeyBytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate secret key: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(accessKeyBytes), base64.RawURLEncoding.EncodeToString(secretKeyBytes), nil
+}
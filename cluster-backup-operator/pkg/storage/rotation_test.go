@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+func TestGenerateCredentialPairProducesDistinctValues(t *testing.T) {
+	accessKey1, secretKey1, err := generateCredentialPair()
+	require.NoError(t, err)
+	accessKey2, secretKey2, err := generateCredentialPair()
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, accessKey1)
+	assert.NotEmpty(t, secretKey1)
+	assert.NotEqual(t, accessKey1, accessKey2)
+	assert.NotEqual(t, secretKey1, secretKey2)
+}
+
+// TestRotateMinioCredentialsDryRunTouchesNothing verifies DryRun returns a candidate
+// credential without contacting a Secret or the (here, deliberately unreachable)
+// admin endpoint -- a nil k8sClient would panic if DryRun ever fell through to the
+// real rotation path.
+func TestRotateMinioCredentialsDryRunTouchesNothing(t *testing.T) {
+	secretRef := backupv1.CredentialsReference{Name: "minio-admin", Namespace: "backups"}
+
+	result, err := RotateMinioCredentials(context.Background(), nil, nil, "", "minio.example.invalid:9000", true, secretRef, "backups", true)
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.NotEmpty(t, result.RotatedAccessKey)
+}
+
+// TestRotateMinioCredentialsRequiresResolvableNamespace verifies the namespace/
+// allow-list check runs (and fails closed) before any admin API call, the same as
+// ResolveCredentials does for ordinary credential resolution.
+func TestRotateMinioCredentialsRequiresResolvableNamespace(t *testing.T) {
+	secretRef := backupv1.CredentialsReference{Name: "minio-admin"}
+
+	_, err := RotateMinioCredentials(context.Background(), nil, nil, "", "minio.example.invalid:9000", true, secretRef, "backups", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no namespace")
+}
+
+func TestRotateMinioCredentialsRejectsDisallowedNamespace(t *testing.T) {
+	secretRef := backupv1.CredentialsReference{Name: "minio-admin", Namespace: "other"}
+
+	_, err := RotateMinioCredentials(context.Background(), nil, []string{"backups"}, "", "minio.example.invalid:9000", true, secretRef, "backups", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not in the operator's allowed namespace list")
+}
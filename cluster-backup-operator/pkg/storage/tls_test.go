@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func tlsTestScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	return scheme
+}
+
+// generateTestCertKeyPair returns a freshly generated, PEM-encoded self-signed
+// certificate and its private key, for tests that need a real (cert, key) pair
+// without depending on a large hardcoded PEM fixture.
+func generateTestCertKeyPair(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "storage-tls-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"127.0.0.1", "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM
+}
+
+func encodeCertPEM(cert *x509.Certificate) ([]byte, error) {
+	if cert == nil {
+		return nil, fmt.Errorf("nil certificate")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), nil
+}
+
+func TestResolveTLSConfigReturnsNilWithoutAnyTLSSource(t *testing.T) {
+	cfg, err := resolveTLSConfig(context.Background(), nil, nil, "", backupv1.StorageLocation{}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, cfg)
+}
+
+func TestResolveTLSConfigAppliesInsecureSkipVerify(t *testing.T) {
+	spec := backupv1.StorageLocation{TLS: &backupv1.StorageTLSConfig{InsecureSkipVerify: true}}
+
+	cfg, err := resolveTLSConfig(context.Background(), nil, nil, "", spec, nil)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	assert.True(t, cfg.InsecureSkipVerify)
+}
+
+func TestResolveTLSConfigLoadsCABundleFromSecretRef(t *testing.T) {
+	certPEM, _ := generateTestCertKeyPair(t)
+
+	scheme := tlsTestScheme(t)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: "backups"},
+		Data:       map[string][]byte{"ca.crt": certPEM},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	spec := backupv1.StorageLocation{
+		TLS: &backupv1.StorageTLSConfig{
+			CABundleSecretRef: &backupv1.StorageCABundleReference{Name: "ca-bundle", Namespace: "backups"},
+		},
+	}
+
+	cfg, err := resolveTLSConfig(context.Background(), fakeClient, []string{"backups"}, "", spec, nil)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	require.NotNil(t, cfg.RootCAs)
+}
+
+func TestResolveTLSConfigLoadsClientCertFromSecretRef(t *testing.T) {
+	certPEM, keyPEM := generateTestCertKeyPair(t)
+
+	scheme := tlsTestScheme(t)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "client-cert", Namespace: "backups"},
+		Data: map[string][]byte{
+			"tls.crt": certPEM,
+			"tls.key": keyPEM,
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	spec := backupv1.StorageLocation{
+		TLS: &backupv1.StorageTLSConfig{
+			ClientCertSecretRef: &backupv1.StorageClientCertReference{Name: "client-cert", Namespace: "backups"},
+		},
+	}
+
+	cfg, err := resolveTLSConfig(context.Background(), fakeClient, []string{"backups"}, "", spec, nil)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	require.Len(t, cfg.Certificates, 1)
+}
+
+func TestTLSSecureUsesExplicitEnabledOverAutoDetection(t *testing.T) {
+	disabled := false
+	spec := backupv1.StorageLocation{TLS: &backupv1.StorageTLSConfig{Enabled: &disabled}}
+	assert.False(t, tlsSecure(spec, true))
+
+	enabled := true
+	spec = backupv1.StorageLocation{TLS: &backupv1.StorageTLSConfig{Enabled: &enabled}}
+	assert.True(t, tlsSecure(spec, false))
+
+	assert.True(t, tlsSecure(backupv1.StorageLocation{}, true))
+}
+
+// TestNewMinioProviderTrustsCABundleAgainstTLSServer exercises the full path through
+// NewMinioProvider against a real httptest.NewTLSServer, verifying a CA bundle
+// resolved from StorageTLSConfig.CABundleSecretRef actually lets the minio client
+// trust the server's self-signed certificate.
+func TestNewMinioProviderTrustsCABundleAgainstTLSServer(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caPEM, err := encodeCertPEM(server.Certificate())
+	require.NoError(t, err)
+
+	scheme := tlsTestScheme(t)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: "backups"},
+		Data:       map[string][]byte{"ca.crt": caPEM},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	spec := backupv1.StorageLocation{
+		Provider: "minio",
+		Bucket:   "demo",
+		Endpoint: server.Listener.Addr().String(),
+		Secure:   true,
+		TLS: &backupv1.StorageTLSConfig{
+			CABundleSecretRef: &backupv1.StorageCABundleReference{Name: "ca-bundle", Namespace: "backups"},
+		},
+	}
+
+	provider, err := NewMinioProvider(context.Background(), fakeClient, []string{"backups"}, "", spec)
+	require.NoError(t, err)
+	require.NotNil(t, provider)
+
+	// The test server doesn't speak the full S3 API, so EnsureBucket itself may still
+	// fail on response parsing; what this guards against is the client rejecting the
+	// TLS handshake outright for not trusting the self-signed certificate.
+	err = provider.EnsureBucket(context.Background(), "demo")
+	if err != nil {
+		assert.NotContains(t, err.Error(), "certificate")
+	}
+}
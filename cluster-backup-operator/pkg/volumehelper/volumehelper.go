@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package volumehelper evaluates a Backup's VolumePolicy DSL against one PVC's
+// attributes. It has no Kubernetes client dependency: callers gather a VolumeContext
+// from the cluster (StorageClass, mounting Pods, etc.) and Resolve picks the first
+// matching rule, so the decision logic stays unit-testable without a fake client.
+package volumehelper
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	corev1 "k8s.io/api/core/v1"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// VolumeContext carries the attributes of one PVC that VolumePolicyRule.Conditions can
+// match against, gathered by the caller from the cluster.
+type VolumeContext struct {
+	// StorageClassName is pvc.Spec.StorageClassName, or "" if unset.
+	StorageClassName string
+
+	// CSIDriver is the CSI driver backing StorageClassName, resolved from the
+	// cluster's StorageClass object. Empty if the StorageClass is not CSI-backed or
+	// could not be resolved.
+	CSIDriver string
+
+	// CapacityBytes is pvc.Spec.Resources.Requests[storage] in bytes.
+	CapacityBytes int64
+
+	// AccessModes is pvc.Spec.AccessModes.
+	AccessModes []corev1.PersistentVolumeAccessMode
+
+	// PodMounted reports whether any running pod currently mounts the PVC.
+	PodMounted bool
+}
+
+// Resolve returns the action of the first rule in rules whose Conditions all match
+// vctx, along with its index. matched is false if no rule matches, in which case the
+// caller should fall back to its own legacy default.
+func Resolve(rules []backupv1.VolumePolicyRule, vctx VolumeContext) (action backupv1.VolumeAction, ruleIndex int, matched bool) {
+	for i, rule := range rules {
+		if conditionsMatch(rule.Conditions, vctx) {
+			return rule.Action, i, true
+		}
+	}
+	return backupv1.VolumeAction{}, -1, false
+}
+
+// conditionsMatch reports whether every non-zero field of c matches vctx. A zero-valued
+// field is not checked, so a rule can match on as few or as many dimensions as needed.
+func conditionsMatch(c backupv1.VolumeConditions, vctx VolumeContext) bool {
+	if c.StorageClass != "" && c.StorageClass != vctx.StorageClassName {
+		return false
+	}
+	if c.CSIDriver != "" && c.CSIDriver != vctx.CSIDriver {
+		return false
+	}
+	if c.Capacity != "" && !capacityInRange(c.Capacity, vctx.CapacityBytes) {
+		return false
+	}
+	if len(c.AccessModes) > 0 && !containsAllAccessModes(vctx.AccessModes, c.AccessModes) {
+		return false
+	}
+	if c.PodMounted != nil && *c.PodMounted != vctx.PodMounted {
+		return false
+	}
+	return true
+}
+
+// capacityInRange parses rng as an inclusive "min,max" quantity range (either side may
+// be empty to leave that bound unchecked, e.g. ",100Gi") and reports whether bytes falls
+// within it. An unparsable bound is treated as unbounded rather than failing the match,
+// since a typo in the policy should not silently exclude every volume.
+func capacityInRange(rng string, bytes int64) bool {
+	minStr, maxStr, _ := strings.Cut(rng, ",")
+
+	if minStr = strings.TrimSpace(minStr); minStr != "" {
+		if min, err := resource.ParseQuantity(minStr); err == nil && bytes < min.Value() {
+			return false
+		}
+	}
+	if maxStr = strings.TrimSpace(maxStr); maxStr != "" {
+		if max, err := resource.ParseQuantity(maxStr); err == nil && bytes > max.Value() {
+			return false
+		}
+	}
+	return true
+}
+
+// containsAllAccessModes reports whether have contains every mode in want.
+func containsAllAccessModes(have, want []corev1.PersistentVolumeAccessMode) bool {
+	set := make(map[corev1.PersistentVolumeAccessMode]bool, len(have))
+	for _, m := range have {
+		set[m] = true
+	}
+	for _, m := range want {
+		if !set[m] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumehelper
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+)
+
+// TestResolveFirstMatchWins verifies that the first rule whose conditions match wins,
+// even when a later rule would also match.
+func TestResolveFirstMatchWins(t *testing.T) {
+	rules := []backupv1.VolumePolicyRule{
+		{
+			Conditions: backupv1.VolumeConditions{StorageClass: "fast-ssd"},
+			Action:     backupv1.VolumeAction{Type: backupv1.VolumeActionSnapshot},
+		},
+		{
+			Conditions: backupv1.VolumeConditions{},
+			Action:     backupv1.VolumeAction{Type: backupv1.VolumeActionFSBackup},
+		},
+	}
+
+	action, idx, matched := Resolve(rules, VolumeContext{StorageClassName: "fast-ssd"})
+	if !matched || idx != 0 || action.Type != backupv1.VolumeActionSnapshot {
+		t.Errorf("Resolve() = %+v, idx %d, matched %v; want snapshot rule 0", action, idx, matched)
+	}
+}
+
+// TestResolveNoMatch verifies that Resolve reports matched=false when no rule's
+// conditions are satisfied.
+func TestResolveNoMatch(t *testing.T) {
+	rules := []backupv1.VolumePolicyRule{
+		{
+			Conditions: backupv1.VolumeConditions{StorageClass: "fast-ssd"},
+			Action:     backupv1.VolumeAction{Type: backupv1.VolumeActionSnapshot},
+		},
+	}
+
+	_, _, matched := Resolve(rules, VolumeContext{StorageClassName: "slow-hdd"})
+	if matched {
+		t.Errorf("expected no rule to match, but Resolve reported a match")
+	}
+}
+
+// TestConditionsMatchCapacityRange verifies that Capacity matches are inclusive range
+// checks, with either bound optional.
+func TestConditionsMatchCapacityRange(t *testing.T) {
+	cases := []struct {
+		name  string
+		rng   string
+		bytes int64
+		want  bool
+	}{
+		{"within bounded range", "10Gi,100Gi", 50 << 30, true},
+		{"below bounded range", "10Gi,100Gi", 5 << 30, false},
+		{"above bounded range", "10Gi,100Gi", 200 << 30, false},
+		{"unbounded min", ",100Gi", 1, true},
+		{"unbounded max", "10Gi,", 1 << 40, true},
+	}
+
+	for _, tc := range cases {
+		c := backupv1.VolumeConditions{Capacity: tc.rng}
+		got := conditionsMatch(c, VolumeContext{CapacityBytes: tc.bytes})
+		if got != tc.want {
+			t.Errorf("%s: conditionsMatch(capacity=%s, bytes=%d) = %v, want %v", tc.name, tc.rng, tc.bytes, got, tc.want)
+		}
+	}
+}
+
+// TestConditionsMatchAccessModes verifies that AccessModes requires every listed mode
+// to be present, not just one of them.
+func TestConditionsMatchAccessModes(t *testing.T) {
+	c := backupv1.VolumeConditions{
+		AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce, corev1.ReadOnlyMany},
+	}
+
+	if conditionsMatch(c, VolumeContext{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}}) {
+		t.Errorf("expected no match when only one of the required access modes is present")
+	}
+	if !conditionsMatch(c, VolumeContext{AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce, corev1.ReadOnlyMany, corev1.ReadWriteMany}}) {
+		t.Errorf("expected match when all required access modes are present, plus an extra one")
+	}
+}
+
+// TestConditionsMatchPodMounted verifies that PodMounted is only checked when set.
+func TestConditionsMatchPodMounted(t *testing.T) {
+	mounted := true
+	c := backupv1.VolumeConditions{PodMounted: &mounted}
+
+	if conditionsMatch(c, VolumeContext{PodMounted: false}) {
+		t.Errorf("expected no match when PodMounted condition requires true but vctx has false")
+	}
+	if !conditionsMatch(c, VolumeContext{PodMounted: true}) {
+		t.Errorf("expected match when PodMounted condition and vctx agree")
+	}
+	if !conditionsMatch(backupv1.VolumeConditions{}, VolumeContext{PodMounted: false}) {
+		t.Errorf("expected unset PodMounted condition to never block a match")
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumesnapshotter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+)
+
+// RecordsPrefix is the backupPath-relative folder SnapshotRecords are read from and the
+// provisioned-volume records below are written to. Exported so callers backing up a PV
+// (writing a SnapshotRecord) and callers restoring one (reading it back, e.g.
+// internal/controller.restoreVolumeFromSnapshot) agree on the same layout without
+// depending on StorageSnapshotter itself.
+const RecordsPrefix = "volume-snapshots"
+
+// provisionedVolumeRecord is what CreateVolumeFromSnapshot persists so repeated calls
+// for the same snapshotID are idempotent rather than minting a new volume ID every
+// reconcile.
+type provisionedVolumeRecord struct {
+	SnapshotID string `json:"snapshotID"`
+	VolumeID   string `json:"volumeID"`
+}
+
+// StorageSnapshotter is the Snapshotter backing this repo: there is no real cloud
+// volume API here, so CreateVolumeFromSnapshot synthesizes a volume ID from snapshotID
+// and records the mapping as JSON alongside the backup's manifests, in the same bucket
+// and storage location used for the rest of the backup/restore path.
+type StorageSnapshotter struct {
+	Provider   storage.Provider
+	Bucket     string
+	BackupPath string
+}
+
+// NewStorageSnapshotter returns a StorageSnapshotter reading and writing snapshot
+// metadata under backupPath in bucket via provider.
+func NewStorageSnapshotter(provider storage.Provider, bucket, backupPath string) *StorageSnapshotter {
+	return &StorageSnapshotter{Provider: provider, Bucket: bucket, BackupPath: backupPath}
+}
+
+// Lookup returns the SnapshotRecord BackupReconciler recorded for pvName, or (nil, nil)
+// if none was backed up (most PVs have no snapshot record).
+func (s *StorageSnapshotter) Lookup(ctx context.Context, pvName string) (*SnapshotRecord, error) {
+	key := fmt.Sprintf("%s/%s/%s.json", s.BackupPath, RecordsPrefix, pvName)
+
+	info, err := s.Provider.StatObject(ctx, s.Bucket, key)
+	if err != nil || info.Size == 0 {
+		return nil, nil
+	}
+
+	var record SnapshotRecord
+	if err := s.downloadJSON(ctx, key, &record); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot record for pv %s: %w", pvName, err)
+	}
+	return &record, nil
+}
+
+// CreateVolumeFromSnapshot synthesizes a new volume ID for snapshotID and records the
+// mapping so a re-run of the same restore reuses it instead of provisioning again.
+// volumeType, az, and iops are recorded for observability but don't affect the
+// synthesized ID, since there's no real cloud provider behind this implementation.
+func (s *StorageSnapshotter) CreateVolumeFromSnapshot(snapshotID, volumeType, az string, iops *int64) (string, error) {
+	ctx := context.Background()
+	key := fmt.Sprintf("%s/%s/provisioned-%s.json", s.BackupPath, RecordsPrefix, snapshotID)
+
+	var existing provisionedVolumeRecord
+	if err := s.downloadJSON(ctx, key, &existing); err == nil && existing.VolumeID != "" {
+		return existing.VolumeID, nil
+	}
+
+	record := provisionedVolumeRecord{
+		SnapshotID: snapshotID,
+		VolumeID:   fmt.Sprintf("restored-%s", snapshotID),
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal provisioned volume record: %w", err)
+	}
+	if err := s.Provider.PutObject(ctx, s.Bucket, key, bytes.NewReader(data), int64(len(data))); err != nil {
+		return "", fmt.Errorf("failed to persist provisioned volume record: %w", err)
+	}
+
+	return record.VolumeID, nil
+}
+
+// SetVolumeID rewrites pv's CSI volume handle, the only volume-source kind this repo's
+// snapshot tooling supports (see pkg/volumehelper and internal/controller/backup_volumepolicy.go).
+func (s *StorageSnapshotter) SetVolumeID(pv *corev1.PersistentVolume, volumeID string) error {
+	if pv.Spec.CSI == nil {
+		return fmt.Errorf("persistentvolume %s has no CSI volume source to rewrite", pv.Name)
+	}
+	pv.Spec.CSI.VolumeHandle = volumeID
+	return nil
+}
+
+// GetVolumeID reads pv's current CSI volume handle.
+func (s *StorageSnapshotter) GetVolumeID(pv *corev1.PersistentVolume) (string, error) {
+	if pv.Spec.CSI == nil {
+		return "", fmt.Errorf("persistentvolume %s has no CSI volume source", pv.Name)
+	}
+	return pv.Spec.CSI.VolumeHandle, nil
+}
+
+// downloadJSON downloads bucket/key and JSON-decodes it into out.
+func (s *StorageSnapshotter) downloadJSON(ctx context.Context, key string, out interface{}) error {
+	object, err := s.Provider.GetObject(ctx, s.Bucket, key)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := io.Copy(buf, object); err != nil {
+		return err
+	}
+	return json.Unmarshal(buf.Bytes(), out)
+}
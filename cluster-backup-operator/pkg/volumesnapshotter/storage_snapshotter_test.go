@@ -0,0 +1,93 @@
+package volumesnapshotter
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/pkg/storage"
+)
+
+func newTestSnapshotter(t *testing.T) *StorageSnapshotter {
+	t.Helper()
+	provider, err := storage.NewLocalProvider(backupv1.StorageLocation{Endpoint: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create local provider: %v", err)
+	}
+	return NewStorageSnapshotter(provider, "test-bucket", "backups/2026-01-01")
+}
+
+// TestLookupNoRecordReturnsNil verifies Lookup returns (nil, nil), not an error, when
+// no SnapshotRecord was ever written for the given PV.
+func TestLookupNoRecordReturnsNil(t *testing.T) {
+	s := newTestSnapshotter(t)
+
+	record, err := s.Lookup(context.Background(), "pv-without-snapshot")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record != nil {
+		t.Errorf("Lookup() = %+v, want nil", record)
+	}
+}
+
+// TestCreateVolumeFromSnapshotIsIdempotent verifies that calling
+// CreateVolumeFromSnapshot twice for the same snapshotID returns the same volume ID
+// rather than provisioning a second volume.
+func TestCreateVolumeFromSnapshotIsIdempotent(t *testing.T) {
+	s := newTestSnapshotter(t)
+
+	first, err := s.CreateVolumeFromSnapshot("snap-123", "gp3", "us-east-1a", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first == "" {
+		t.Fatalf("expected a non-empty volume ID")
+	}
+
+	second, err := s.CreateVolumeFromSnapshot("snap-123", "gp3", "us-east-1a", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second != first {
+		t.Errorf("CreateVolumeFromSnapshot() = %q on second call, want %q (idempotent)", second, first)
+	}
+}
+
+// TestSetAndGetVolumeIDRoundTrip verifies SetVolumeID/GetVolumeID operate on the PV's
+// CSI volume handle.
+func TestSetAndGetVolumeIDRoundTrip(t *testing.T) {
+	s := newTestSnapshotter(t)
+	pv := &corev1.PersistentVolume{
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-old"},
+			},
+		},
+	}
+
+	if err := s.SetVolumeID(pv, "vol-new"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := s.GetVolumeID(pv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "vol-new" {
+		t.Errorf("GetVolumeID() = %q, want %q", got, "vol-new")
+	}
+}
+
+// TestSetVolumeIDRejectsNonCSIVolume verifies a PV without a CSI source is rejected
+// rather than silently no-op'd.
+func TestSetVolumeIDRejectsNonCSIVolume(t *testing.T) {
+	s := newTestSnapshotter(t)
+	pv := &corev1.PersistentVolume{}
+
+	if err := s.SetVolumeID(pv, "vol-new"); err == nil {
+		t.Errorf("expected an error for a PV with no CSI volume source, got nil")
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package volumesnapshotter provisions a fresh cloud volume from a backed-up snapshot
+// and rewrites a PersistentVolume's CSI volume handle to point at it, independently of
+// the CSI VolumeSnapshot/VolumeSnapshotContent-based PVC restore flow in
+// internal/controller/restore_volumesnapshot.go. Callers look up a PV's SnapshotRecord
+// (see Lookup) and pass its SnapshotID into CreateVolumeFromSnapshot.
+package volumesnapshotter
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Snapshotter provisions a new volume from a snapshot and reads/writes the resulting
+// volume ID on a PersistentVolume. Implementations are cloud-provider-specific; the
+// only one in this repo is StorageSnapshotter.
+type Snapshotter interface {
+	// CreateVolumeFromSnapshot provisions a new volume from snapshotID, sized and
+	// placed per volumeType/az, with iops applied if the underlying provider supports
+	// provisioned IOPS (nil leaves it at the provider's default). Returns the new
+	// volume's ID.
+	CreateVolumeFromSnapshot(snapshotID, volumeType, az string, iops *int64) (volumeID string, err error)
+
+	// SetVolumeID rewrites pv's cloud-provider volume handle to volumeID.
+	SetVolumeID(pv *corev1.PersistentVolume, volumeID string) error
+
+	// GetVolumeID reads pv's current cloud-provider volume handle.
+	GetVolumeID(pv *corev1.PersistentVolume) (string, error)
+}
+
+// SnapshotRecord is the metadata BackupReconciler would need to record per PV for
+// RestoreVolumes to provision a replacement volume: which snapshot backs it, and the
+// volume attributes to recreate. Keyed by PV name in storage; see Lookup.
+type SnapshotRecord struct {
+	// PVName is the PersistentVolume this record was captured for.
+	PVName string `json:"pvName"`
+
+	// SnapshotID identifies the underlying cloud snapshot, e.g. an EBS snapshot ID.
+	SnapshotID string `json:"snapshotID"`
+
+	// VolumeType is the cloud-provider volume type to provision, e.g. "gp3".
+	VolumeType string `json:"volumeType,omitempty"`
+
+	// AvailabilityZone is where the new volume should be provisioned.
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// IOPS requests provisioned IOPS on volume types that support it. nil leaves it at
+	// the provider's default.
+	IOPS *int64 `json:"iops,omitempty"`
+}
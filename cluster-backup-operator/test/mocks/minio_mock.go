@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"bytes"
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"strings"
@@ -9,10 +11,49 @@ import (
 	"time"
 )
 
+// EncryptionParams describes the server-side encryption requested for an object,
+// mirroring what a real object store accepts: SSE-C (Algorithm + a customer-supplied
+// CustomerKey, identified on the wire by its CustomerKeyMD5) or SSE-KMS (a managed
+// KMSKeyID, with Algorithm/CustomerKey left empty). PutObject records whichever one was
+// used to write an object; GetObject must be given the same one back to read it.
+type EncryptionParams struct {
+	Algorithm      string
+	CustomerKey    []byte
+	CustomerKeyMD5 string
+	KMSKeyID       string
+}
+
+// sseC reports whether p requests SSE-C (a customer-supplied key) rather than SSE-KMS.
+func (p *EncryptionParams) sseC() bool {
+	return p != nil && len(p.CustomerKey) > 0
+}
+
+// matches reports whether p is the same encryption (same customer key, or same KMS key
+// ID) as written, so GetObject can tell a wrong-key read from a correct one.
+func (p *EncryptionParams) matches(written *EncryptionParams) bool {
+	if p == nil || written == nil {
+		return p == nil && written == nil
+	}
+	if written.sseC() != p.sseC() {
+		return false
+	}
+	if written.sseC() {
+		return hex.EncodeToString(md5Sum(p.CustomerKey)) == hex.EncodeToString(md5Sum(written.CustomerKey))
+	}
+	return p.KMSKeyID == written.KMSKeyID
+}
+
+func md5Sum(b []byte) []byte {
+	sum := md5.Sum(b)
+	return sum[:]
+}
+
 // MockMinioClient provides a mock implementation of Minio client for testing
 type MockMinioClient struct {
-	objects map[string][]byte
-	mutex   sync.RWMutex
+	objects    map[string][]byte
+	encryption map[string]*EncryptionParams
+	replicas   []*MockMinioClient
+	mutex      sync.RWMutex
 
 	// Configuration for simulating failures
 	ShouldFailPutObject   bool
@@ -23,6 +64,17 @@ type MockMinioClient struct {
 	PutObjectCalls   []PutObjectCall
 	GetObjectCalls   []GetObjectCall
 	ListObjectsCalls []ListObjectsCall
+	ReplicationCalls []ReplicationCall
+}
+
+// ReplicationCall represents a single fan-out write to a replica registered via
+// WithReplica, for test verification. Err is the replica's own PutObject outcome
+// (e.g. non-nil when that replica has ShouldFailPutObject set), never the primary's.
+type ReplicationCall struct {
+	Bucket string
+	Key    string
+	Err    error
+	Time   time.Time
 }
 
 // PutObjectCall represents a call to PutObject for test verification
@@ -58,14 +110,29 @@ type ObjectInfo struct {
 func NewMockMinioClient() *MockMinioClient {
 	return &MockMinioClient{
 		objects:          make(map[string][]byte),
+		encryption:       make(map[string]*EncryptionParams),
 		PutObjectCalls:   make([]PutObjectCall, 0),
 		GetObjectCalls:   make([]GetObjectCall, 0),
 		ListObjectsCalls: make([]ListObjectsCall, 0),
+		ReplicationCalls: make([]ReplicationCall, 0),
 	}
 }
 
-// PutObject stores an object in the mock storage
-func (m *MockMinioClient) PutObject(bucket, key string, reader io.Reader, size int64) error {
+// WithReplica registers other as a replication target: every future PutObject this
+// client accepts is also written to other once the primary write succeeds, with the
+// outcome recorded in ReplicationCalls regardless of whether it succeeded. Returns m
+// so it can be chained directly off NewMockMinioClient().
+func (m *MockMinioClient) WithReplica(other *MockMinioClient) *MockMinioClient {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.replicas = append(m.replicas, other)
+	return m
+}
+
+// PutObject stores an object in the mock storage. enc is nil for an unencrypted object,
+// or describes the SSE-C/SSE-KMS encryption GetObject must be given back to read it.
+func (m *MockMinioClient) PutObject(bucket, key string, reader io.Reader, size int64, enc *EncryptionParams) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -90,12 +157,29 @@ func (m *MockMinioClient) PutObject(bucket, key string, reader io.Reader, size i
 	// Store the object
 	objectKey := fmt.Sprintf("%s/%s", bucket, key)
 	m.objects[objectKey] = data
+	m.encryption[objectKey] = enc
+
+	// Fan out to every registered replica; a replica failure is recorded in
+	// ReplicationCalls but never returned, mirroring the real ReplicatingProvider
+	// where a degraded replica does not fail the backup.
+	for _, replica := range m.replicas {
+		replErr := replica.PutObject(bucket, key, bytes.NewReader(data), int64(len(data)), enc)
+		m.ReplicationCalls = append(m.ReplicationCalls, ReplicationCall{
+			Bucket: bucket,
+			Key:    key,
+			Err:    replErr,
+			Time:   time.Now(),
+		})
+	}
 
 	return nil
 }
 
-// GetObject retrieves an object from the mock storage
-func (m *MockMinioClient) GetObject(bucket, key string) (io.Reader, error) {
+// GetObject retrieves an object from the mock storage. enc must match whatever
+// EncryptionParams the object was written with (nil for an unencrypted object);
+// a mismatched or missing customer key/KMS key ID returns a descriptive error instead
+// of the object data, mirroring a real object store rejecting the request.
+func (m *MockMinioClient) GetObject(bucket, key string, enc *EncryptionParams) (io.Reader, error) {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
@@ -116,6 +200,17 @@ func (m *MockMinioClient) GetObject(bucket, key string) (io.Reader, error) {
 		return nil, fmt.Errorf("object not found: %s/%s", bucket, key)
 	}
 
+	written := m.encryption[objectKey]
+	if !enc.matches(written) {
+		if written.sseC() {
+			return nil, fmt.Errorf("object %s/%s is SSE-C encrypted: the customer key provided does not match the one used to encrypt it", bucket, key)
+		}
+		if written != nil {
+			return nil, fmt.Errorf("object %s/%s is SSE-KMS encrypted with key %q: wrong or missing KMS key ID provided", bucket, key, written.KMSKeyID)
+		}
+		return nil, fmt.Errorf("object %s/%s is not encrypted: unexpected encryption parameters provided", bucket, key)
+	}
+
 	return bytes.NewReader(data), nil
 }
 
@@ -191,6 +286,7 @@ func (m *MockMinioClient) Reset() {
 	m.PutObjectCalls = make([]PutObjectCall, 0)
 	m.GetObjectCalls = make([]GetObjectCall, 0)
 	m.ListObjectsCalls = make([]ListObjectsCall, 0)
+	m.ReplicationCalls = make([]ReplicationCall, 0)
 
 	m.ShouldFailPutObject = false
 	m.ShouldFailGetObject = false
@@ -291,13 +387,15 @@ func (m *MockMinioClient) SimulateGetObjectError() {
 	m.ShouldFailGetObject = true
 }
 
-// StoreTestBackup stores a test backup in the mock storage
-func (m *MockMinioClient) StoreTestBackup(bucket, backupName string, resources map[string][]byte) error {
+// StoreTestBackup stores a test backup in the mock storage. enc is nil for an
+// unencrypted backup, or the single EncryptionParams applied to every resource in it
+// (a whole Backup shares one StorageLocation.Encryption config, never a per-resource one).
+func (m *MockMinioClient) StoreTestBackup(bucket, backupName string, resources map[string][]byte, enc *EncryptionParams) error {
 	for resourceType, data := range resources {
 		key := fmt.Sprintf("backups/%s/%s.yaml", backupName, resourceType)
 		reader := bytes.NewReader(data)
 
-		err := m.PutObject(bucket, key, reader, int64(len(data)))
+		err := m.PutObject(bucket, key, reader, int64(len(data)), enc)
 		if err != nil {
 			return err
 		}
@@ -306,8 +404,10 @@ func (m *MockMinioClient) StoreTestBackup(bucket, backupName string, resources m
 	return nil
 }
 
-// GetTestBackup retrieves a test backup from the mock storage
-func (m *MockMinioClient) GetTestBackup(bucket, backupName string) (map[string][]byte, error) {
+// GetTestBackup retrieves a test backup from the mock storage, presenting enc for every
+// object read back; a nil or mismatched enc against a backup stored with StoreTestBackup's
+// enc fails with the same descriptive error GetObject returns.
+func (m *MockMinioClient) GetTestBackup(bucket, backupName string, enc *EncryptionParams) (map[string][]byte, error) {
 	prefix := fmt.Sprintf("backups/%s/", backupName)
 	objects, err := m.ListObjects(bucket, prefix)
 	if err != nil {
@@ -317,7 +417,7 @@ func (m *MockMinioClient) GetTestBackup(bucket, backupName string) (map[string][
 	resources := make(map[string][]byte)
 
 	for _, obj := range objects {
-		reader, err := m.GetObject(bucket, obj.Key)
+		reader, err := m.GetObject(bucket, obj.Key, enc)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,146 @@
+package mocks
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestPutObjectGetObjectRoundTripUnencrypted(t *testing.T) {
+	client := NewMockMinioClient()
+	data := []byte("plain data")
+
+	if err := client.PutObject("bucket", "key", bytes.NewReader(data), int64(len(data)), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reader, err := client.GetObject("bucket", "key", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, _ := io.ReadAll(reader)
+	if string(got) != string(data) {
+		t.Errorf("GetObject() = %q, want %q", got, data)
+	}
+}
+
+func TestGetObjectSSECWrongKeyFails(t *testing.T) {
+	client := NewMockMinioClient()
+	data := []byte("secret data")
+	writeKey := &EncryptionParams{Algorithm: "AES256", CustomerKey: []byte("0123456789abcdef0123456789abcdef")}
+
+	if err := client.PutObject("bucket", "key", bytes.NewReader(data), int64(len(data)), writeKey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := client.GetObject("bucket", "key", nil); err == nil {
+		t.Error("expected an error reading an SSE-C object with no key, got nil")
+	}
+
+	wrongKey := &EncryptionParams{Algorithm: "AES256", CustomerKey: []byte("ffffffffffffffffffffffffffffffff")}
+	if _, err := client.GetObject("bucket", "key", wrongKey); err == nil {
+		t.Error("expected an error reading an SSE-C object with the wrong key, got nil")
+	}
+
+	reader, err := client.GetObject("bucket", "key", writeKey)
+	if err != nil {
+		t.Fatalf("unexpected error reading with the correct key: %v", err)
+	}
+	got, _ := io.ReadAll(reader)
+	if string(got) != string(data) {
+		t.Errorf("GetObject() = %q, want %q", got, data)
+	}
+}
+
+func TestGetObjectSSEKMSWrongKeyIDFails(t *testing.T) {
+	client := NewMockMinioClient()
+	data := []byte("kms encrypted data")
+	writeKey := &EncryptionParams{KMSKeyID: "alias/backups"}
+
+	if err := client.PutObject("bucket", "key", bytes.NewReader(data), int64(len(data)), writeKey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := client.GetObject("bucket", "key", &EncryptionParams{KMSKeyID: "alias/other"}); err == nil {
+		t.Error("expected an error reading an SSE-KMS object with the wrong key ID, got nil")
+	}
+
+	if _, err := client.GetObject("bucket", "key", writeKey); err != nil {
+		t.Errorf("unexpected error reading with the correct KMS key ID: %v", err)
+	}
+}
+
+func TestPutObjectReplicatesToEveryReplica(t *testing.T) {
+	primary := NewMockMinioClient()
+	replicaA := NewMockMinioClient()
+	replicaB := NewMockMinioClient()
+	primary.WithReplica(replicaA).WithReplica(replicaB)
+
+	data := []byte("resource data")
+	if err := primary.PutObject("bucket", "key", bytes.NewReader(data), int64(len(data)), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, replica := range map[string]*MockMinioClient{"A": replicaA, "B": replicaB} {
+		if !replica.HasObject("bucket", "key") {
+			t.Errorf("expected replica %s to have received the object written to the primary", name)
+		}
+	}
+	if len(primary.ReplicationCalls) != 2 {
+		t.Errorf("expected 2 replication calls, got %d", len(primary.ReplicationCalls))
+	}
+}
+
+func TestPutObjectDegradedReplicaDoesNotFailPrimaryWrite(t *testing.T) {
+	primary := NewMockMinioClient()
+	healthy := NewMockMinioClient()
+	failing := NewMockMinioClient()
+	failing.SimulatePutObjectError()
+	primary.WithReplica(healthy).WithReplica(failing)
+
+	data := []byte("resource data")
+	if err := primary.PutObject("bucket", "key", bytes.NewReader(data), int64(len(data)), nil); err != nil {
+		t.Fatalf("expected the primary write to succeed despite a failing replica, got: %v", err)
+	}
+	if !primary.HasObject("bucket", "key") {
+		t.Error("expected the primary to store the object")
+	}
+	if !healthy.HasObject("bucket", "key") {
+		t.Error("expected the healthy replica to receive the object")
+	}
+	if failing.HasObject("bucket", "key") {
+		t.Error("expected the failing replica to not store the object")
+	}
+
+	var failureRecorded bool
+	for _, call := range primary.ReplicationCalls {
+		if call.Err != nil {
+			failureRecorded = true
+		}
+	}
+	if !failureRecorded {
+		t.Error("expected ReplicationCalls to record the failing replica's error")
+	}
+}
+
+func TestStoreAndGetTestBackupEncrypted(t *testing.T) {
+	client := NewMockMinioClient()
+	writeKey := &EncryptionParams{Algorithm: "AES256", CustomerKey: []byte("0123456789abcdef0123456789abcdef")}
+	resources := map[string][]byte{"secret": []byte("db-credentials")}
+
+	if err := client.StoreTestBackup("bucket", "my-backup", resources, writeKey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := client.GetTestBackup("bucket", "my-backup", nil); err == nil {
+		t.Error("expected an error restoring an encrypted backup with no key, got nil")
+	}
+
+	got, err := client.GetTestBackup("bucket", "my-backup", writeKey)
+	if err != nil {
+		t.Fatalf("unexpected error restoring with the correct key: %v", err)
+	}
+	if string(got["secret"]) != "db-credentials" {
+		t.Errorf("GetTestBackup() = %v, want secret=db-credentials", got)
+	}
+}
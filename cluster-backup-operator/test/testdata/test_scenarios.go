@@ -6,6 +6,8 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -345,7 +347,569 @@ func GetSecretsAndConfigMapsScenario() TestScenario {
 	}
 }
 
+// GetEncryptedSecretsBackupScenario returns a scenario backing up Secrets under SSE-C
+// encryption, restoring with the same KeyRef secret so the round trip succeeds.
+func GetEncryptedSecretsBackupScenario() TestScenario {
+	namespace := "test-encrypted-secrets"
+
+	storageLocation := backupv1.StorageLocation{
+		Provider: "minio",
+		Bucket:   "encrypted-bucket",
+		Encryption: &backupv1.EncryptionConfig{
+			Mode:   backupv1.EncryptionModeSSEC,
+			KeyRef: &backupv1.EncryptionKeyReference{Name: "backup-encryption-key", Namespace: namespace},
+		},
+	}
+
+	return TestScenario{
+		Name:        "Encrypted Secrets Backup",
+		Description: "Tests backup and restore of Secrets under SSE-C server-side encryption",
+		Resources: []TestResource{
+			{
+				Type:         "secret",
+				Object:       CreateTestSecret("db-credentials", namespace),
+				ShouldBackup: true,
+			},
+		},
+		Backup: &backupv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "encrypted-secrets-backup",
+				Namespace: namespace,
+			},
+			Spec: backupv1.BackupSpec{
+				Source: backupv1.BackupSource{
+					Namespace:     namespace,
+					ResourceTypes: []string{"secrets"},
+				},
+				Schedule:        "0 2 * * *",
+				StorageLocation: storageLocation,
+			},
+		},
+		Restore: &backupv1.Restore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "encrypted-secrets-restore",
+				Namespace: namespace,
+			},
+			Spec: backupv1.RestoreSpec{
+				Source: backupv1.RestoreSource{
+					BackupPath:      "backups/encrypted-secrets-backup",
+					StorageLocation: storageLocation,
+				},
+				Target: backupv1.RestoreTarget{
+					Namespaces: []string{namespace},
+				},
+			},
+		},
+		Expected: ExpectedOutcome{
+			BackupPhase:       backupv1.BackupPhaseScheduled,
+			RestorePhase:      backupv1.RestorePhaseCompleted,
+			ResourcesBackedUp: 1,
+			ResourcesRestored: 1,
+			ShouldFail:        false,
+		},
+	}
+}
+
+// GetWrongKeyRestoreFailureScenario mirrors GetEncryptedSecretsBackupScenario, but the
+// Restore's KeyRef names a different Secret than the one the backup was encrypted with,
+// so the SSE-C customer key presented on read doesn't match what was used on write.
+func GetWrongKeyRestoreFailureScenario() TestScenario {
+	namespace := "test-wrong-key-restore"
+
+	return TestScenario{
+		Name:        "Wrong-Key Restore Failure",
+		Description: "Tests that restoring an SSE-C encrypted backup with the wrong customer key fails",
+		Resources: []TestResource{
+			{
+				Type:         "secret",
+				Object:       CreateTestSecret("db-credentials", namespace),
+				ShouldBackup: true,
+			},
+		},
+		Backup: &backupv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "wrong-key-backup",
+				Namespace: namespace,
+			},
+			Spec: backupv1.BackupSpec{
+				Source: backupv1.BackupSource{
+					Namespace:     namespace,
+					ResourceTypes: []string{"secrets"},
+				},
+				Schedule: "0 2 * * *",
+				StorageLocation: backupv1.StorageLocation{
+					Provider: "minio",
+					Bucket:   "wrong-key-bucket",
+					Encryption: &backupv1.EncryptionConfig{
+						Mode:   backupv1.EncryptionModeSSEC,
+						KeyRef: &backupv1.EncryptionKeyReference{Name: "backup-encryption-key", Namespace: namespace},
+					},
+				},
+			},
+		},
+		Restore: &backupv1.Restore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "wrong-key-restore",
+				Namespace: namespace,
+			},
+			Spec: backupv1.RestoreSpec{
+				Source: backupv1.RestoreSource{
+					BackupPath: "backups/wrong-key-backup",
+					StorageLocation: backupv1.StorageLocation{
+						Provider: "minio",
+						Bucket:   "wrong-key-bucket",
+						Encryption: &backupv1.EncryptionConfig{
+							Mode:   backupv1.EncryptionModeSSEC,
+							KeyRef: &backupv1.EncryptionKeyReference{Name: "restore-encryption-key", Namespace: namespace},
+						},
+					},
+				},
+				Target: backupv1.RestoreTarget{
+					Namespaces: []string{namespace},
+				},
+			},
+		},
+		Expected: ExpectedOutcome{
+			BackupPhase:   backupv1.BackupPhaseScheduled,
+			RestorePhase:  backupv1.RestorePhaseFailed,
+			ShouldFail:    true,
+			ExpectedError: "customer key provided does not match the one used to encrypt it",
+		},
+	}
+}
+
+// GetCSISnapshotScenario returns a scenario covering CSI VolumeSnapshot backup of a
+// PVC: the backup requests SnapshotVolumes with a VolumeSnapshotClassMapping for the
+// PVC's StorageClass, and the restore remaps that class for the target cluster via
+// VolumeRestore.
+func GetCSISnapshotScenario() TestScenario {
+	namespace := "test-csi-snapshot"
+	storageClassName := "fast-ssd"
+
+	return TestScenario{
+		Name:        "CSI Volume Snapshot Backup",
+		Description: "Tests backup and restore of a PVC via CSI VolumeSnapshot",
+		Resources: []TestResource{
+			{
+				Type:         "storageclass",
+				Object:       CreateTestStorageClass(storageClassName),
+				ShouldBackup: false,
+			},
+			{
+				Type:         "pvc",
+				Object:       CreateTestPVC("app-data", namespace, storageClassName),
+				ShouldBackup: true,
+			},
+		},
+		Backup: &backupv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "csi-snapshot-backup",
+				Namespace: namespace,
+			},
+			Spec: backupv1.BackupSpec{
+				Source: backupv1.BackupSource{
+					Namespace:                  namespace,
+					ResourceTypes:              []string{"persistentvolumeclaims"},
+					SnapshotVolumes:            true,
+					VolumeSnapshotClassMapping: map[string]string{storageClassName: "fast-ssd-snapclass"},
+				},
+				Schedule: "0 2 * * *",
+				StorageLocation: backupv1.StorageLocation{
+					Provider: "minio",
+					Bucket:   "csi-snapshot-bucket",
+				},
+			},
+		},
+		Restore: &backupv1.Restore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "csi-snapshot-restore",
+				Namespace: namespace,
+			},
+			Spec: backupv1.RestoreSpec{
+				Source: backupv1.RestoreSource{
+					BackupPath: "backups/csi-snapshot-backup",
+					StorageLocation: backupv1.StorageLocation{
+						Provider: "minio",
+						Bucket:   "csi-snapshot-bucket",
+					},
+				},
+				Target: backupv1.RestoreTarget{
+					Namespaces: []string{namespace},
+				},
+				VolumeRestore: &backupv1.VolumeRestoreOptions{
+					RestorePVs:                 boolPtr(true),
+					VolumeSnapshotClassMapping: map[string]string{"fast-ssd-snapclass": "fast-ssd-snapclass"},
+				},
+			},
+		},
+		Expected: ExpectedOutcome{
+			BackupPhase:       backupv1.BackupPhaseScheduled,
+			RestorePhase:      backupv1.RestorePhaseCompleted,
+			ResourcesBackedUp: 1,
+			ResourcesRestored: 1,
+			ShouldFail:        false,
+		},
+	}
+}
+
 // GetFrequentBackupScenario returns a scenario with frequent backup schedule
+// GetReplicatedBackupScenario returns a scenario covering StorageLocation.ReplicaTargets:
+// every object written to the primary bucket is fanned out to a second replica bucket,
+// with per-target sync status recorded on BackupStatus.Replicas. The scenario's Expected
+// outcome covers the healthy case (both replicas synced); a degraded replica (one target
+// failing its writes while the backup still succeeds) is exercised directly against
+// test/mocks.MockMinioClient rather than through this scenario, since ExpectedOutcome has
+// no per-replica-phase field to assert against.
+func GetReplicatedBackupScenario() TestScenario {
+	namespace := "test-replicated"
+
+	return TestScenario{
+		Name:        "Replicated Backup",
+		Description: "Tests backup fan-out to a second StorageLocation via ReplicaTargets",
+		Resources: []TestResource{
+			{
+				Type:         "configmap",
+				Object:       CreateTestConfigMap("app-config", namespace),
+				ShouldBackup: true,
+			},
+		},
+		Backup: &backupv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "replicated-backup",
+				Namespace: namespace,
+			},
+			Spec: backupv1.BackupSpec{
+				Source: backupv1.BackupSource{
+					Namespace:     namespace,
+					ResourceTypes: []string{"configmaps"},
+				},
+				Schedule: "0 2 * * *",
+				StorageLocation: backupv1.StorageLocation{
+					Provider: "minio",
+					Bucket:   "primary-bucket",
+					ReplicaTargets: []backupv1.ReplicaTarget{
+						{
+							Name: "secondary-site",
+							StorageLocation: backupv1.StorageLocation{
+								Provider: "minio",
+								Bucket:   "secondary-bucket",
+								Endpoint: "secondary.minio.svc:9000",
+							},
+						},
+					},
+				},
+			},
+		},
+		Expected: ExpectedOutcome{
+			BackupPhase:       backupv1.BackupPhaseScheduled,
+			ResourcesBackedUp: 1,
+			ShouldFail:        false,
+		},
+	}
+}
+
+// GetDeduplicatedBackupScenario returns a scenario for BackupSource.Deduplicate,
+// where every resource is written as a content-addressed chunk under the bucket-wide
+// chunks/ prefix instead of one object per resource under the run's own path. The
+// space savings this yields across repeated runs of the same namespace (only a
+// changed resource's new content produces a new chunk; everything else reuses a
+// chunk an earlier run already wrote) is exercised directly against dedupState in
+// TestDedupStateBlobCountGrowsByOneForChangedResource, since TestScenario only
+// models a single backup run rather than the two-run sequence that comparison needs.
+func GetDeduplicatedBackupScenario() TestScenario {
+	namespace := "test-dedup"
+
+	return TestScenario{
+		Name:        "Deduplicated Backup",
+		Description: "Tests backup with Deduplicate storing resources as content-addressed chunks",
+		Resources: []TestResource{
+			{
+				Type:         "configmap",
+				Object:       CreateTestConfigMap("app-config", namespace),
+				ShouldBackup: true,
+			},
+		},
+		Backup: &backupv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "dedup-backup",
+				Namespace: namespace,
+			},
+			Spec: backupv1.BackupSpec{
+				Source: backupv1.BackupSource{
+					Namespace:     namespace,
+					ResourceTypes: []string{"configmaps"},
+					Deduplicate:   true,
+				},
+				Schedule: "0 2 * * *",
+				StorageLocation: backupv1.StorageLocation{
+					Provider: "minio",
+					Bucket:   "dedup-test-bucket",
+				},
+			},
+		},
+		Expected: ExpectedOutcome{
+			BackupPhase:       backupv1.BackupPhaseScheduled,
+			ResourcesBackedUp: 1,
+			ShouldFail:        false,
+		},
+	}
+}
+
+// GetMultiProviderScenario returns a scenario asserting the same backup spec, run
+// against a "local" StorageLocation instead of the usual "minio" one, backs up the
+// same resources the same way - the byte-identical-object guarantee this depends on
+// is exercised directly in pkg/storage's
+// TestSameBackupAgainstTwoProvidersProducesByteIdenticalManifests, since TestScenario
+// models one Backup against one StorageLocation rather than running the same backup
+// twice.
+func GetMultiProviderScenario() TestScenario {
+	namespace := "test-multi-provider"
+
+	return TestScenario{
+		Name:        "Multi Provider Scenario",
+		Description: "Tests backup against a local filesystem StorageLocation instead of minio",
+		Resources: []TestResource{
+			{
+				Type:         "configmap",
+				Object:       CreateTestConfigMap("app-config", namespace),
+				ShouldBackup: true,
+			},
+		},
+		Backup: &backupv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "multi-provider-backup",
+				Namespace: namespace,
+			},
+			Spec: backupv1.BackupSpec{
+				Source: backupv1.BackupSource{
+					Namespace:     namespace,
+					ResourceTypes: []string{"configmaps"},
+				},
+				Schedule: "0 2 * * *",
+				StorageLocation: backupv1.StorageLocation{
+					Provider: "local",
+					Bucket:   "multi-provider-bucket",
+					Endpoint: "/var/lib/cluster-backup-operator",
+				},
+			},
+		},
+		Expected: ExpectedOutcome{
+			BackupPhase:       backupv1.BackupPhaseScheduled,
+			ResourcesBackedUp: 1,
+			ShouldFail:        false,
+		},
+	}
+}
+
+// GetOrLabelSelectorScenario returns a scenario for BackupSource.OrLabelSelectors,
+// where a resource matching any one of several selectors should be backed up even
+// though no single selector (the old LabelSelector-only behavior) matches it.
+func GetOrLabelSelectorScenario() TestScenario {
+	namespace := "test-or-labels"
+
+	return TestScenario{
+		Name:        "OR Label Selector Scenario",
+		Description: "Tests backup with OrLabelSelectors matching either of two teams",
+		Resources: []TestResource{
+			{
+				Type:         "deployment",
+				Object:       CreateTestDeploymentWithLabels("team-a-app", namespace, map[string]string{"team": "a"}),
+				ShouldBackup: true,
+			},
+			{
+				Type:         "deployment",
+				Object:       CreateTestDeploymentWithLabels("team-b-app", namespace, map[string]string{"team": "b"}),
+				ShouldBackup: true,
+			},
+			{
+				Type:         "deployment",
+				Object:       CreateTestDeploymentWithLabels("team-c-app", namespace, map[string]string{"team": "c"}),
+				ShouldBackup: false,
+			},
+		},
+		Backup: &backupv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "or-label-backup",
+				Namespace: namespace,
+			},
+			Spec: backupv1.BackupSpec{
+				Source: backupv1.BackupSource{
+					Namespace: namespace,
+					OrLabelSelectors: []metav1.LabelSelector{
+						{MatchLabels: map[string]string{"team": "a"}},
+						{MatchLabels: map[string]string{"team": "b"}},
+					},
+				},
+				Schedule: "0 2 * * *",
+				StorageLocation: backupv1.StorageLocation{
+					Provider: "minio",
+					Bucket:   "or-label-test-bucket",
+				},
+			},
+		},
+		Expected: ExpectedOutcome{
+			BackupPhase:       backupv1.BackupPhaseScheduled,
+			ResourcesBackedUp: 2, // Only team=a and team=b deployments
+			ShouldFail:        false,
+		},
+	}
+}
+
+// GetNamespaceGlobExcludeScenario returns a scenario for BackupSource.ExcludeNamespaces
+// glob matching, excluding every "kube-*" namespace while backing up all others.
+func GetNamespaceGlobExcludeScenario() TestScenario {
+	return TestScenario{
+		Name:        "Namespace Glob Exclude Scenario",
+		Description: "Tests backup excluding namespaces matching a kube-* glob",
+		Resources: []TestResource{
+			{
+				Type:         "configmap",
+				Object:       CreateTestConfigMap("app-config", "dev-app"),
+				ShouldBackup: true,
+			},
+			{
+				Type:         "configmap",
+				Object:       CreateTestConfigMap("system-config", "kube-system"),
+				ShouldBackup: false,
+			},
+			{
+				Type:         "configmap",
+				Object:       CreateTestConfigMap("public-config", "kube-public"),
+				ShouldBackup: false,
+			},
+		},
+		Backup: &backupv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "namespace-glob-exclude-backup",
+				Namespace: "dev-app",
+			},
+			Spec: backupv1.BackupSpec{
+				Source: backupv1.BackupSource{
+					Namespaces:        []string{"*"},
+					ExcludeNamespaces: []string{"kube-*"},
+					ResourceTypes:     []string{"configmaps"},
+				},
+				Schedule: "0 2 * * *",
+				StorageLocation: backupv1.StorageLocation{
+					Provider: "minio",
+					Bucket:   "namespace-glob-test-bucket",
+				},
+			},
+		},
+		Expected: ExpectedOutcome{
+			BackupPhase:       backupv1.BackupPhaseScheduled,
+			ResourcesBackedUp: 1, // Only dev-app's configmap
+			ShouldFail:        false,
+		},
+	}
+}
+
+// GetClusterScopedFilterScenario returns a scenario for BackupSource.ExcludedResources
+// applied to cluster-scoped resources, excluding one named ClusterRole by "type/name"
+// while still backing up the rest of that type.
+func GetClusterScopedFilterScenario() TestScenario {
+	return TestScenario{
+		Name:        "Cluster Scoped Filter Scenario",
+		Description: "Tests ExcludedResources skipping a single named cluster-scoped resource",
+		Resources: []TestResource{
+			{
+				Type:         "clusterrole",
+				Object:       CreateTestClusterRole("backup-reader"),
+				ShouldBackup: true,
+			},
+			{
+				Type:         "clusterrole",
+				Object:       CreateTestClusterRole("cluster-admin-role"),
+				ShouldBackup: false,
+			},
+		},
+		Backup: &backupv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-scoped-filter-backup",
+				Namespace: "default",
+			},
+			Spec: backupv1.BackupSpec{
+				Source: backupv1.BackupSource{
+					Namespace:               "default",
+					IncludeClusterResources: true,
+					ExcludedResources:       []string{"clusterroles/cluster-admin-role"},
+				},
+				Schedule: "0 2 * * *",
+				StorageLocation: backupv1.StorageLocation{
+					Provider: "minio",
+					Bucket:   "cluster-scoped-filter-test-bucket",
+				},
+			},
+		},
+		Expected: ExpectedOutcome{
+			BackupPhase:       backupv1.BackupPhaseScheduled,
+			ResourcesBackedUp: 1, // Only backup-reader; cluster-admin-role is excluded
+			ShouldFail:        false,
+		},
+	}
+}
+
+// GetClusterScopedRestoreScenario returns a scenario for RestoreTarget.IncludeClusterResources:
+// a backup with IncludeClusterResources writes a ClusterRole under its "cluster/" path,
+// and a restore with the matching flag set restores it back.
+func GetClusterScopedRestoreScenario() TestScenario {
+	namespace := "cluster-scoped-restore-ns"
+
+	return TestScenario{
+		Name:        "Cluster Scoped Restore Scenario",
+		Description: "Tests RestoreTarget.IncludeClusterResources restoring a cluster-scoped ClusterRole",
+		Resources: []TestResource{
+			{
+				Type:         "clusterrole",
+				Object:       CreateTestClusterRole("restore-reader"),
+				ShouldBackup: true,
+			},
+		},
+		Backup: &backupv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-scoped-restore-backup",
+				Namespace: namespace,
+			},
+			Spec: backupv1.BackupSpec{
+				Source: backupv1.BackupSource{
+					Namespace:               namespace,
+					IncludeClusterResources: true,
+				},
+				Schedule: "0 2 * * *",
+				StorageLocation: backupv1.StorageLocation{
+					Provider: "minio",
+					Bucket:   "cluster-scoped-restore-bucket",
+				},
+			},
+		},
+		Restore: &backupv1.Restore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-scoped-restore",
+				Namespace: namespace,
+			},
+			Spec: backupv1.RestoreSpec{
+				Source: backupv1.RestoreSource{
+					BackupPath: "backups/cluster-scoped-restore-backup",
+					StorageLocation: backupv1.StorageLocation{
+						Provider: "minio",
+						Bucket:   "cluster-scoped-restore-bucket",
+					},
+				},
+				Target: backupv1.RestoreTarget{
+					IncludeClusterResources: true,
+				},
+			},
+		},
+		Expected: ExpectedOutcome{
+			BackupPhase:       backupv1.BackupPhaseScheduled,
+			RestorePhase:      backupv1.RestorePhaseCompleted,
+			ResourcesBackedUp: 1,
+			ResourcesRestored: 1,
+			ShouldFail:        false,
+		},
+	}
+}
+
 func GetFrequentBackupScenario() TestScenario {
 	namespace := "test-frequent"
 
@@ -480,6 +1044,22 @@ timeout=30s
 	}
 }
 
+// CreateTestClusterRole returns a minimal cluster-scoped ClusterRole for testing.
+func CreateTestClusterRole(name string) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     []string{"get", "list"},
+			},
+		},
+	}
+}
+
 func CreateTestConfigMapWithBinaryData(name, namespace string) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -555,10 +1135,41 @@ func CreateTestIngress(name, namespace string) *networkingv1.Ingress {
 	}
 }
 
+func CreateTestPVC(name, namespace, storageClassName string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: &storageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	}
+}
+
+func CreateTestStorageClass(name string) *storagev1.StorageClass {
+	return &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Provisioner: "ebs.csi.aws.com",
+	}
+}
+
 func int32Ptr(i int32) *int32 {
 	return &i
 }
 
+func boolPtr(b bool) *bool {
+	return &b
+}
+
 // GetAllTestScenarios returns all available test scenarios
 func GetAllTestScenarios() []TestScenario {
 	return []TestScenario{
@@ -568,6 +1179,16 @@ func GetAllTestScenarios() []TestScenario {
 		GetInvalidScheduleScenario(),
 		GetLargeResourceScenario(),
 		GetSecretsAndConfigMapsScenario(),
+		GetEncryptedSecretsBackupScenario(),
+		GetWrongKeyRestoreFailureScenario(),
+		GetCSISnapshotScenario(),
+		GetReplicatedBackupScenario(),
+		GetDeduplicatedBackupScenario(),
+		GetMultiProviderScenario(),
+		GetOrLabelSelectorScenario(),
+		GetNamespaceGlobExcludeScenario(),
+		GetClusterScopedFilterScenario(),
+		GetClusterScopedRestoreScenario(),
 		GetFrequentBackupScenario(),
 	}
 }
@@ -0,0 +1,50 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	backupv1 "github.com/rfashwall/cnd-workshop/api/v1"
+	"github.com/rfashwall/cnd-workshop/internal/controller"
+)
+
+func TestBackupScheduleControllerStructure(t *testing.T) {
+	reconciler := &controller.BackupScheduleReconciler{}
+	assert.NotNil(t, reconciler, "BackupScheduleReconciler should be instantiable")
+}
+
+func TestBackupScheduleResourceCreation(t *testing.T) {
+	schedule := &backupv1.BackupSchedule{
+		Spec: backupv1.BackupScheduleSpec{
+			Schedule:             "0 2 * * *",
+			MaxSuccessfulBackups: 3,
+			MaxFailedBackups:     1,
+			Template: backupv1.BackupSpec{
+				Source: backupv1.BackupSource{
+					Namespace: "test-namespace",
+				},
+				StorageLocation: backupv1.StorageLocation{
+					Provider: "minio",
+					Bucket:   "test-bucket",
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, "0 2 * * *", schedule.Spec.Schedule)
+	assert.False(t, schedule.Spec.Pause)
+	assert.Equal(t, int32(3), schedule.Spec.MaxSuccessfulBackups)
+	assert.Equal(t, "test-namespace", schedule.Spec.Template.Source.Namespace)
+}
+
+func TestBackupSchedulePause(t *testing.T) {
+	schedule := &backupv1.BackupSchedule{
+		Spec: backupv1.BackupScheduleSpec{
+			Schedule: "0 2 * * *",
+			Pause:    true,
+		},
+	}
+
+	assert.True(t, schedule.Spec.Pause, "paused schedule should not create new Backups")
+}